@@ -10,7 +10,12 @@ const (
 	ListSizeMedium = 20 // For neighbour lists, etc.
 	ListSizeLarge  = 64 // For atom and bond lists, etc.
 
-	MaxBonds    = 20            // Maximum number of bonds an atom can have.
-	MaxRings    = ListSizeSmall // Maximum number of rings an atom can be a part of.
+	// The bonds and rings of an atom are held in bit sets that grow as
+	// needed; `MaxBonds` and `MaxRings` merely size them initially.
+	// `MaxBonds` also bounds the bonds perceived from coordinates.
+	MaxBonds    = 20            // Typical maximum number of bonds an atom has.
+	MaxRings    = ListSizeSmall // Typical maximum number of rings an atom is a part of.
 	MaxFeatures = ListSizeSmall // Maximum number functional groups on an atom.
+
+	MaxRingCount = 255 // Maximum number of rings in a molecule; ring IDs are `uint8`.
 )
@@ -2,6 +2,7 @@ package common
 
 import (
 	"fmt"
+	"math"
 )
 
 // Element holds the essential chemical information of a given natural
@@ -13,12 +14,143 @@ type Element struct {
 	Weight         float64 // Atomic weight of the most abundant isotope
 	Valence        int8    // Default valence
 	OxStates       []int8  // Other oxidation states
-	ElecNegativity float64 // Electronegativity of the default oxidation state
+	ElecNegativity float64 // Pauling electronegativity of the default oxidation state; math.MaxFloat64 if unknown
+	CovalentRadius float64 // Single-bond covalent radius, in Angstroms; 0 if unknown
+	VdwRadius      float64 // Van der Waals radius, in Angstroms; 0 if unknown
 }
 
 // String answers a representation of the element that is easily
 // readable.
 func (e *Element) String() string {
-	return fmt.Sprintf("%s : {Number: %d, Symbol: %s, Weight: %.4f, Valence: %d, Oxidation States: %v, Electronegativity: %.4f}\n",
-		e.Name, e.Number, e.Symbol, e.Weight, e.Valence, e.OxStates, e.ElecNegativity)
+	return fmt.Sprintf("%s : {Number: %d, Symbol: %s, Weight: %.4f, Valence: %d, Oxidation States: %v, Electronegativity: %.4f, Covalent Radius: %.2f, Van der Waals Radius: %.2f}\n",
+		e.Name, e.Number, e.Symbol, e.Weight, e.Valence, e.OxStates, e.ElecNegativity, e.CovalentRadius, e.VdwRadius)
+}
+
+// CovalentBondLength answers the expected length, in Angstroms, of a
+// single bond between atoms of the elements with the given atomic
+// numbers: the sum of their covalent radii.  Answers `0` if the radius
+// of either element is not known.
+func CovalentBondLength(a, b uint8) float64 {
+	if int(a) >= len(ElementSymbols) || int(b) >= len(ElementSymbols) {
+		return 0
+	}
+
+	ra := PeriodicTable[ElementSymbols[a]].CovalentRadius
+	rb := PeriodicTable[ElementSymbols[b]].CovalentRadius
+	if ra == 0 || rb == 0 {
+		return 0
+	}
+	return ra + rb
+}
+
+// ElecNegativity answers the Pauling electronegativity of the
+// element with the given atomic number.  Answers `0` if it is not
+// known, as it is not for the noble gases and several of the heavier
+// elements.
+func ElecNegativity(atNum uint8) float64 {
+	if int(atNum) >= len(ElementSymbols) {
+		return 0
+	}
+
+	en := PeriodicTable[ElementSymbols[atNum]].ElecNegativity
+	if en == math.MaxFloat64 {
+		return 0
+	}
+	return en
+}
+
+// PeriodOf answers the period - the row of the periodic table - of the
+// element with the given atomic number.  Answers `0` for an unknown
+// element.
+func PeriodOf(atNum uint8) int {
+	switch {
+	case atNum == 0:
+		return 0
+	case atNum <= 2:
+		return 1
+	case atNum <= 10:
+		return 2
+	case atNum <= 18:
+		return 3
+	case atNum <= 36:
+		return 4
+	case atNum <= 54:
+		return 5
+	case atNum <= 86:
+		return 6
+	case atNum <= 118:
+		return 7
+	}
+
+	return 0
+}
+
+// periodStarts holds the atomic number of the first element of each
+// period, indexed by the period.
+var periodStarts = [...]uint8{0, 1, 3, 11, 19, 37, 55, 87}
+
+// GroupOf answers the IUPAC group - the column of the periodic table,
+// from 1 to 18 - of the element with the given atomic number.
+//
+// Lanthanum and actinium are placed in group 3.  The other lanthanides
+// and actinides belong to no group; `0` is answered for them, as it is
+// for an unknown element.
+func GroupOf(atNum uint8) int {
+	p := PeriodOf(atNum)
+	if p == 0 {
+		return 0
+	}
+
+	pos := int(atNum-periodStarts[p]) + 1
+	switch p {
+	case 1:
+		if pos == 1 {
+			return 1
+		}
+		return 18
+	case 2, 3:
+		if pos <= 2 {
+			return pos
+		}
+		return pos + 10
+	case 4, 5:
+		return pos
+	}
+
+	// Periods 6 and 7 include the f-block.
+	switch {
+	case pos <= 3:
+		return pos
+	case pos <= 17:
+		return 0
+	}
+	return pos - 14
+}
+
+// IsHalogen answers if the element with the given atomic number is a
+// halogen: one of fluorine, chlorine, bromine, iodine or astatine.
+func IsHalogen(atNum uint8) bool {
+	return GroupOf(atNum) == 17 && atNum <= 85
+}
+
+// IsChalcogen answers if the element with the given atomic number is a
+// chalcogen: one of oxygen, sulfur, selenium, tellurium or polonium.
+func IsChalcogen(atNum uint8) bool {
+	return GroupOf(atNum) == 16 && atNum <= 84
+}
+
+// IsMetal answers if the element with the given atomic number is a
+// metal.  The metalloids - boron, silicon, germanium, arsenic,
+// antimony, tellurium and astatine - are not metals.
+//
+// All the elements from francium onwards are treated as metals, but
+// for those in groups 17 and 18.
+func IsMetal(atNum uint8) bool {
+	switch atNum {
+	case 0, 1, 2, 5, 6, 7, 8, 9, 10, 14, 15, 16, 17, 18, 32, 33, 34, 35, 36, 51, 52, 53, 54, 85, 86:
+		return false
+	}
+
+	g := GroupOf(atNum)
+	return PeriodOf(atNum) > 0 && g != 17 && g != 18
 }
@@ -0,0 +1,114 @@
+package common
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPeriodAndGroup(t *testing.T) {
+	cases := []struct {
+		sym           string
+		period, group int
+	}{
+		{"H", 1, 1}, {"He", 1, 18},
+		{"Li", 2, 1}, {"C", 2, 14}, {"F", 2, 17}, {"Ne", 2, 18},
+		{"Na", 3, 1}, {"Mg", 3, 2}, {"Al", 3, 13}, {"Cl", 3, 17},
+		{"K", 4, 1}, {"Sc", 4, 3}, {"Fe", 4, 8}, {"Zn", 4, 12}, {"Br", 4, 17},
+		{"Ag", 5, 11}, {"I", 5, 17}, {"Xe", 5, 18},
+		{"Cs", 6, 1}, {"La", 6, 3}, {"Ce", 6, 0}, {"Lu", 6, 0}, {"Hf", 6, 4}, {"Pt", 6, 10}, {"At", 6, 17}, {"Rn", 6, 18},
+		{"Fr", 7, 1}, {"Ac", 7, 3}, {"U", 7, 0}, {"Rf", 7, 4},
+	}
+	for _, c := range cases {
+		n := PeriodicTable[c.sym].Number
+		if p := PeriodOf(n); p != c.period {
+			t.Errorf("%s : period %d; want %d", c.sym, p, c.period)
+		}
+		if g := GroupOf(n); g != c.group {
+			t.Errorf("%s : group %d; want %d", c.sym, g, c.group)
+		}
+	}
+
+	if PeriodOf(0) != 0 || GroupOf(0) != 0 || PeriodOf(119) != 0 || GroupOf(119) != 0 {
+		t.Error("unknown elements have a period or a group")
+	}
+
+	// Each period has two elements in the s-block, and each of the
+	// later periods has six in the p-block.
+	blocks := make(map[[2]int]int)
+	for n := 1; n <= 118; n++ {
+		g := GroupOf(uint8(n))
+		if g < 0 || g > 18 {
+			t.Errorf("element %d : group %d", n, g)
+		}
+		if g == 1 || g == 2 {
+			blocks[[2]int{PeriodOf(uint8(n)), 0}]++
+		}
+		if g >= 13 {
+			blocks[[2]int{PeriodOf(uint8(n)), 1}]++
+		}
+	}
+	for p := 2; p <= 7; p++ {
+		if blocks[[2]int{p, 0}] != 2 || blocks[[2]int{p, 1}] != 6 {
+			t.Errorf("period %d : %d elements in groups 1 and 2, %d in groups 13 to 18; want 2 and 6",
+				p, blocks[[2]int{p, 0}], blocks[[2]int{p, 1}])
+		}
+	}
+}
+
+func TestHalogensAndMetals(t *testing.T) {
+	halogens := map[string]bool{"F": true, "Cl": true, "Br": true, "I": true, "At": true}
+	nonMetals := map[string]bool{
+		"H": true, "He": true, "B": true, "C": true, "N": true, "O": true, "F": true, "Ne": true,
+		"Si": true, "P": true, "S": true, "Cl": true, "Ar": true,
+		"Ge": true, "As": true, "Se": true, "Br": true, "Kr": true,
+		"Sb": true, "Te": true, "I": true, "Xe": true,
+		"At": true, "Rn": true, "Uus": true, "Uuo": true,
+	}
+
+	for n := 1; n <= 118; n++ {
+		sym := ElementSymbols[n]
+		if IsHalogen(uint8(n)) != halogens[sym] {
+			t.Errorf("%s : halogen %v; want %v", sym, IsHalogen(uint8(n)), halogens[sym])
+		}
+		if IsMetal(uint8(n)) == nonMetals[sym] {
+			t.Errorf("%s : metal %v; want %v", sym, IsMetal(uint8(n)), !nonMetals[sym])
+		}
+	}
+
+	if IsHalogen(0) || IsMetal(0) {
+		t.Error("unknown element is a halogen or a metal")
+	}
+}
+
+func TestCovalentBondLength(t *testing.T) {
+	// Reference lengths of single bonds, in Angstroms, as in ethane,
+	// methanol and methylamine.
+	cases := []struct {
+		a, b   string
+		length float64
+	}{
+		{"C", "C", 1.54},
+		{"C", "O", 1.43},
+		{"C", "N", 1.47},
+	}
+	for _, c := range cases {
+		l := CovalentBondLength(PeriodicTable[c.a].Number, PeriodicTable[c.b].Number)
+		if math.Abs(l-c.length) > 0.05 {
+			t.Errorf("%s-%s : length %.2f; want %.2f", c.a, c.b, l, c.length)
+		}
+	}
+
+	if l := CovalentBondLength(6, 200); l != 0 {
+		t.Errorf("C and an unknown element : length %.2f; want 0", l)
+	}
+}
+
+func TestElecNegativity(t *testing.T) {
+	if en := ElecNegativity(PeriodicTable["O"].Number); en != PeriodicTable["O"].ElecNegativity {
+		t.Errorf("O : electronegativity %.2f; want %.2f", en, PeriodicTable["O"].ElecNegativity)
+	}
+	// That of neon is not known.
+	if en := ElecNegativity(PeriodicTable["Ne"].Number); en != 0 {
+		t.Errorf("Ne : electronegativity %.2f; want 0", en)
+	}
+}
@@ -25,6 +25,7 @@ const (
 	BondTypeDouble
 	BondTypeTriple
 	BondTypeAltern // InChI says 'avoid by all means'!
+	BondTypeDative // Coordinate bond; the first atom donates the pair.
 )
 
 // BondStereo defines the possible stereo orientations of a given
@@ -82,3 +83,207 @@ const (
 	UnsaturationTripleBondW
 	UnsaturationCharged
 )
+
+// unsaturationNames holds the names of the unsaturation states, in
+// the order of their values.
+var unsaturationNames = [...]string{
+	"None",
+	"Aromatic",
+	"DoubleBondC",
+	"DoubleBondW",
+	"DoubleBondCC",
+	"DoubleBondCW",
+	"DoubleBondWW",
+	"TripleBondC",
+	"TripleBondW",
+	"Charged",
+}
+
+// String answers the name of this unsaturation state.
+func (u Unsaturation) String() string {
+	if int(u) < len(unsaturationNames) {
+		return unsaturationNames[u]
+	}
+
+	return "Unknown"
+}
+
+// AromaticityModel selects the set of rules used for determining the
+// aromaticity of rings and ring systems.
+type AromaticityModel uint8
+
+const (
+	// Hückel's rule over ring systems and rings, with RxnWeaver's
+	// pi-electron contributions.
+	AromaticityModelDefault AromaticityModel = iota
+	// As the default model, but exocyclic double bonds to carbon
+	// disqualify a ring, and semi-aromatic 6-membered rings - such
+	// as those of pyridones - are aromatic.
+	AromaticityModelDaylight
+	// Only rings of alternating single and double bonds, each
+	// considered individually, are aromatic.  Lone pairs do not
+	// contribute.
+	AromaticityModelMDL
+)
+
+// AmideClass classifies an amide nitrogen by the number of hydrogen
+// atoms attached to it.
+type AmideClass uint8
+
+const (
+	AmideClassNone      AmideClass = iota
+	AmideClassPrimary              // -C(=O)NH2
+	AmideClassSecondary            // -C(=O)NHR
+	AmideClassTertiary             // -C(=O)NR2
+)
+
+// Hybridization describes the hybridization of the valence orbitals of
+// an atom.
+type Hybridization uint8
+
+const (
+	HybridizationNone Hybridization = iota // Unknown, or not applicable.
+	HybridizationSp
+	HybridizationSp2
+	HybridizationSp3
+)
+
+// hybridizationNames holds the names of the hybridization states, in
+// the order of their values.
+var hybridizationNames = [...]string{
+	"None",
+	"sp",
+	"sp2",
+	"sp3",
+}
+
+// String answers the name of this hybridization state.
+func (h Hybridization) String() string {
+	if int(h) < len(hybridizationNames) {
+		return hybridizationNames[h]
+	}
+
+	return "Unknown"
+}
+
+// Severity grades a problem found when validating a molecule.
+type Severity uint8
+
+const (
+	SeverityWarning Severity = iota // Suspicious, but usable.
+	SeverityError                   // Invalid; results are unreliable.
+)
+
+// severityNames holds the names of the severities, in the order of
+// their values.
+var severityNames = [...]string{
+	"warning",
+	"error",
+}
+
+// String answers the name of this severity.
+func (s Severity) String() string {
+	if int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+
+	return "unknown"
+}
+
+// CIPLabel is the Cahn-Ingold-Prelog descriptor of a tetrahedral
+// stereo centre.
+type CIPLabel uint8
+
+const (
+	CIPLabelNone CIPLabel = iota // Not a stereo centre, or not determined.
+	CIPLabelR                    // Rectus.
+	CIPLabelS                    // Sinister.
+)
+
+// cipLabelNames holds the names of the CIP labels, in the order of
+// their values.
+var cipLabelNames = [...]string{
+	"",
+	"R",
+	"S",
+}
+
+// String answers the name of this CIP label.
+func (l CIPLabel) String() string {
+	if int(l) < len(cipLabelNames) {
+		return cipLabelNames[l]
+	}
+
+	return "?"
+}
+
+// HydrogenPolicy selects how the hydrogen counts of atoms are
+// determined, when reading formats that may omit hydrogen atoms.
+//
+// The default - the zero value - is `HydrogenPolicyFill`, since MDL
+// formats leave hydrogen atoms implicit, expecting readers to infer
+// them from valences.
+type HydrogenPolicy uint8
+
+const (
+	// Hydrogen atoms are added to bring each atom to the default
+	// valence of its element, as adjusted by its charge.  Atoms
+	// already beyond it are brought to the next valence their element
+	// permits, as in hypervalent sulphur or phosphorus.
+	HydrogenPolicyFill HydrogenPolicy = iota
+	// As `HydrogenPolicyFill`, but atoms at or beyond the default
+	// valence gain no hydrogen atoms.
+	HydrogenPolicyFillCapped
+	// Only hydrogen atoms given explicitly are counted.
+	HydrogenPolicyExplicit
+)
+
+// hydrogenPolicyNames holds the names of the hydrogen policies, in the
+// order of their values.
+var hydrogenPolicyNames = [...]string{
+	"fill",
+	"fill-capped",
+	"explicit",
+}
+
+// String answers the name of this hydrogen policy.
+func (p HydrogenPolicy) String() string {
+	if int(p) < len(hydrogenPolicyNames) {
+		return hydrogenPolicyNames[p]
+	}
+
+	return "unknown"
+}
+
+// ComponentPolicy selects how molecule builders treat input that is
+// not a single connected component, such as a salt or a mixture.
+//
+// The default - the zero value - is `ComponentPolicyKeep`, which
+// builds a single molecule regardless.
+type ComponentPolicy uint8
+
+const (
+	// All the components are kept in a single molecule.
+	ComponentPolicyKeep ComponentPolicy = iota
+	// Disconnected input is rejected with an error.
+	ComponentPolicyReject
+	// Each component is built as a molecule of its own.
+	ComponentPolicySplit
+)
+
+// componentPolicyNames holds the names of the component policies, in
+// the order of their values.
+var componentPolicyNames = [...]string{
+	"keep",
+	"reject",
+	"split",
+}
+
+// String answers the name of this component policy.
+func (p ComponentPolicy) String() string {
+	if int(p) < len(componentPolicyNames) {
+		return componentPolicyNames[p]
+	}
+
+	return "unknown"
+}
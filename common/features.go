@@ -0,0 +1,90 @@
+package common
+
+// FeatureKind identifies a functional group that can be substituted
+// on an atom.  Atoms record their features as the `uint16` values of
+// these kinds; `FeatureName` and `Priority` interpret such values.
+//
+// The values of the existing kinds are fixed; new kinds are added at
+// the end.  The importance of a kind is given by its position in the
+// registry `FunctionalGroups`, not by its value.  When an atom has
+// more than one functional group substituted on it, they are ordered
+// accordingly, and the first is its primary feature.
+type FeatureKind uint16
+
+const (
+	FeatureNone FeatureKind = iota
+	FeatureCarboxyl
+	FeatureAnhydride
+	FeatureEster
+	FeatureAmide
+	FeatureNitrile
+	FeatureCarbonyl
+	FeatureNitro
+	FeatureHydroxyl
+	FeatureThiol
+	FeatureAmine
+	FeatureEther
+	FeatureHalide
+)
+
+// FunctionalGroup describes a functional group known to RxnWeaver.
+type FunctionalGroup struct {
+	Kind FeatureKind // Identifier of the group.
+	Name string      // Conventional name of the group.
+}
+
+// FunctionalGroups is the registry of functional groups that can be
+// detected in a molecule, in descending order of importance.
+var FunctionalGroups = []FunctionalGroup{
+	{FeatureCarboxyl, "carboxyl"},
+	{FeatureAnhydride, "anhydride"},
+	{FeatureEster, "ester"},
+	{FeatureAmide, "amide"},
+	{FeatureNitrile, "nitrile"},
+	{FeatureCarbonyl, "carbonyl"},
+	{FeatureNitro, "nitro"},
+	{FeatureHydroxyl, "hydroxyl"},
+	{FeatureThiol, "thiol"},
+	{FeatureAmine, "amine"},
+	{FeatureEther, "ether"},
+	{FeatureHalide, "halide"},
+}
+
+// Priority answers the rank of this functional group in the registry
+// `FunctionalGroups`: `0` for the most important group, and larger
+// values for less important ones.  Unknown kinds rank after all the
+// known ones.
+func (fk FeatureKind) Priority() int {
+	for i, fg := range FunctionalGroups {
+		if fg.Kind == fk {
+			return i
+		}
+	}
+
+	return len(FunctionalGroups)
+}
+
+// String answers the conventional name of this functional group.
+func (fk FeatureKind) String() string {
+	for _, fg := range FunctionalGroups {
+		if fg.Kind == fk {
+			return fg.Name
+		}
+	}
+
+	return "none"
+}
+
+// FeatureName answers the conventional name of the functional group
+// with the given feature ID, as recorded on atoms.  Answers `none` for
+// an unknown ID.
+func FeatureName(id uint16) string {
+	return FeatureKind(id).String()
+}
+
+// Priority answers the rank of the functional group with the given
+// feature ID, as recorded on atoms: `0` for the most important group.
+// See `FeatureKind.Priority`.
+func Priority(id uint16) int {
+	return FeatureKind(id).Priority()
+}
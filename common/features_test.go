@@ -0,0 +1,38 @@
+package common
+
+import "testing"
+
+func TestFunctionalGroupRegistry(t *testing.T) {
+	seen := make(map[FeatureKind]bool)
+	for i, fg := range FunctionalGroups {
+		if fg.Kind == FeatureNone || seen[fg.Kind] {
+			t.Errorf("kind %d registered at %d is none or repeated", fg.Kind, i)
+		}
+		seen[fg.Kind] = true
+
+		if p := Priority(uint16(fg.Kind)); p != i {
+			t.Errorf("%s has priority %d; want %d", fg.Name, p, i)
+		}
+		if n := FeatureName(uint16(fg.Kind)); n != fg.Name {
+			t.Errorf("kind %d is named %s; want %s", fg.Kind, n, fg.Name)
+		}
+	}
+	if len(seen) != int(FeatureHalide) {
+		t.Errorf("%d kinds registered; want %d", len(seen), FeatureHalide)
+	}
+
+	// The ordering used to sort the features of atoms.
+	order := []FeatureKind{FeatureCarboxyl, FeatureEster, FeatureAmide, FeatureCarbonyl, FeatureHydroxyl, FeatureAmine, FeatureHalide}
+	for i := 1; i < len(order); i++ {
+		if order[i-1].Priority() >= order[i].Priority() {
+			t.Errorf("%s does not outrank %s", order[i-1], order[i])
+		}
+	}
+
+	if n := FeatureName(999); n != "none" {
+		t.Errorf("unknown kind is named %s; want none", n)
+	}
+	if p := Priority(999); p != len(FunctionalGroups) {
+		t.Errorf("unknown kind has priority %d; want %d", p, len(FunctionalGroups))
+	}
+}
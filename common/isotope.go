@@ -0,0 +1,61 @@
+package common
+
+import (
+	"fmt"
+	"math"
+)
+
+// monoisotopicMasses holds the exact masses, in unified atomic mass
+// units, of the most abundant isotopes of the elements commonly found
+// in organic molecules, keyed by atomic number.
+//
+// J.R. de Laeter et al., Atomic weights of the elements: Review 2000,
+// Pure Appl. Chem. 75, 683-800 (2003).
+var monoisotopicMasses = map[uint8]float64{
+	1:  1.00782503207,
+	3:  7.01600455,
+	5:  11.0093054,
+	6:  12.0,
+	7:  14.0030740048,
+	8:  15.99491461956,
+	9:  18.99840322,
+	11: 22.9897692809,
+	12: 23.985041700,
+	14: 27.9769265325,
+	15: 30.97376163,
+	16: 31.97207100,
+	17: 34.96885268,
+	19: 38.96370668,
+	20: 39.96259098,
+	34: 79.9165213,
+	35: 78.9183371,
+	53: 126.904473,
+}
+
+// MonoisotopicMass answers the exact mass of the most abundant isotope
+// of the element with the given atomic number.  For elements not
+// covered, the average atomic weight is answered.
+func MonoisotopicMass(atNum uint8) float64 {
+	if m, ok := monoisotopicMasses[atNum]; ok {
+		return m
+	}
+
+	return PeriodicTable[ElementSymbols[atNum]].Weight
+}
+
+// IsotopeMass answers the exact mass of the isotope with the given
+// mass number, of the element with the given atomic number.  When the
+// isotope is not known, its mass number is answered as an
+// approximation.
+func IsotopeMass(atNum uint8, massNumber int) float64 {
+	sym := fmt.Sprintf("%s_%d", ElementSymbols[atNum], massNumber)
+	if el, ok := PeriodicTable[sym]; ok {
+		return el.Weight
+	}
+
+	if m, ok := monoisotopicMasses[atNum]; ok && int(math.Floor(m+0.5)) == massNumber {
+		return m
+	}
+
+	return float64(massNumber)
+}
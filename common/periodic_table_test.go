@@ -0,0 +1,27 @@
+package common
+
+import "testing"
+
+func TestIsValidValence(t *testing.T) {
+	cases := []struct {
+		sym   string
+		v     int8
+		valid bool
+	}{
+		{"C", 4, true},
+		{"C", 5, false},
+		{"N", 3, true},
+		{"O", 2, true},
+		{"S", 6, true},
+		{"Cl", 1, true},
+		// The sole oxidation state of fluorine is negative.
+		{"F", 1, true},
+		{"F", 3, false},
+	}
+	for _, c := range cases {
+		ok, err := IsValidValence(PeriodicTable[c.sym].Number, c.v)
+		if ok != c.valid || (err == nil) != c.valid {
+			t.Errorf("%s with valence %d : valid %v, error %v; want valid %v", c.sym, c.v, ok, err, c.valid)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package molecule
+
+import (
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// AcidicSites answers the input IDs of the atoms of this molecule that
+// can lose a proton under ordinary conditions: the acidic oxygen atoms
+// of carboxylic acids, phenols and sulfonic acids.  An oxygen is
+// reported whether it is protonated, or already deprotonated, so that
+// the answer is the same for all protonation states of a molecule.
+//
+// The atoms are answered in the order in which they are held.  Stale
+// rings and aromaticity are determined afresh; `nil` is answered if
+// that fails.
+func (m *Molecule) AcidicSites() []uint16 {
+	if m.refresh(stageAromaticity) != nil {
+		return nil
+	}
+
+	ids := make([]uint16, 0, cmn.ListSizeTiny)
+	for _, a := range m.atoms {
+		if a.isAcidicO() {
+			ids = append(ids, a.iId)
+		}
+	}
+
+	return ids
+}
+
+// BasicSites answers the input IDs of the atoms of this molecule that
+// can gain a proton under ordinary conditions: the nitrogen atoms of
+// aliphatic amines, and the imino nitrogen atoms of amidines and
+// guanidines.  A nitrogen is reported whether it is protonated, or
+// not.
+//
+// Amide nitrogen atoms are not basic; nor are those of anilines,
+// aromatic rings, nitro groups and sulfonamides.
+//
+// The atoms are answered in the order in which they are held.  Stale
+// rings and aromaticity are determined afresh; `nil` is answered if
+// that fails.
+func (m *Molecule) BasicSites() []uint16 {
+	if m.refresh(stageAromaticity) != nil {
+		return nil
+	}
+
+	ids := make([]uint16, 0, cmn.ListSizeTiny)
+	for _, a := range m.atoms {
+		if a.isAliphaticAmineN() || a.isAmidineN() {
+			ids = append(ids, a.iId)
+		}
+	}
+
+	return ids
+}
+
+// isAcidicO answers if this atom is an oxygen bearing a hydrogen or a
+// negative charge, that is singly-bonded to the carbonyl carbon of a
+// carboxyl group, to an aromatic carbon, or to the sulfur of a
+// sulfonic acid.
+func (a *_Atom) isAcidicO() bool {
+	if a.atNum != 8 || a.bonds.Count() != 1 || a.singleBondCount != 1 {
+		return false
+	}
+	if a.hCount == 0 && a.charge >= 0 {
+		return false
+	}
+
+	mol := a.mol
+	oa := mol.atomWithIid(a.distinctNeighbours()[0])
+	switch {
+	case oa.atNum == 6 && oa.carboxylKind() == cmn.FeatureCarboxyl:
+		return true
+	case oa.atNum == 6 && oa.isInAroRing:
+		return true
+	case oa.atNum == 16:
+		return oa.isSulfonylS()
+	}
+
+	return false
+}
+
+// isSulfonylS answers if this atom is a sulfur bonded to three or more
+// oxygen atoms and a carbon atom, as in a sulfonic acid.
+func (a *_Atom) isSulfonylS() bool {
+	if a.atNum != 16 {
+		return false
+	}
+
+	mol := a.mol
+	no, nc := 0, 0
+	for _, nid := range a.distinctNeighbours() {
+		switch mol.atomWithIid(nid).atNum {
+		case 8:
+			no++
+		case 6:
+			nc++
+		}
+	}
+
+	return no >= 3 && nc >= 1
+}
+
+// isAliphaticAmineN answers if this atom is the nitrogen of an
+// aliphatic amine, or of its conjugate acid: a non-aromatic nitrogen
+// with only single bonds, all to saturated carbon atoms.  Quaternary
+// ammonium nitrogen atoms, bearing no hydrogen, are not.
+func (a *_Atom) isAliphaticAmineN() bool {
+	if a.atNum != 7 || a.isInAroRing || a.doubleBondCount > 0 || a.tripleBondCount > 0 {
+		return false
+	}
+
+	conns := int(a.bonds.Count()) + int(a.hCount)
+	switch {
+	case a.charge == 0 && conns == 3:
+	case a.charge == 1 && conns == 4 && a.hCount > 0:
+	default:
+		return false
+	}
+
+	mol := a.mol
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		b := mol.bondWithId(uint16(bid))
+		if b.bType != cmn.BondTypeSingle {
+			return false
+		}
+		oa := mol.atomWithIid(b.otherAtomIid(a.iId))
+		if oa.atNum != 6 || oa.isInAroRing || oa.unsaturation != cmn.UnsaturationNone {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isAmidineN answers if this atom is the imino nitrogen of an amidine
+// or a guanidine, or of its conjugate acid: a non-aromatic nitrogen
+// doubly-bonded to a non-aromatic carbon, which is also singly-bonded
+// to a trivalent nitrogen.
+func (a *_Atom) isAmidineN() bool {
+	if a.atNum != 7 || a.isInAroRing || a.doubleBondCount != 1 || a.charge < 0 {
+		return false
+	}
+
+	mol := a.mol
+	cid, db := a.firstDoublyBondedNeighbourId()
+	if db == nil || db.isAro {
+		return false
+	}
+	c := mol.atomWithIid(cid)
+	if c.atNum != 6 || c.isInAroRing {
+		return false
+	}
+
+	for bid, ok := c.bonds.NextSet(0); ok; bid, ok = c.bonds.NextSet(bid + 1) {
+		b := mol.bondWithId(uint16(bid))
+		if b == db || b.bType != cmn.BondTypeSingle {
+			continue
+		}
+		if mol.atomWithIid(b.otherAtomIid(c.iId)).isTrivalentN() {
+			return true
+		}
+	}
+
+	return false
+}
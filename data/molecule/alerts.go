@@ -0,0 +1,112 @@
+package molecule
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AlertSet is a named collection of structural alerts: SMARTS patterns
+// of substructures that make a molecule undesirable, such as reactive
+// or assay-interfering groups.  See `MatchSMARTS` for the supported
+// subset of SMARTS.
+//
+// An alert set is read-only once loaded; it can be used to screen
+// several molecules concurrently.
+type AlertSet struct {
+	names   []string        // Names of the alerts, in sorted order.
+	queries []*_SmartsQuery // Parsed patterns, aligned with the names.
+}
+
+// LoadAlerts answers an alert set comprising the given SMARTS
+// patterns, keyed by the names of their alerts.  All the patterns are
+// parsed upfront; an error is answered for the first invalid one, in
+// the order of their names.
+func LoadAlerts(patterns map[string]string) (*AlertSet, error) {
+	s := &AlertSet{
+		names:   make([]string, 0, len(patterns)),
+		queries: make([]*_SmartsQuery, 0, len(patterns)),
+	}
+	for name := range patterns {
+		s.names = append(s.names, name)
+	}
+	sort.Strings(s.names)
+
+	for _, name := range s.names {
+		q, err := parseSmarts(patterns[name])
+		if err != nil {
+			return nil, fmt.Errorf("Alert %q : %v", name, err)
+		}
+		s.queries = append(s.queries, q)
+	}
+
+	return s, nil
+}
+
+// BuiltinAlerts answers a small alert set of common reactive and
+// promiscuous groups, such as acyl halides, aldehydes, Michael
+// acceptors, quinones and rhodanines.  See `Names` for all of them.
+//
+// The same set is answered on every call; being read-only, it can be
+// shared freely.
+func BuiltinAlerts() *AlertSet {
+	return builtinAlerts
+}
+
+// builtinAlerts is the alert set of `BuiltinAlerts`, parsed once.
+var builtinAlerts = loadBuiltinAlerts()
+
+// loadBuiltinAlerts parses the patterns of the built-in alerts.  They
+// are fixed, so that a failure is a defect in this package; it panics
+// in that case.
+func loadBuiltinAlerts() *AlertSet {
+	s, err := LoadAlerts(builtinAlertPatterns)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// builtinAlertPatterns lists the alerts of `BuiltinAlerts`.
+var builtinAlertPatterns = map[string]string{
+	"acyl_halide":      "C(=O)[F,Cl,Br,I]",
+	"aldehyde":         "[#6][CH1]=O",
+	"alkyl_halide":     "[CH2;!R][Cl,Br,I]",
+	"azo":              "[#6]N=N[#6]",
+	"catechol":         "c([OH1])c[OH1]",
+	"epoxide":          "C1OC1",
+	"isocyanate":       "N=C=O",
+	"michael_acceptor": "[CH2]=CC=O",
+	"nitroaromatic":    "a[N+](=O)[O-]",
+	"peroxide":         "OO",
+	"quinone":          "O=C1C=CC(=O)C=C1",
+	"rhodanine":        "S=C1SC(=*)C(=O)N1",
+	"sulfonyl_halide":  "S(=O)(=O)[F,Cl,Br,I]",
+	"thiol":            "[SH1]",
+}
+
+// Names answers the names of the alerts in this set, in sorted order.
+func (s *AlertSet) Names() []string {
+	return append([]string(nil), s.names...)
+}
+
+// Screen answers the names of the alerts in this set that match the
+// given molecule, in sorted order.  Matching of each alert stops at its
+// first match.
+//
+// Stale rings and aromaticity of the molecule are determined afresh;
+// `nil` is answered if that fails.
+func (s *AlertSet) Screen(m *Molecule) []string {
+	if m.refresh(stageAromaticity) != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(s.names))
+	for i, q := range s.queries {
+		if len(m.matchSmarts(q, 1)) > 0 {
+			names = append(names, s.names[i])
+		}
+	}
+
+	return names
+}
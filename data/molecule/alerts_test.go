@@ -0,0 +1,13 @@
+package molecule
+
+import "testing"
+
+func TestBuiltinAlertsParsedOnce(t *testing.T) {
+	s := BuiltinAlerts()
+	if len(s.Names()) != len(builtinAlertPatterns) {
+		t.Errorf("%d alerts; want %d", len(s.Names()), len(builtinAlertPatterns))
+	}
+	if BuiltinAlerts() != s {
+		t.Error("built-in alerts parsed again")
+	}
+}
@@ -0,0 +1,90 @@
+package molecule
+
+import (
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// DetermineAromaticity determines the aromaticity of the ring systems
+// of this molecule, and of their rings, as described in
+// `doc/design/aromaticity-determination.md`.  Any previously-set
+// aromaticity flags are cleared first.
+//
+// A ring system comprising a single ring is handled as that ring,
+// so that the ring itself gets marked when aromatic.
+//
+// The aromaticity model last selected using
+// `DetermineAromaticityWithModel` applies; the default model, if none
+// was selected.
+//
+// Rings and ring systems must have been detected before this method
+// is invoked.
+func (m *Molecule) DetermineAromaticity() error {
+	m.clearAromaticity()
+
+	if m.aroModel == cmn.AromaticityModelMDL {
+		// Rings are considered individually.
+		for _, rs := range m.ringSystems {
+			rs.isAro = true
+			for _, rid := range rs.rings {
+				r := m.ringWithId(rid)
+				r.determineAromaticity()
+				rs.isAro = rs.isAro && r.isAro
+			}
+		}
+		return nil
+	}
+
+	for _, rs := range m.ringSystems {
+		if rs.size() == 1 {
+			r := m.ringWithId(rs.ringAt(0))
+			r.determineAromaticity()
+			rs.isAro = r.isAro
+			continue
+		}
+
+		rs.determineAromaticity()
+	}
+
+	if m.aroModel == cmn.AromaticityModelDaylight {
+		for _, r := range m.rings {
+			if r.isSemiAromaticOfSize6() {
+				r.markAromatic()
+			}
+		}
+	}
+
+	return nil
+}
+
+// DetermineAromaticityWithModel selects the given aromaticity model
+// for this molecule, and determines aromaticity accordingly.  See
+// `DetermineAromaticity`.
+//
+// The model stays selected; later normalisations use it as well.
+// Since that changes derived information, an error is answered if this
+// molecule is frozen.
+func (m *Molecule) DetermineAromaticityWithModel(model cmn.AromaticityModel) error {
+	if err := m.checkMutable(); err != nil {
+		return err
+	}
+	m.aroModel = model
+	return m.DetermineAromaticity()
+}
+
+// clearAromaticity resets the aromaticity flags of all atoms, bonds,
+// rings and ring systems of this molecule.
+func (m *Molecule) clearAromaticity() {
+	for _, a := range m.atoms {
+		a.isInAroRing = false
+	}
+	for _, b := range m.bonds {
+		b.isAro = false
+	}
+	for _, r := range m.rings {
+		r.isAro = false
+		r.isHetAro = false
+	}
+	for _, rs := range m.ringSystems {
+		rs.isAro = false
+	}
+}
@@ -0,0 +1,98 @@
+package molecule
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// carbocycleMolfile answers a molfile of a single carbon ring, with the
+// given bond orders, and the given charge on its last atom.  Hydrogen
+// atoms are left implicit.
+func carbocycleMolfile(orders []int, charge int) string {
+	n := len(orders)
+	var sb strings.Builder
+	sb.WriteString("ring\n  test\n\n")
+	fmt.Fprintf(&sb, "%3d%3d  0  0  0  0  0  0  0  0999 V2000\n", n, n)
+	for i := 0; i < n; i++ {
+		sb.WriteString("    0.0000    0.0000    0.0000 C   0  0  0  0  0  0  0  0  0  0  0  0\n")
+	}
+	for i, o := range orders {
+		fmt.Fprintf(&sb, "%3d%3d%3d  0\n", i+1, (i+1)%n+1, o)
+	}
+	if charge != 0 {
+		fmt.Fprintf(&sb, "M  CHG  1 %3d %3d\n", n, charge)
+	}
+	sb.WriteString("M  END")
+	return sb.String()
+}
+
+func TestChargedRingAromaticity(t *testing.T) {
+	cases := []struct {
+		name     string
+		orders   []int
+		charge   int
+		formula  string
+		aromatic bool
+	}{
+		{"tropylium", []int{2, 1, 2, 1, 2, 1, 1}, 1, "C7H7", true},
+		{"cyclopentadienyl", []int{2, 1, 2, 1, 1}, -1, "C5H5", true},
+		{"cyclopentadiene", []int{2, 1, 2, 1, 1}, 0, "C5H6", false},
+	}
+	for _, c := range cases {
+		m, _, err := ParseMolfile(molfileLines(carbocycleMolfile(c.orders, c.charge)), 1)
+		if err != nil {
+			t.Fatalf("%s : %v", c.name, err)
+		}
+		if err := m.Normalise(); err != nil {
+			t.Fatalf("%s : %v", c.name, err)
+		}
+
+		if f := m.Formula(); f != c.formula {
+			t.Errorf("%s : formula %s; want %s", c.name, f, c.formula)
+		}
+		for _, a := range m.atoms {
+			if a.isInAroRing != c.aromatic {
+				t.Errorf("%s : atom %d aromatic : %v; want %v", c.name, a.iId, a.isInAroRing, c.aromatic)
+			}
+		}
+		m.discard()
+	}
+}
+
+func TestFusedAromaticRingCounts(t *testing.T) {
+	cases := []struct {
+		name            string
+		n               int // Number of carbon atoms.
+		bonds           []_TestBond
+		isolated, fused int
+	}{
+		{"benzene", 6, testBonds([][3]int{
+			{1, 2, 2}, {2, 3, 1}, {3, 4, 2}, {4, 5, 1}, {5, 6, 2}, {6, 1, 1},
+		}), 1, 0},
+		{"naphthalene", 10, testBonds([][3]int{
+			{1, 2, 2}, {2, 3, 1}, {3, 4, 2}, {4, 5, 1}, {5, 6, 2}, {6, 1, 1},
+			{5, 7, 1}, {7, 8, 2}, {8, 9, 1}, {9, 10, 2}, {10, 6, 1},
+		}), 0, 2},
+		// The rings of biphenyl share no bond.
+		{"biphenyl", 12, testBonds([][3]int{
+			{1, 2, 2}, {2, 3, 1}, {3, 4, 2}, {4, 5, 1}, {5, 6, 2}, {6, 1, 1},
+			{1, 7, 1},
+			{7, 8, 2}, {8, 9, 1}, {9, 10, 2}, {10, 11, 1}, {11, 12, 2}, {12, 7, 1},
+		}), 2, 0},
+	}
+	for _, c := range cases {
+		syms := make([]string, c.n)
+		for i := range syms {
+			syms[i] = "C"
+		}
+		m := buildMolecule(t, syms, c.bonds)
+		if n := m.IsolatedAromaticRingCount(); n != c.isolated {
+			t.Errorf("%s : %d isolated aromatic rings; want %d", c.name, n, c.isolated)
+		}
+		if n := m.FusedAromaticRingCount(); n != c.fused {
+			t.Errorf("%s : %d fused aromatic rings; want %d", c.name, n, c.fused)
+		}
+		m.discard()
+	}
+}
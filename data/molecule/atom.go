@@ -3,6 +3,9 @@ package molecule
 import (
 	"fmt"
 	"math"
+	"sort"
+	"strconv"
+	"strings"
 
 	bits "github.com/willf/bitset"
 
@@ -21,6 +24,9 @@ type _Atom struct {
 	iId    uint16    // Serial input ID of this atom.
 	nId    uint16    // Normalised ID of this atom.
 
+	isotope uint16 // Mass number, if a specific isotope; `0` otherwise.
+	mapNum  uint16 // Atom-atom mapping number in a reaction; `0` if unmapped.
+
 	X float32 // X-coordinate of this atom.
 	Y float32 // Y-coordinate of this atom.
 	Z float32 // Z-coordinate of this atom.
@@ -32,11 +38,18 @@ type _Atom struct {
 
 	unsaturation cmn.Unsaturation // Current composite state of this atom.
 
+	// Tetrahedral configuration of this atom, if it is a stereo
+	// centre.  See `PerceiveTetrahedralStereo` for the details.
+	stereoParity cmn.StereoParity
+	// CIP descriptor of this atom, if it is a stereo centre.  See
+	// `AssignCIP`.
+	cipLabel cmn.CIPLabel
+
 	pHash uint64 // A pseudo-hash of this atom, using some attributes.
 	sHash uint64 // A pseudo-hash of this atom, using some attributes.
 
-	bonds           *bits.BitSet // Bitmap of bonds of this atom.
-	nbrs            []uint16     // Expanded list of neighbours of this atom.
+	bonds           *bits.BitSet // Bitmap of bonds of this atom, indexed by bond ID.
+	nbrs            []uint16     // Neighbours, repeated per bond order.
 	singleBondCount uint8        // Number of single bonds this atom has.
 	doubleBondCount uint8        // Number of double bonds this atom has.
 	tripleBondCount uint8        // Number of triple bonds this atom has.
@@ -82,6 +95,76 @@ func newAtom(mol *Molecule, atNum uint8, iId int) *_Atom {
 	return atom
 }
 
+// String answers a one-line, human-readable description of the state
+// of this atom, for debugging.  It is safe to call at any time; the
+// normalised ID is shown as `-` until one is assigned.
+//
+//	C#5 [nId=3] bonds=3 H=1 charge=0 unsat=DoubleBondW rings={2,3} aromatic=true features=[carbonyl]
+func (a *_Atom) String() string {
+	var sb strings.Builder
+	sb.Grow(96)
+
+	sb.WriteString(a.symbol)
+	sb.WriteByte('#')
+	sb.WriteString(strconv.Itoa(int(a.iId)))
+	sb.WriteString(" [nId=")
+	if a.nId == 0 {
+		sb.WriteByte('-')
+	} else {
+		sb.WriteString(strconv.Itoa(int(a.nId)))
+	}
+	sb.WriteString("] bonds=")
+	sb.WriteString(strconv.Itoa(int(a.bonds.Count())))
+	sb.WriteString(" H=")
+	sb.WriteString(strconv.Itoa(int(a.hCount)))
+	sb.WriteString(" charge=")
+	sb.WriteString(strconv.Itoa(int(a.charge)))
+	sb.WriteString(" unsat=")
+	sb.WriteString(a.unsaturation.String())
+	sb.WriteString(" rings=")
+	writeBitSet(&sb, a.rings)
+	sb.WriteString(" aromatic=")
+	sb.WriteString(strconv.FormatBool(a.isInAroRing))
+	sb.WriteString(" features=[")
+	for i, f := range a.features {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(cmn.FeatureName(f))
+	}
+	sb.WriteByte(']')
+
+	return sb.String()
+}
+
+// writeBitSet writes the members of the given set to the given
+// builder, in increasing order, as in `{2,3}`.
+func writeBitSet(sb *strings.Builder, bs *bits.BitSet) {
+	sb.WriteByte('{')
+	first := true
+	for i, ok := bs.NextSet(0); ok; i, ok = bs.NextSet(i + 1) {
+		if !first {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.Itoa(int(i)))
+		first = false
+	}
+	sb.WriteByte('}')
+}
+
+// writeIds writes the given number of IDs to the given builder, in
+// order, as in `{2,3}`.  The function answers the ID at each index.
+func writeIds(sb *strings.Builder, n int, id func(i int) int) {
+	sb.WriteByte('{')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.Itoa(id(i)))
+	}
+	sb.WriteByte('}')
+}
+
 // AtomicNumber answers the atomic number of this atom.
 func (a *_Atom) AtomicNumber() uint8 {
 	return a.atNum
@@ -102,25 +185,27 @@ func (a *_Atom) Parent() *Molecule {
 // parts of RxnWeaver's decision rules.  Exercise great caution should
 // you need to modify this in any manner!
 func (a *_Atom) determineUnsaturation() error {
-	nb := int(a.bonds.Count())
-	nn := len(a.nbrs)
+	// `nbrs` lists each neighbour once per order of the bond to it;
+	// its length is, hence, the sum of the bond orders of this atom.
+	bos := len(a.nbrs)
 
-	// Atom has a residual charge.
-	if a.charge != 0 {
+	// Atom has a residual charge, possibly by way of a dative bond.
+	if a.formalCharge() != 0 {
 		a.unsaturation = cmn.UnsaturationCharged
 		return nil
 	}
 
-	// For an uncharged atom, valence should be sane.
-	if a.hCount > 0 {
-		os := int8(nn) + int8(a.hCount)
-		if ok, err := cmn.IsValidOxidationState(a.atNum, os); !ok {
+	// For an uncharged atom, valence should be sane.  The unpaired
+	// electrons of a radical occupy valence, as bonds do.
+	if a.hCount > 0 || a.radical != cmn.RadicalNone {
+		v := int8(bos) + int8(a.hCount) + int8(a.radicalElectronCount())
+		if ok, err := cmn.IsValidValence(a.atNum, v); !ok {
 			return err
 		}
 	}
 
 	// Case of all single bonds.
-	if nb == nn {
+	if a.doubleBondCount == 0 && a.tripleBondCount == 0 {
 		a.unsaturation = cmn.UnsaturationNone
 		return nil
 	}
@@ -177,6 +262,275 @@ func (a *_Atom) determineUnsaturation() error {
 	return nil
 }
 
+// checkValence answers an error if the valence of this atom - the sum
+// of its bond orders, hydrogen atoms and unpaired electrons - is not
+// permitted for its element.  See `cmn.IsValidValence`.  Charged atoms
+// are not checked, as in `determineUnsaturation`.
+func (a *_Atom) checkValence() error {
+	if a.formalCharge() != 0 {
+		return nil
+	}
+
+	v := int8(len(a.nbrs)) + int8(a.hCount) + int8(a.radicalElectronCount())
+	if ok, err := cmn.IsValidValence(a.atNum, v); !ok {
+		return err
+	}
+	return nil
+}
+
+// chargedValence answers the default valence of this atom's element,
+// raised by a positive charge on a pnictogen or a chalcogen, and
+// lowered by any other charge.
+func (a *_Atom) chargedValence() int {
+	v := int(cmn.PeriodicTable[a.symbol].Valence)
+	ch := a.formalCharge()
+	switch {
+	case ch > 0 && (a.atNum == 7 || a.atNum == 8 || a.atNum == 15 || a.atNum == 16):
+		v += ch
+	case ch > 0:
+		v -= ch
+	case ch < 0:
+		v += ch
+	}
+
+	return v
+}
+
+// hypervalentValences answers the valences, in ascending order, that
+// a neutral atom of the element with the given atomic number may
+// exhibit.  Answers `nil` for elements that are not hypervalent.
+func hypervalentValences(atNum uint8) []int {
+	switch atNum {
+	case 15:
+		return []int{3, 5}
+	case 16, 34:
+		return []int{2, 4, 6}
+	case 17, 35, 53:
+		return []int{1, 3, 5, 7}
+	}
+
+	return nil
+}
+
+// mdlPiElectronCount answers the number of pi electrons contributed by
+// this atom under the MDL aromaticity model: `1` if it has exactly one
+// double bond, and that in a ring.  Any other atom prevents its rings
+// from becoming aromatic.
+func (a *_Atom) mdlPiElectronCount() (int, bool) {
+	if a.doubleBondCount != 1 || a.tripleBondCount != 0 {
+		return 0, false
+	}
+
+	_, b := a.firstDoublyBondedNeighbourId()
+	if b == nil || !b.isCyclic() {
+		return 0, false
+	}
+	return 1, true
+}
+
+// hasExocyclicDoubleBondToC answers if this atom has an acyclic double
+// bond to a carbon atom.
+func (a *_Atom) hasExocyclicDoubleBondToC() bool {
+	mol := a.mol
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		b := mol.bondWithId(uint16(bid))
+		if b.bType != cmn.BondTypeDouble || b.isCyclic() {
+			continue
+		}
+		if mol.atomWithIid(b.otherAtomIid(a.iId)).atNum == 6 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isChargeConsistent answers if the sum of this atom's bond orders,
+// hydrogen count and radical electrons is within the valence permitted
+// by its charge.  See
+// `chargedValence`.  Neutral atoms of hypervalent elements are
+// permitted their highest valence.  Elements with no default valence
+// are not checked.
+func (a *_Atom) isChargeConsistent() bool {
+	if cmn.PeriodicTable[a.symbol].Valence < 0 {
+		return true
+	}
+
+	max := a.chargedValence()
+	if a.formalCharge() == 0 {
+		if vs := hypervalentValences(a.atNum); len(vs) > 0 {
+			max = vs[len(vs)-1]
+		}
+	}
+
+	return len(a.nbrs)+int(a.hCount)+a.radicalElectronCount() <= max
+}
+
+// formalCharge answers the residual charge of this atom, adjusted for
+// its dative bonds: each bond it donates raises the charge by one, and
+// each it accepts lowers it by one.
+//
+// A dative bond is, thus, treated as a single bond between
+// charge-separated atoms.  A nitro group drawn as `N(->O)=O` is
+// validated exactly as one drawn as `[N+]([O-])=O`.
+func (a *_Atom) formalCharge() int {
+	ch := int(a.charge)
+
+	mol := a.mol
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		b := mol.bondWithId(uint16(bid))
+		if b.bType != cmn.BondTypeDative {
+			continue
+		}
+		if b.a1 == a.iId {
+			ch++
+		} else {
+			ch--
+		}
+	}
+
+	return ch
+}
+
+// inferHydrogenCount sets the hydrogen count of this atom according
+// to the given policy.  It is meant for input formats that omit
+// hydrogen atoms.  See `cmn.HydrogenPolicy`.
+//
+// Under the filling policies, the count is that needed to bring this
+// atom to the valence permitted by its charge, after accounting for
+// its bond orders and radical electrons.  See `chargedValence`.  Atoms
+// already beyond that valence are brought to the next valence of
+// their element, or left alone, as the policy specifies.  Hydrogen
+// atoms given explicitly are never removed.
+//
+// Hydrogen atoms are not inferred for elements with no default
+// valence, nor for atoms whose bonds exceed every valence their
+// element permits.  Bonds given as aromatic in the input should have
+// been assigned orders, beforehand.  Answers an error in these cases,
+// leaving the hydrogen count unchanged.
+func (a *_Atom) inferHydrogenCount(policy cmn.HydrogenPolicy) error {
+	if policy == cmn.HydrogenPolicyExplicit {
+		return nil
+	}
+	if cmn.PeriodicTable[a.symbol].Valence < 0 {
+		return fmt.Errorf("Atom %d : element %s has no default valence.", a.iId, a.symbol)
+	}
+	if a.hasUnassignedBondOrder() {
+		return fmt.Errorf("Atom %d has aromatic bonds with no assigned order.", a.iId)
+	}
+
+	used := len(a.nbrs) + a.radicalElectronCount()
+	h := a.chargedValence() - used
+	if h < 0 && policy == cmn.HydrogenPolicyFill && a.formalCharge() == 0 {
+		for _, hv := range hypervalentValences(a.atNum) {
+			if hv >= used {
+				h = hv - used
+				break
+			}
+		}
+	}
+	if h < 0 {
+		if policy == cmn.HydrogenPolicyFillCapped {
+			return nil
+		}
+		return fmt.Errorf("Atom %d is hypervalent; its hydrogen count can not be inferred.", a.iId)
+	}
+
+	if a.formalCharge() == 0 {
+		if ok, err := cmn.IsValidValence(a.atNum, int8(used+h)); !ok {
+			return err
+		}
+	}
+
+	if h > int(a.hCount) {
+		a.hCount = uint8(h)
+	}
+	return nil
+}
+
+// inferFormalCharge answers the charge that reconciles the valence of
+// this uncharged atom - the sum of its bond orders, hydrogen count and
+// radical electrons - with the default valence of its element.  See
+// `chargedValence`.
+//
+// A pnictogen or a chalcogen with one bond order too many is a cation,
+// as in ammonium.  A metal short of its valence is a cation as well,
+// while any other atom short of its valence is an anion.  A carbon
+// atom short of its valence could equally be a cation, an anion or a
+// carbene; it is answered `0`, as are atoms already charged, atoms at
+// a valence their element permits, and atoms of elements with no
+// default valence.
+//
+// Answers an error when no single charge reconciles the valence -
+// pentavalent nitrogen, for instance - or when the charge is not a
+// valid oxidation state of the element.
+func (a *_Atom) inferFormalCharge() (int, error) {
+	v := int(cmn.PeriodicTable[a.symbol].Valence)
+	if v < 0 || a.formalCharge() != 0 {
+		return 0, nil
+	}
+	if a.hasUnassignedBondOrder() {
+		return 0, fmt.Errorf("Atom %d has aromatic bonds with no assigned order.", a.iId)
+	}
+
+	used := len(a.nbrs) + int(a.hCount) + a.radicalElectronCount()
+	if used == v {
+		return 0, nil
+	}
+	for _, hv := range hypervalentValences(a.atNum) {
+		if used == hv {
+			return 0, nil
+		}
+	}
+
+	ch := 0
+	d := used - v
+	switch {
+	case d == 1 && (a.atNum == 7 || a.atNum == 8 || a.atNum == 15 || a.atNum == 16):
+		ch = 1
+	case d < 0 && a.atNum == 6:
+		return 0, nil
+	case d < 0 && cmn.IsMetal(a.atNum):
+		ch = -d
+	case d < 0:
+		ch = d
+	default:
+		return 0, fmt.Errorf("Atom %d : valence %d of %s can not be reconciled by a charge.", a.iId, used, a.symbol)
+	}
+
+	if ok, err := cmn.IsValidOxidationState(a.atNum, int8(ch)); !ok {
+		return 0, fmt.Errorf("Atom %d : %v", a.iId, err)
+	}
+	return ch, nil
+}
+
+// hasUnassignedBondOrder answers if any of the bonds of this atom was
+// given as aromatic in the input, and is yet to be assigned an order.
+func (a *_Atom) hasUnassignedBondOrder() bool {
+	mol := a.mol
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		if mol.bondWithId(uint16(bid)).bType == cmn.BondTypeAltern {
+			return true
+		}
+	}
+
+	return false
+}
+
+// radicalElectronCount answers the number of valence electrons of this
+// atom that are taken up by its radical state: `1` for a doublet, and
+// `2` for a singlet or a triplet.
+func (a *_Atom) radicalElectronCount() int {
+	switch a.radical {
+	case cmn.RadicalDoublet:
+		return 1
+	case cmn.RadicalSinglet, cmn.RadicalTriplet:
+		return 2
+	}
+
+	return 0
+}
+
 // piElectronCount answers the number of delocalised pi electrons
 // contributed by this atom.
 //
@@ -187,26 +541,38 @@ func (a *_Atom) determineUnsaturation() error {
 // could contribute towards computation of aromaticity or not.  A
 // `false` value means that the presence of such an atom prevents the
 // ring containing it from becoming aromatic.
+//
+// The contributions depend on the aromaticity model of the molecule.
+// See `cmn.AromaticityModel`.
 func (a *_Atom) piElectronCount() (int, bool) {
 	mol := a.mol
-	wtSum := 100*int16(a.doubleBondCount) + 10*int16(a.singleBondCount) + int16(a.charge)
+
+	switch mol.aroModel {
+	case cmn.AromaticityModelMDL:
+		return a.mdlPiElectronCount()
+	case cmn.AromaticityModelDaylight:
+		if a.hasExocyclicDoubleBondToC() {
+			return 0, false
+		}
+	}
+
+	wtSum := 100*int16(a.doubleBondCount) + 10*int16(a.singleBondCount) + int16(a.formalCharge())
 
 	switch a.atNum {
 	case 6:
 		switch wtSum {
-		case 19:
+		case 19, 29: // Carbanion; its lone pair is delocalised.
 			return 2, true
+		case 21, 31: // Carbocation; its empty p orbital is delocalised.
+			return 0, true
 		case 20:
 			return 0, true
 		case 110:
 			return 1, true
 		case 120:
-			var b *_Bond
-			for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
-				b = mol.bondWithId(uint16(bid))
-				if b.bType == cmn.BondTypeDouble {
-					break
-				}
+			_, b := a.firstDoublyBondedNeighbourId()
+			if b == nil {
+				return 0, true
 			}
 			if !b.isCyclic() { // Exocyclic bond.
 				return 0, true
@@ -220,7 +586,7 @@ func (a *_Atom) piElectronCount() (int, bool) {
 		switch wtSum {
 		case 20, 30:
 			return 2, true
-		case 110, 121:
+		case 110, 111, 121:
 			return 1, true
 		default:
 			return 0, true
@@ -244,6 +610,9 @@ func (a *_Atom) piElectronCount() (int, bool) {
 			return 1, true
 		case 120:
 			oaid, b := a.firstDoublyBondedNeighbourId()
+			if b == nil {
+				return 0, true
+			}
 			oa := mol.atomWithIid(oaid)
 			if oa.atNum == 8 && !b.isCyclic() { // Exocyclic bond with an oxygen.
 				return 2, true
@@ -282,6 +651,23 @@ func (a *_Atom) isJunction() bool {
 	return a.bonds.Count() > 2
 }
 
+// distinctNeighbours answers the input IDs of the atoms bonded to
+// this atom, each listed once, in the order of the bonds.
+//
+// Note that `nbrs` lists a neighbour once per order of the bond to
+// it, which suits valence arithmetic.  Use this method, instead, when
+// each neighbour should be visited only once.
+func (a *_Atom) distinctNeighbours() []uint16 {
+	mol := a.mol
+
+	ids := make([]uint16, 0, a.bonds.Count())
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		ids = append(ids, mol.bondWithId(uint16(bid)).otherAtomIid(a.iId))
+	}
+
+	return ids
+}
+
 // addBond adds the given bond to this atom, if it is not already
 // present.  It also adjusts the list of its neighbours appropriately.
 //
@@ -296,7 +682,7 @@ func (a *_Atom) addBond(b *_Bond) {
 
 	a.bonds.Set(uint(b.id))
 	nbrId := b.otherAtomIid(a.iId)
-	n := int(b.bType)
+	n := b.order()
 	for i := 0; i < n; i++ {
 		a.nbrs = append(a.nbrs, nbrId)
 	}
@@ -316,10 +702,19 @@ func (a *_Atom) addBond(b *_Bond) {
 //
 // Note that it does NOT check to see if the removal conforms to this
 // atom's current valence configuration.
+//
+// The bond is ignored if this atom does not have it.  Exactly as many
+// neighbour entries as the bond order are removed, mirroring
+// `addBond`.
 func (a *_Atom) removeBond(b *_Bond) {
+	if !a.bonds.Test(uint(b.id)) {
+		return
+	}
+
 	nbrId := b.otherAtomIid(a.iId)
+	n := b.order()
 
-	switch b.bType {
+	switch n {
 	case 1:
 		a.singleBondCount--
 	case 2:
@@ -330,7 +725,8 @@ func (a *_Atom) removeBond(b *_Bond) {
 
 	wid := 0
 	for _, nid := range a.nbrs {
-		if nid == nbrId {
+		if nid == nbrId && n > 0 {
+			n--
 			continue
 		}
 		a.nbrs[wid] = nid
@@ -356,7 +752,9 @@ func (a *_Atom) bondTo(other uint16) *_Bond {
 }
 
 // firstDoublyBondedNeighbourId answers this atom's doubly-bonded
-// neighbour having the highest priority.
+// neighbour having the highest priority.  Answers `0` and `nil` if no
+// double bond is found among the bonds of this atom, whatever its
+// double bond count says.
 //
 // This method assumes that the molecule is already normalised!
 // Calling it on a molecule that has not be normalised yet, leads to
@@ -374,7 +772,7 @@ func (a *_Atom) firstDoublyBondedNeighbourId() (uint16, *_Bond) {
 		}
 	}
 
-	panic("Should never be here!")
+	return 0, nil
 }
 
 // firstMultiplyBondedNeighbourId answers this atom's doubly-bonded
@@ -445,6 +843,7 @@ func (a *_Atom) smallestRing() (uint8, error) {
 		if size == min {
 			c++
 		} else if size < min {
+			min = size
 			ret = uint8(rid)
 			c = 1
 		}
@@ -541,6 +940,29 @@ func (a *_Atom) addFeature(fid uint16) {
 	a.features = append(a.features, fid)
 }
 
+// sortFeaturesByPriority orders the features of this atom in descending
+// order of importance, as given by `cmn.Priority`.  The relative
+// order of repeated features is immaterial.
+func (a *_Atom) sortFeaturesByPriority() {
+	sort.Sort(_FeaturesByPriority(a.features))
+}
+
+// _FeaturesByPriority sorts features in descending order of
+// importance.
+type _FeaturesByPriority []uint16
+
+func (fs _FeaturesByPriority) Len() int {
+	return len(fs)
+}
+
+func (fs _FeaturesByPriority) Swap(i, j int) {
+	fs[i], fs[j] = fs[j], fs[i]
+}
+
+func (fs _FeaturesByPriority) Less(i, j int) bool {
+	return cmn.Priority(fs[i]) < cmn.Priority(fs[j])
+}
+
 // removeFeature removes the first instance of the given feature from
 // this atom's list of features, if it exists in it.
 //
@@ -747,11 +1169,21 @@ func (a *_Atom) isTrivalentN() bool {
 	return a.atNum == 7 && a.valence == 3
 }
 
+// donorMinElecNegativity is the least electronegativity of an atom
+// that can donate its lone pair.  The heavier members of groups 15 and
+// 16 - antimony, bismuth and polonium - fall below it; their lone
+// pairs are held too inertly to be donated.
+const donorMinElecNegativity = 2.1
+
 // isElectronDonating answers if this atom can donate one or more
 // electrons.
 //
 // An atom can donate electrons if it is saturated with its natural
-// valence, and has no electron-withdrawing neighbours.
+// valence, has no electron-withdrawing neighbours, and has a lone
+// pair to donate.  Only the elements of groups 15 and 16 that are
+// sufficiently electronegative qualify; the halogens, which also have
+// lone pairs, are electron-withdrawing instead.  See
+// `isElectronWithdrawingAcross`.
 //
 // TODO(js): Verify this method's authenticity.
 func (a *_Atom) isElectronDonating() bool {
@@ -760,27 +1192,139 @@ func (a *_Atom) isElectronDonating() bool {
 		return false
 	}
 
-	switch a.atNum {
-	case 7, 15:
-		return a.bonds.Count() <= 3
-	case 8, 16:
-		return a.bonds.Count() <= 2
+	if cmn.ElecNegativity(a.atNum) < donorMinElecNegativity {
+		return false
+	}
+
+	switch g := cmn.GroupOf(a.atNum); g {
+	case 15, 16:
+		// An atom with as many bonds as its group's valence still
+		// has a lone pair.
+		return int(a.bonds.Count()) <= 18-g
 	}
 
 	return false
 }
 
-// isHalogen answers if this atom is one of fluorine, chlorine,
-// bromine or iodine.
-func (a *_Atom) isHalogen() bool {
-	switch a.atNum {
-	case 9, 17, 35, 53:
-		return true
+// lonePairCount answers the number of lone pairs of this atom: its
+// valence electrons, less those taken up by its bonds, hydrogen atoms,
+// charge and radical state, in pairs.  Answers `0` for elements outside
+// the p-block.
+func (a *_Atom) lonePairCount() int {
+	g := cmn.GroupOf(a.atNum)
+	if g < 13 {
+		return 0
+	}
+
+	free := g - 10 - a.formalCharge() - len(a.nbrs) - int(a.hCount) - a.radicalElectronCount()
+	if free <= 0 {
+		return 0
+	}
+	return free / 2
+}
+
+// hybridization answers the hybridization of this atom, as determined
+// by its steric number: the number of atoms bonded to it, including
+// the attached hydrogen atoms, together with its lone pairs.  Steric
+// numbers of 2, 3 and 4 correspond to sp, sp2 and sp3, respectively.
+//
+// Atoms in aromatic rings are sp2, regardless.  So are pnictogens and
+// chalcogens whose lone pair is conjugated with a neighbouring multiple
+// bond or aromatic ring, such as the nitrogen of an amide or of
+// aniline.
+//
+// Hydrogen, elements outside the p-block and hypervalent atoms - those
+// with a steric number over 4 - answer `HybridizationNone`.
+// Aromaticity must have been determined - usually by normalising the
+// molecule - before this method is invoked.
+func (a *_Atom) hybridization() cmn.Hybridization {
+	if g := cmn.GroupOf(a.atNum); g < 13 || g == 18 {
+		return cmn.HybridizationNone
+	}
+	if a.isAromatic() {
+		return cmn.HybridizationSp2
+	}
+
+	lp := a.lonePairCount()
+	switch int(a.bonds.Count()) + int(a.hCount) + lp {
+	case 2:
+		return cmn.HybridizationSp
+	case 3:
+		return cmn.HybridizationSp2
+	case 4:
+		if g := cmn.GroupOf(a.atNum); (g == 15 || g == 16) && lp > 0 && a.isConjugatedWithNeighbour() {
+			return cmn.HybridizationSp2
+		}
+		return cmn.HybridizationSp3
+	}
+
+	return cmn.HybridizationNone
+}
+
+// isConjugatedWithNeighbour answers if any neighbour of this singly-
+// bonded atom has a multiple bond, or is aromatic.
+func (a *_Atom) isConjugatedWithNeighbour() bool {
+	if a.doubleBondCount > 0 || a.tripleBondCount > 0 {
+		return false
+	}
+
+	mol := a.mol
+	for _, nid := range a.distinctNeighbours() {
+		oa := mol.atomWithIid(nid)
+		if oa.isAromatic() || oa.doubleBondCount > 0 || oa.tripleBondCount > 0 {
+			return true
+		}
 	}
 
 	return false
 }
 
+// isPlainH answers if this atom is a hydrogen of natural isotopic
+// composition.  Such hydrogen atoms are folded into the hydrogen
+// counts of their neighbours, rather than being bonded to them.
+// Isotopic hydrogen atoms - deuterium, for instance - are retained as
+// atoms, so that they are not lost.
+func (a *_Atom) isPlainH() bool {
+	return a.atNum == 1 && a.isotope == 0
+}
+
+// mass answers the mass of this atom, excluding its attached hydrogen
+// atoms.  The average atomic weight of its element is used, unless
+// `mono` is `true`, in which case the mass of the most abundant
+// isotope is used.  The mass of a specific isotope, when set, is used
+// in either case.
+func (a *_Atom) mass(mono bool) float64 {
+	switch {
+	case a.isotope != 0:
+		return cmn.IsotopeMass(a.atNum, int(a.isotope))
+	case mono:
+		return cmn.MonoisotopicMass(a.atNum)
+	}
+
+	return cmn.PeriodicTable[cmn.ElementSymbols[a.atNum]].Weight
+}
+
+// heavyDegree answers the number of non-hydrogen atoms bonded to this
+// atom.
+func (a *_Atom) heavyDegree() int {
+	mol := a.mol
+
+	c := 0
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		oa := mol.atomWithIid(mol.bondWithId(uint16(bid)).otherAtomIid(a.iId))
+		if oa.atNum != 1 {
+			c++
+		}
+	}
+
+	return c
+}
+
+// isHalogen answers if this atom is a halogen.  See `cmn.IsHalogen`.
+func (a *_Atom) isHalogen() bool {
+	return cmn.IsHalogen(a.atNum)
+}
+
 // isNH2orOHorSH answers if this atom is a nitrogen with two attached
 // hydrogen atoms, or an oxygen with one attached hydrogen atom, or a
 // sulfur with one attached hydrogen atom.
@@ -798,3 +1342,200 @@ func (a *_Atom) isNH2orOHorSH() bool {
 
 	return false
 }
+
+// Atom is a read-only view of an atom in a molecule.  It is the form
+// in which atoms are made available outside this package.
+type Atom struct {
+	a *_Atom
+}
+
+// AtomWithIid answers a view of the atom with the given input ID, if
+// one such exists.
+func (m *Molecule) AtomWithIid(id uint16) (Atom, bool) {
+	a := m.atomWithIid(id)
+	if a == nil {
+		return Atom{}, false
+	}
+
+	return Atom{a}, true
+}
+
+// InputId answers the serial input ID of this atom.
+func (atom Atom) InputId() uint16 {
+	return atom.a.iId
+}
+
+// NormalisedId answers the normalised ID of this atom.  It is `0` if
+// the molecule has not been normalised yet.
+func (atom Atom) NormalisedId() uint16 {
+	return atom.a.nId
+}
+
+// AtomicNumber answers the atomic number of this atom's element.
+func (atom Atom) AtomicNumber() uint8 {
+	return atom.a.atNum
+}
+
+// Symbol answers the chemical symbol of this atom.
+func (atom Atom) Symbol() string {
+	return atom.a.symbol
+}
+
+// Charge answers the residual charge on this atom.
+func (atom Atom) Charge() int8 {
+	return atom.a.charge
+}
+
+// HydrogenCount answers the number of hydrogen atoms attached to this
+// atom.
+func (atom Atom) HydrogenCount() uint8 {
+	return atom.a.hCount
+}
+
+// Coordinates answers the X-, Y- and Z-coordinates of this atom.
+func (atom Atom) Coordinates() (float32, float32, float32) {
+	return atom.a.X, atom.a.Y, atom.a.Z
+}
+
+// IsAromatic answers if this atom is part of an aromatic ring.
+func (atom Atom) IsAromatic() bool {
+	return atom.a.isAromatic()
+}
+
+// IsCyclic answers if this atom participates in at least one ring.
+func (atom Atom) IsCyclic() bool {
+	return atom.a.isCyclic()
+}
+
+// StereoConfig answers the tetrahedral configuration of this atom, if
+// it is a stereo centre.  Answers `StereoParityNone` otherwise.
+func (atom Atom) StereoConfig() cmn.StereoParity {
+	return atom.a.stereoParity
+}
+
+// CIPLabel answers the CIP descriptor of this atom, if it is a stereo
+// centre whose descriptor has been assigned.  Answers `CIPLabelNone`
+// otherwise.  See `Molecule.AssignCIP`.
+func (atom Atom) CIPLabel() cmn.CIPLabel {
+	return atom.a.cipLabel
+}
+
+// PrimaryFunctionalGroup answers the most important functional group
+// substituted on this atom, as a `cmn.FeatureKind`.  Answers `0` if
+// no functional group is substituted on it.
+//
+// Functional groups are detected when the molecule is normalised.
+func (atom Atom) PrimaryFunctionalGroup() uint16 {
+	return atom.a.functionalGroup()
+}
+
+// FunctionalGroups answers the functional groups substituted on this
+// atom, as `cmn.FeatureKind`s, in descending order of importance.  A
+// group substituted more than once is repeated.  Answers an empty
+// list if no functional group is substituted on it.
+//
+// Functional groups are detected when the molecule is normalised.
+func (atom Atom) FunctionalGroups() []uint16 {
+	return append(make([]uint16, 0, len(atom.a.features)), atom.a.features...)
+}
+
+// AmideClass answers the class of this atom, if it is an amide
+// nitrogen.  Answers `AmideClassNone` otherwise.
+func (atom Atom) AmideClass() cmn.AmideClass {
+	return atom.a.amideClass()
+}
+
+// Hybridization answers the hybridization of this atom.  Answers
+// `HybridizationNone` for hydrogen, elements outside the p-block and
+// hypervalent atoms.
+//
+// Aromaticity is taken into account only when the molecule has been
+// normalised.
+func (atom Atom) Hybridization() cmn.Hybridization {
+	return atom.a.hybridization()
+}
+
+// Degree answers the number of atoms bonded to this atom, irrespective
+// of the orders of the bonds.  Attached hydrogen atoms are not
+// counted.
+func (atom Atom) Degree() int {
+	return int(atom.a.bonds.Count())
+}
+
+// HeavyDegree answers the number of non-hydrogen atoms bonded to this
+// atom.
+func (atom Atom) HeavyDegree() int {
+	return atom.a.heavyDegree()
+}
+
+// TotalConnections answers the number of atoms bonded to this atom,
+// including the attached hydrogen atoms.
+func (atom Atom) TotalConnections() int {
+	return atom.Degree() + int(atom.a.hCount)
+}
+
+// Radical answers the radical configuration of this atom.
+func (atom Atom) Radical() cmn.Radical {
+	return atom.a.radical
+}
+
+// MapNumber answers the atom-atom mapping number of this atom in a
+// reaction.  Answers `0` if this atom is unmapped.
+func (atom Atom) MapNumber() uint16 {
+	return atom.a.mapNum
+}
+
+// Atoms answers views of the atoms of this molecule, in the order in
+// which they are held.
+func (m *Molecule) Atoms() []Atom {
+	atoms := make([]Atom, len(m.atoms))
+	for i, a := range m.atoms {
+		atoms[i] = Atom{a}
+	}
+
+	return atoms
+}
+
+// SelectAtoms answers the input IDs of the atoms of this molecule that
+// satisfy the given predicate, in the order in which they are held.
+// See package `query` for predicates that can be combined.
+//
+// Stale rings and aromaticity are determined afresh, before the
+// predicate is applied; `nil` is answered if that fails.
+func (m *Molecule) SelectAtoms(pred func(Atom) bool) []uint16 {
+	if m.refresh(stageAromaticity) != nil {
+		return nil
+	}
+
+	ids := make([]uint16, 0, cmn.ListSizeSmall)
+	for _, a := range m.atoms {
+		if pred(Atom{a}) {
+			ids = append(ids, a.iId)
+		}
+	}
+
+	return ids
+}
+
+// NeighbourInfo describes an atom bonded to a given atom, together
+// with the order of the bond between them.
+type NeighbourInfo struct {
+	AtomId    uint16       // Input ID of the neighbouring atom.
+	BondOrder cmn.BondType // Order of the bond to the neighbour.
+}
+
+// Neighbours answers the atoms bonded to this atom, each listed once,
+// with the orders of the bonds to them.  Attached hydrogen atoms are
+// not included.
+func (atom Atom) Neighbours() []NeighbourInfo {
+	a := atom.a
+	mol := a.mol
+
+	ns := make([]NeighbourInfo, 0, a.bonds.Count())
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		b := mol.bondWithId(uint16(bid))
+		ns = append(ns, NeighbourInfo{b.otherAtomIid(a.iId), b.bType})
+	}
+
+	return ns
+}
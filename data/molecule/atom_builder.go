@@ -2,6 +2,7 @@ package molecule
 
 import (
 	"fmt"
+	"math"
 
 	cmn "github.com/RxnWeaver/RxnWeaver/common"
 )
@@ -15,9 +16,14 @@ import (
 // is to send it to a molecule for inclusion in it.
 //
 // An instance of builder can create and build any number of atoms.
+//
+// Setters that are given an invalid value record the failure, and
+// leave the property unchanged; `Build` then answers the error, and
+// discards the atom.
 type AtomBuilder struct {
 	mol *Molecule // Molecule whose atoms this builder constructs.
 	a   *_Atom    // Atom being built by this builder.
+	err error     // First failure in building the current atom.
 }
 
 // New creates a new atom instance in this builder.
@@ -34,9 +40,28 @@ func (ab *AtomBuilder) New(sym string, iId int) (*AtomBuilder, error) {
 	// The molecule, in which this atom gets eventually included,
 	// should set itself as the containing molecule.
 	ab.a = newAtom(ab.mol, el.Number, iId)
+	ab.err = nil
+
+	// Symbols such as `D` and `C_13` denote specific isotopes.
+	if el.Number > 0 && el.Symbol != cmn.ElementSymbols[el.Number] {
+		ab.a.isotope = uint16(math.Floor(el.Weight + 0.5))
+	}
 	return ab, nil
 }
 
+// Isotope sets the mass number of this atom, marking it as a specific
+// isotope of its element.  A non-positive mass number resets it to the
+// natural isotopic composition.
+func (ab *AtomBuilder) Isotope(massNumber int) *AtomBuilder {
+	if massNumber > 0 {
+		ab.a.isotope = uint16(massNumber)
+	} else {
+		ab.a.isotope = 0
+	}
+
+	return ab
+}
+
 // Coordinates sets the given coordinates as the X-, Y- and
 // Z-coordinates of this atom.
 func (ab *AtomBuilder) Coordinates(x, y, z float32) *AtomBuilder {
@@ -47,25 +72,70 @@ func (ab *AtomBuilder) Coordinates(x, y, z float32) *AtomBuilder {
 	return ab
 }
 
-// Charge sets the residual charge on this atom.
-func (ab *AtomBuilder) Charge(ch int) *AtomBuilder {
-	switch ch {
-	case 1:
-		ab.a.charge = int8(3)
-	case 2:
-		ab.a.charge = int8(2)
-	case 3:
-		ab.a.charge = int8(1)
-	case 4:
-		ab.a.radical = cmn.RadicalDoublet
-	case 5:
-		ab.a.charge = int8(-1)
-	case 6:
-		ab.a.charge = int8(-2)
-	case 7:
-		ab.a.charge = int8(-3)
-	default:
-		ab.a.charge = int8(0)
+// Charge sets the residual charge on this atom.  It should be in the
+// range -3 to +3; any other value makes `Build` fail.
+func (ab *AtomBuilder) Charge(netCharge int) *AtomBuilder {
+	if err := checkCharge(netCharge); err != nil {
+		if ab.err == nil {
+			ab.err = err
+		}
+		return ab
+	}
+
+	ab.a.charge = int8(netCharge)
+	return ab
+}
+
+// checkCharge answers an error if the given residual charge is outside
+// the range -3 to +3, which atoms can carry.
+func checkCharge(ch int) error {
+	if ch < -3 || ch > 3 {
+		return fmt.Errorf("Invalid charge : %d", ch)
+	}
+	return nil
+}
+
+// Radical sets the radical configuration of this atom.
+func (ab *AtomBuilder) Radical(r cmn.Radical) *AtomBuilder {
+	ab.a.radical = r
+	return ab
+}
+
+// ChargeCode sets the residual charge or the radical state of this
+// atom, given its MDL molfile charge code, as follows.
+//
+//	0 : uncharged
+//	1 : +3
+//	2 : +2
+//	3 : +1
+//	4 : doublet radical; uncharged
+//	5 : -1
+//	6 : -2
+//	7 : -3
+//
+// Any other code is treated as `0`.  Note that the codes run opposite
+// to the charges, and that code `4` sets a radical, not a charge.
+//
+// Deprecated: Use `Charge` and `Radical`, which can also express a
+// charged radical.
+func (ab *AtomBuilder) ChargeCode(code int) *AtomBuilder {
+	ch, r := mdlChargeFromCode(code)
+	ab.Charge(ch)
+	if r != cmn.RadicalNone {
+		ab.Radical(r)
+	}
+
+	return ab
+}
+
+// MapNumber sets the atom-atom mapping number of this atom, relating
+// it to an atom on the other side of a reaction.  A non-positive
+// number marks the atom as unmapped.
+func (ab *AtomBuilder) MapNumber(n int) *AtomBuilder {
+	if n > 0 && n <= math.MaxUint16 {
+		ab.a.mapNum = uint16(n)
+	} else {
+		ab.a.mapNum = 0
 	}
 
 	return ab
@@ -79,3 +149,28 @@ func (ab *AtomBuilder) Valence(v int) *AtomBuilder {
 
 	return ab
 }
+
+// Build adds the atom being built to the molecule.  Answers the input
+// ID of the atom.
+//
+// `New` should have been called, before this method is invoked.  Once
+// built, the atom is released by this builder; `New` should be called
+// again for the next atom.  Answers the first failure recorded by a
+// setter, if any, without adding the atom.
+func (ab *AtomBuilder) Build() (uint16, error) {
+	a := ab.a
+	if a == nil {
+		return 0, fmt.Errorf("No atom is being built.")
+	}
+	if err := ab.err; err != nil {
+		ab.a, ab.err = nil, nil
+		return 0, err
+	}
+
+	if err := ab.mol.addAtom(a); err != nil {
+		return 0, err
+	}
+
+	ab.a = nil
+	return a.iId, nil
+}
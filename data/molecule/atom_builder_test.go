@@ -0,0 +1,121 @@
+package molecule
+
+import (
+	"testing"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+func TestAtomBuilderRejectsCharge(t *testing.T) {
+	m := New()
+	defer m.discard()
+
+	ab := m.NewAtomBuilder()
+	if _, err := ab.New("N", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ab.Charge(4).Build(); err == nil {
+		t.Fatal("atom with charge +4 built")
+	}
+	if len(m.atoms) != 0 {
+		t.Errorf("molecule has %d atoms; want 0", len(m.atoms))
+	}
+
+	// The failure does not carry over to the next atom.
+	if _, err := ab.New("N", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ab.Charge(1).Build(); err != nil {
+		t.Fatal(err)
+	}
+	if ch := m.atomWithIid(1).charge; ch != 1 {
+		t.Errorf("charge %d; want 1", ch)
+	}
+}
+
+// TestAtomBuilderEthanol builds ethanol atom by atom, with the atom
+// and bond builders, and checks that the result is a usable molecule.
+func TestAtomBuilderEthanol(t *testing.T) {
+	m := New()
+	defer m.discard()
+
+	ab := m.NewAtomBuilder()
+	if _, err := ab.Build(); err == nil {
+		t.Fatal("atom built without `New`")
+	}
+	coords := [][3]float32{{0, 0, 0}, {1.3, 0.75, 0}, {2.6, 0, 0}}
+	for i, sym := range []string{"C", "C", "O"} {
+		if _, err := ab.New(sym, i+1); err != nil {
+			t.Fatal(err)
+		}
+		c := coords[i]
+		iId, err := ab.Coordinates(c[0], c[1], c[2]).Charge(0).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if iId != uint16(i+1) {
+			t.Errorf("atom input ID %d; want %d", iId, i+1)
+		}
+	}
+
+	bb := m.NewBondBuilder()
+	for i, p := range [][2]int{{1, 2}, {2, 3}} {
+		if _, err := bb.New(i + 1); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := bb.Atoms(p[0], p[1]); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := bb.BondType(cmn.BondTypeSingle); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := bb.Build(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := m.InferHydrogenCounts(cmn.HydrogenPolicyFill); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Normalise(); err != nil {
+		t.Fatal(err)
+	}
+	if f := m.Formula(); f != "C2H6O" {
+		t.Errorf("formula %s; want C2H6O", f)
+	}
+	if got, want := hCounts(m), []int{3, 2, 1}; !equalInts(got, want) {
+		t.Errorf("hydrogen counts %v; want %v", got, want)
+	}
+}
+
+func TestAtomBuilderChargeCode(t *testing.T) {
+	cases := []struct {
+		code    int
+		charge  int8
+		radical cmn.Radical
+	}{
+		{0, 0, cmn.RadicalNone},
+		{1, 3, cmn.RadicalNone},
+		{2, 2, cmn.RadicalNone},
+		{3, 1, cmn.RadicalNone},
+		{4, 0, cmn.RadicalDoublet},
+		{5, -1, cmn.RadicalNone},
+		{6, -2, cmn.RadicalNone},
+		{7, -3, cmn.RadicalNone},
+		{8, 0, cmn.RadicalNone},
+		{-1, 0, cmn.RadicalNone},
+	}
+
+	m := New()
+	defer m.discard()
+	ab := m.NewAtomBuilder()
+	for _, c := range cases {
+		if _, err := ab.New("C", 1); err != nil {
+			t.Fatal(err)
+		}
+		ab.ChargeCode(c.code)
+		if ab.a.charge != c.charge || ab.a.radical != c.radical {
+			t.Errorf("code %d : charge %d, radical %v; want %d, %v", c.code, ab.a.charge, ab.a.radical, c.charge, c.radical)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package molecule
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AtomPairs answers the atom-pair descriptor of this molecule, in the
+// manner of Carhart et al.: the number of occurrences of each pair of
+// atom types at each topological distance.  See `pairType` for the
+// typing of atoms.
+//
+// Each key is of the form `type1-distance-type2`, with the smaller of
+// the two types first, so that a pair is counted under the same key
+// irrespective of the order of its atoms.  Pairs of atoms in
+// disconnected components are not counted.
+//
+// This molecule should have been normalised; `nil` is answered
+// otherwise.
+func (m *Molecule) AtomPairs() map[string]int {
+	if !m.isNormalised {
+		return nil
+	}
+
+	types := make(map[uint16]string, len(m.atoms))
+	for _, a := range m.atoms {
+		types[a.iId] = a.pairType()
+	}
+
+	pairs := make(map[string]int)
+	for i, a1 := range m.atoms {
+		for _, a2 := range m.atoms[i+1:] {
+			d := m.distanceBetween(a1, a2)
+			if d <= 0 {
+				continue
+			}
+
+			t1, t2 := types[a1.iId], types[a2.iId]
+			if t1 > t2 {
+				t1, t2 = t2, t1
+			}
+			pairs[fmt.Sprintf("%s-%d-%s", t1, d, t2)]++
+		}
+	}
+
+	return pairs
+}
+
+// TopologicalTorsions answers the topological torsion descriptor of
+// this molecule, in the manner of Nilakantan et al.: the number of
+// occurrences of each sequence of atom types along paths of four atoms
+// - three bonds.  See `pairType` for the typing of atoms.
+//
+// Each key lists the types of the atoms along the path, separated by
+// `-`.  A path can be read in either direction; the lexically smaller
+// reading is used, so that each path is counted once, under the same
+// key.
+//
+// This molecule should have been normalised; `nil` is answered
+// otherwise.
+func (m *Molecule) TopologicalTorsions() map[string]int {
+	if !m.isNormalised {
+		return nil
+	}
+
+	types := make(map[uint16]string, len(m.atoms))
+	for _, a := range m.atoms {
+		types[a.iId] = a.pairType()
+	}
+
+	// Each path is visited once, through its central bond.
+	tors := make(map[string]int)
+	for _, b := range m.bonds {
+		a2, a3 := m.atomWithIid(b.a1), m.atomWithIid(b.a2)
+		for _, id1 := range a2.distinctNeighbours() {
+			if id1 == a3.iId {
+				continue
+			}
+			for _, id4 := range a3.distinctNeighbours() {
+				if id4 == a2.iId || id4 == id1 {
+					continue
+				}
+
+				fwd := strings.Join([]string{types[id1], types[a2.iId], types[a3.iId], types[id4]}, "-")
+				rev := strings.Join([]string{types[id4], types[a3.iId], types[a2.iId], types[id1]}, "-")
+				if rev < fwd {
+					fwd = rev
+				}
+				tors[fwd]++
+			}
+		}
+	}
+
+	return tors
+}
+
+// pairType answers the type of this atom, as used in atom-pair and
+// topological torsion descriptors.  It comprises the symbol of the
+// element - in lowercase, if the atom is aromatic - and the number of
+// atoms bonded to this atom, e.g. `cD3` or `OD1`.
+func (a *_Atom) pairType() string {
+	sym := a.symbol
+	if a.isAromatic() {
+		sym = strings.ToLower(sym)
+	}
+
+	return fmt.Sprintf("%sD%d", sym, a.bonds.Count())
+}
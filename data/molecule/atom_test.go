@@ -0,0 +1,366 @@
+package molecule
+
+import (
+	"testing"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// TestBondLookupUsesBondIds checks that the bonds of an atom are
+// looked up by their own IDs - the bit indices of the atom's bond set
+// - rather than by any neighbouring ID.
+func TestBondLookupUsesBondIds(t *testing.T) {
+	m := New()
+	defer m.discard()
+
+	// Acetaldehyde, with bond IDs that are neither dense nor start
+	// at 1.
+	for i, atNum := range []uint8{6, 6, 8} {
+		if err := m.addAtom(newAtom(m, atNum, i+1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bonds := []struct {
+		id     int
+		a1, a2 uint16
+		bType  cmn.BondType
+	}{
+		{5, 1, 2, cmn.BondTypeSingle},
+		{7, 2, 3, cmn.BondTypeDouble},
+	}
+	for _, bd := range bonds {
+		b := newBond(m, bd.id)
+		b.a1, b.a2, b.bType = bd.a1, bd.a2, bd.bType
+		if err := m.addBond(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c1, c2, o := m.atomWithIid(1), m.atomWithIid(2), m.atomWithIid(3)
+	if b := c1.bondTo(2); b == nil || b.id != 5 {
+		t.Errorf("bond C1-C2 is %v; want bond 5", b)
+	}
+	if b := o.bondTo(2); b == nil || b.id != 7 {
+		t.Errorf("bond O-C2 is %v; want bond 7", b)
+	}
+	if b := c1.bondTo(3); b != nil {
+		t.Errorf("bond C1-O is %v; want none", b)
+	}
+
+	if nid, b := c2.firstDoublyBondedNeighbourId(); nid != 3 || b == nil || b.id != 7 {
+		t.Errorf("double bond of C2 is %v, to atom %d; want bond 7, to atom 3", b, nid)
+	}
+	if nid, b := o.firstDoublyBondedNeighbourId(); nid != 2 || b == nil || b.id != 7 {
+		t.Errorf("double bond of O is %v, to atom %d; want bond 7, to atom 2", b, nid)
+	}
+	if nid, b := c1.firstDoublyBondedNeighbourId(); nid != 0 || b != nil {
+		t.Errorf("double bond of C1 is %v, to atom %d; want none", b, nid)
+	}
+}
+
+// TestRemoveDoubleBond adds a double bond to an atom, and removes it again.
+// The atom's neighbours and bond counts should be as before.
+func TestRemoveDoubleBond(t *testing.T) {
+	m := New()
+	defer m.discard()
+
+	for i := 1; i <= 3; i++ {
+		if err := m.addAtom(newAtom(m, 6, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b1 := newBond(m, 1)
+	b1.a1, b1.a2, b1.bType = 1, 2, cmn.BondTypeSingle
+	b2 := newBond(m, 2)
+	b2.a1, b2.a2, b2.bType = 2, 3, cmn.BondTypeDouble
+	for _, b := range []*_Bond{b1, b2} {
+		if err := m.addBond(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c2 := m.atomWithIid(2)
+	if !equalIds(c2.nbrs, []uint16{1, 3, 3}) || c2.singleBondCount != 1 || c2.doubleBondCount != 1 {
+		t.Fatalf("C2 before removal : neighbours %v, %d single and %d double bonds; want [1 3 3], 1 and 1",
+			c2.nbrs, c2.singleBondCount, c2.doubleBondCount)
+	}
+	if err := c2.determineUnsaturation(); err != nil || c2.unsaturation != cmn.UnsaturationDoubleBondC {
+		t.Errorf("C2 before removal : unsaturation %v, error %v; want %v", c2.unsaturation, err, cmn.UnsaturationDoubleBondC)
+	}
+
+	c2.removeBond(b2)
+	if !equalIds(c2.nbrs, []uint16{1}) || c2.singleBondCount != 1 || c2.doubleBondCount != 0 {
+		t.Errorf("C2 after removal : neighbours %v, %d single and %d double bonds; want [1], 1 and 0",
+			c2.nbrs, c2.singleBondCount, c2.doubleBondCount)
+	}
+	if c2.bonds.Test(uint(b2.id)) {
+		t.Errorf("C2 after removal : still has bond %d", b2.id)
+	}
+	if err := c2.determineUnsaturation(); err != nil || c2.unsaturation != cmn.UnsaturationNone {
+		t.Errorf("C2 after removal : unsaturation %v, error %v; want %v", c2.unsaturation, err, cmn.UnsaturationNone)
+	}
+
+	// Removing a bond the atom no longer has changes nothing.
+	c2.removeBond(b2)
+	if !equalIds(c2.nbrs, []uint16{1}) || c2.singleBondCount != 1 || c2.doubleBondCount != 0 {
+		t.Errorf("C2 after second removal : neighbours %v, %d single and %d double bonds; want [1], 1 and 0",
+			c2.nbrs, c2.singleBondCount, c2.doubleBondCount)
+	}
+}
+
+// equalIds answers if the given lists of IDs are equal.
+func equalIds(a, b []uint16) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestPiElectronCountSulphur checks the pi electron count of a sulphur
+// atom whose bond counts give the weight sum of one double and two
+// single bonds.  Without a double bond to be found among its bonds,
+// the count should fall through to none, rather than fail.
+func TestPiElectronCountSulphur(t *testing.T) {
+	// Dimethyl sulphoxide.
+	m := New()
+	defer m.discard()
+
+	for i, atNum := range []uint8{6, 16, 6, 8} {
+		if err := m.addAtom(newAtom(m, atNum, i+1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bonds := []struct {
+		id     int
+		a1, a2 uint16
+		bType  cmn.BondType
+	}{
+		{1, 1, 2, cmn.BondTypeSingle},
+		{2, 2, 3, cmn.BondTypeSingle},
+		{3, 2, 4, cmn.BondTypeDouble},
+	}
+	for _, bd := range bonds {
+		b := newBond(m, bd.id)
+		b.a1, b.a2, b.bType = bd.a1, bd.a2, bd.bType
+		if err := m.addBond(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := m.atomWithIid(2)
+	if n, ok := s.piElectronCount(); n != 2 || !ok {
+		t.Errorf("S of S=O : %d pi electrons, ok %v; want 2, true", n, ok)
+	}
+
+	// With the S=O bond gone, but its count left behind, the weight
+	// sum still says 120, while only single bonds remain.
+	s.removeBond(m.bondWithId(3))
+	s.doubleBondCount = 1
+	if n, ok := s.piElectronCount(); n != 0 || !ok {
+		t.Errorf("S with single bonds only : %d pi electrons, ok %v; want 0, true", n, ok)
+	}
+}
+
+// TestRadicalValences checks that the unpaired electron of a radical
+// occupies valence: a methyl radical and a nitroxide are valid, with
+// their hydrogen atoms filled accordingly, while a radical that would
+// exceed its valence is not.
+func TestRadicalValences(t *testing.T) {
+	// Charge code `4` denotes a doublet radical.
+	const methylMolfile = `methyl
+  test
+
+  1  0  0  0  0  0  0  0  0  0999 V2000
+    0.0000    0.0000    0.0000 C   0  4  0  0  0  0  0  0  0  0  0  0
+M  END`
+	const nitroxideMolfile = `dimethyl nitroxide
+  test
+
+  4  3  0  0  0  0  0  0  0  0999 V2000
+    0.0000    0.0000    0.0000 C   0  0  0  0  0  0  0  0  0  0  0  0
+    1.2990    0.7500    0.0000 N   0  0  0  0  0  0  0  0  0  0  0  0
+    2.5981    0.0000    0.0000 C   0  0  0  0  0  0  0  0  0  0  0  0
+    1.2990    2.2500    0.0000 O   0  4  0  0  0  0  0  0  0  0  0  0
+  1  2  1  0
+  2  3  1  0
+  2  4  1  0
+M  END`
+
+	cases := []struct {
+		name    string
+		molfile string
+		hs      []int
+		radical uint16 // Radical atom.
+	}{
+		{"methyl", methylMolfile, []int{3}, 1},
+		{"dimethyl nitroxide", nitroxideMolfile, []int{3, 0, 3, 0}, 4},
+	}
+	for _, c := range cases {
+		m, _, err := ParseMolfile(molfileLines(c.molfile), 1)
+		if err != nil {
+			t.Errorf("%s : %v", c.name, err)
+			continue
+		}
+		if hs := hCounts(m); !equalInts(hs, c.hs) {
+			t.Errorf("%s : hydrogen counts %v; want %v", c.name, hs, c.hs)
+		}
+		if a, _ := m.AtomWithIid(c.radical); a.Radical() != cmn.RadicalDoublet {
+			t.Errorf("%s : atom %d is %v; want a doublet radical", c.name, c.radical, a.Radical())
+		}
+		if ids := m.ChargeInconsistentAtoms(); len(ids) != 0 {
+			t.Errorf("%s : atoms %v are inconsistent with their charges", c.name, ids)
+		}
+		m.discard()
+	}
+
+	// A methane radical has one electron too many for carbon.
+	m := New()
+	defer m.discard()
+	a := newAtom(m, 6, 1)
+	a.hCount, a.radical = 4, cmn.RadicalDoublet
+	if err := m.addAtom(a); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.determineUnsaturation(); err == nil {
+		t.Error("CH4 radical : valence accepted; want an error")
+	}
+}
+
+// TestUnsaturationOfHydrocarbons checks the unsaturation of the carbon
+// atoms of ethane, ethylene and acetylene.  Since `nbrs` repeats a
+// neighbour per bond order, its length differs from the number of
+// bonds exactly when a multiple bond is present.
+func TestUnsaturationOfHydrocarbons(t *testing.T) {
+	cases := []struct {
+		name  string
+		order int
+		nbrs  int
+		unsat cmn.Unsaturation
+	}{
+		{"ethane", 1, 1, cmn.UnsaturationNone},
+		{"ethylene", 2, 2, cmn.UnsaturationDoubleBondC},
+		{"acetylene", 3, 3, cmn.UnsaturationTripleBondC},
+	}
+	for _, c := range cases {
+		m := buildMolecule(t, []string{"C", "C"}, testBonds([][3]int{{1, 2, c.order}}))
+		for _, id := range []uint16{1, 2} {
+			a := m.atomWithIid(id)
+			if len(a.nbrs) != c.nbrs || a.bonds.Count() != 1 {
+				t.Errorf("%s : C%d has %d neighbour entries over %d bonds; want %d over 1",
+					c.name, id, len(a.nbrs), a.bonds.Count(), c.nbrs)
+			}
+			if a.unsaturation != c.unsat {
+				t.Errorf("%s : C%d has unsaturation %v; want %v", c.name, id, a.unsaturation, c.unsat)
+			}
+		}
+		m.discard()
+	}
+
+	// An ethylene carbon atom with three hydrogen atoms exceeds its
+	// valence.
+	m := New()
+	defer m.discard()
+	for i, h := range []uint8{3, 2} {
+		a := newAtom(m, 6, i+1)
+		a.hCount = h
+		if err := m.addAtom(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b := newBond(m, 1)
+	b.a1, b.a2, b.bType = 1, 2, cmn.BondTypeDouble
+	if err := m.addBond(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.atomWithIid(1).determineUnsaturation(); err == nil {
+		t.Error("CH3=CH2 : valence accepted; want an error")
+	}
+}
+
+// TestInferHydrogenCount checks the hydrogen atoms inferred for lone
+// atoms of methane, ammonia, water and hydrogen fluoride, and for
+// charged species, including the methyl carbocation.  A carbon atom with five bonds
+// exceeds every valence of carbon.
+func TestInferHydrogenCount(t *testing.T) {
+	cases := []struct {
+		name   string
+		atNum  uint8
+		charge int8
+		hCount uint8
+	}{
+		{"methane", 6, 0, 4},
+		{"ammonia", 7, 0, 3},
+		{"water", 8, 0, 2},
+		{"methyl cation", 6, 1, 3},
+		{"ammonium", 7, 1, 4},
+		{"hydroxide", 8, -1, 1},
+		{"hydrogen fluoride", 9, 0, 1},
+	}
+	for _, c := range cases {
+		m := New()
+		a := newAtom(m, c.atNum, 1)
+		a.charge = c.charge
+		if err := m.addAtom(a); err != nil {
+			t.Fatal(err)
+		}
+		if err := a.inferHydrogenCount(cmn.HydrogenPolicyFill); err != nil || a.hCount != c.hCount {
+			t.Errorf("%s : %d hydrogen atoms, error %v; want %d", c.name, a.hCount, err, c.hCount)
+		}
+		m.discard()
+	}
+
+	m := New()
+	defer m.discard()
+	for i := 1; i <= 6; i++ {
+		if err := m.addAtom(newAtom(m, 6, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 2; i <= 6; i++ {
+		b := newBond(m, i-1)
+		b.a1, b.a2, b.bType = 1, uint16(i), cmn.BondTypeSingle
+		if err := m.addBond(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.atomWithIid(1).inferHydrogenCount(cmn.HydrogenPolicyFill); err == nil {
+		t.Error("pentavalent carbon : hydrogen atoms inferred; want an error")
+	}
+}
+
+// TestNeighbourElectronics checks the electron-withdrawing neighbours
+// of the atoms of butanone, an enolizable ketone.  Both of its alpha
+// carbon atoms bear enolic hydrogen atoms; its beta carbon does not.
+func TestNeighbourElectronics(t *testing.T) {
+	// C1 C2(=O3) C4 C5
+	m := buildMolecule(t, []string{"C", "C", "O", "C", "C"},
+		testBonds([][3]int{{1, 2, 1}, {2, 3, 2}, {2, 4, 1}, {4, 5, 1}}))
+	defer m.discard()
+
+	cases := []struct {
+		name           string
+		id             uint16
+		satEw, unsatEw int
+		enolicH        int
+	}{
+		{"alpha CH3", 1, 1, 0, 3},
+		{"carbonyl C", 2, 0, 1, 0},
+		{"alpha CH2", 4, 1, 0, 2},
+		{"beta CH3", 5, 0, 0, 0},
+	}
+	for _, c := range cases {
+		a := m.atomWithIid(c.id)
+		if a.satEwNbrCount != c.satEw || a.unsatEwNbrCount != c.unsatEw {
+			t.Errorf("%s : %d saturated and %d unsaturated electron-withdrawing neighbours; want %d and %d",
+				c.name, a.satEwNbrCount, a.unsatEwNbrCount, c.satEw, c.unsatEw)
+		}
+		if n := a.enolicHydrogenCount(); n != c.enolicH {
+			t.Errorf("%s : %d enolic hydrogen atoms; want %d", c.name, n, c.enolicH)
+		}
+	}
+}
@@ -8,3 +8,40 @@ type Attribute struct {
 	Name  string
 	Value string
 }
+
+// SetAttribute annotates this molecule with the given name and value.
+// If an attribute with the same name already exists, its value is
+// replaced with the given one.
+func (m *Molecule) SetAttribute(name, value string) {
+	for i := range m.attributes {
+		if m.attributes[i].Name == name {
+			m.attributes[i].Value = value
+			return
+		}
+	}
+
+	m.attributes = append(m.attributes, Attribute{name, value})
+}
+
+// Attribute answers the value of the attribute with the given name,
+// if this molecule has one such.
+func (m *Molecule) Attribute(name string) (string, bool) {
+	for _, attr := range m.attributes {
+		if attr.Name == name {
+			return attr.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// RemoveAttribute removes the attribute with the given name from this
+// molecule, if one such exists.
+func (m *Molecule) RemoveAttribute(name string) {
+	for i, attr := range m.attributes {
+		if attr.Name == name {
+			m.attributes = append(m.attributes[:i], m.attributes[i+1:]...)
+			return
+		}
+	}
+}
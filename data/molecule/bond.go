@@ -3,6 +3,8 @@ package molecule
 import (
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 
 	cmn "github.com/RxnWeaver/RxnWeaver/common"
 )
@@ -20,13 +22,17 @@ type _Bond struct {
 
 	a1      uint16         // iId of the first atom in the bond.
 	a2      uint16         // iId of the second atom in the bond.
-	bType   cmn.BondType   // Is this bond single, double or triple?
+	bType   cmn.BondType   // Is this bond single, double, triple or dative?
 	bStereo cmn.BondStereo // See the enum definitions for details.
 
 	isAro  bool   // Is this bond aromatic?
 	isLink bool   // Is this bond part of a linking chain?
 	hash   uint32 // For fast comparisons.
 
+	// E/Z configuration of this bond, if it is a stereogenic double
+	// bond.  See `AssignDoubleBondStereo` for the details.
+	stereoParity cmn.StereoParity
+
 	rings []uint8 // The rings this bond participates in.
 }
 
@@ -42,6 +48,70 @@ func newBond(mol *Molecule, id int) *_Bond {
 	return bond
 }
 
+// String answers a one-line, human-readable description of the state
+// of this bond, for debugging.  Atoms are shown with their elements and
+// input IDs, and the bond type by its conventional symbol.
+//
+//	B#7 C1=O2 stereo=0 aromatic=false rings={1}
+func (b *_Bond) String() string {
+	var sb strings.Builder
+	sb.Grow(64)
+
+	sb.WriteString("B#")
+	sb.WriteString(strconv.Itoa(int(b.id)))
+	sb.WriteByte(' ')
+	b.writeAtom(&sb, b.a1)
+	sb.WriteByte(bondOrderSymbol(b.bType))
+	b.writeAtom(&sb, b.a2)
+	sb.WriteString(" stereo=")
+	sb.WriteString(strconv.Itoa(int(b.bStereo)))
+	sb.WriteString(" aromatic=")
+	sb.WriteString(strconv.FormatBool(b.isAro))
+	sb.WriteString(" rings=")
+	writeIds(&sb, len(b.rings), func(i int) int { return int(b.rings[i]) })
+
+	return sb.String()
+}
+
+// writeAtom writes the element and the input ID of the given atom of
+// this bond to the given builder.  The element is omitted if the atom
+// is unknown.
+func (b *_Bond) writeAtom(sb *strings.Builder, aid uint16) {
+	if b.mol != nil {
+		if a := b.mol.atomWithIid(aid); a != nil {
+			sb.WriteString(a.symbol)
+		}
+	}
+	sb.WriteString(strconv.Itoa(int(aid)))
+}
+
+// order answers the number of bond orders this bond contributes to
+// the valence of each of its atoms.  A dative bond counts as a single
+// bond.  See `_Atom.formalCharge`.  So does an aromatic bond whose
+// order is yet to be assigned.  See `Kekulize`.
+func (b *_Bond) order() int {
+	switch b.bType {
+	case cmn.BondTypeDative, cmn.BondTypeAltern:
+		return 1
+	}
+
+	return int(b.bType)
+}
+
+// polarity answers the difference in the electronegativities of the
+// two atoms bound by this bond, as an absolute value.  Answers `0` if
+// the electronegativity of either atom is not known.
+func (b *_Bond) polarity() float64 {
+	mol := b.mol
+	en1 := cmn.ElecNegativity(mol.atomWithIid(b.a1).atNum)
+	en2 := cmn.ElecNegativity(mol.atomWithIid(b.a2).atNum)
+	if en1 == 0 || en2 == 0 {
+		return 0
+	}
+
+	return math.Abs(en1 - en2)
+}
+
 // otherAtomIid answers the atom other than the given one that
 // participates in this bond.  Answers `0` if the given atom does not
 // participate in this bond at all.
@@ -115,7 +185,42 @@ func (b *_Bond) isInRingOfSize(n int) bool {
 	return false
 }
 
-// smallestRing answers the smallest unique ring in which this atom
+// isInRingLargerThan answers if this bond participates in at least
+// one ring that is larger than the given number.
+func (b *_Bond) isInRingLargerThan(n int) bool {
+	mol := b.mol
+	for _, rid := range b.rings {
+		r := mol.ringWithId(rid)
+		if r.size() > n {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ringCount answers the number of rings in which this bond
+// participates.
+func (b *_Bond) ringCount() int {
+	return len(b.rings)
+}
+
+// smallestRingSize answers the size of the smallest ring in which this
+// bond participates.  Answers `0` if this bond is acyclic.
+func (b *_Bond) smallestRingSize() int {
+	min := 0
+
+	mol := b.mol
+	for _, rid := range b.rings {
+		if size := mol.ringWithId(rid).size(); min == 0 || size < min {
+			min = size
+		}
+	}
+
+	return min
+}
+
+// smallestRing answers the smallest unique ring in which this bond
 // participates.  If no such unique ring exists, an error is answered.
 func (b *_Bond) smallestRing() (uint8, error) {
 	if !b.isCyclic() {
@@ -133,6 +238,7 @@ func (b *_Bond) smallestRing() (uint8, error) {
 		if size == min {
 			c++
 		} else if size < min {
+			min = size
 			ret = uint8(rid)
 			c = 1
 		}
@@ -144,3 +250,83 @@ func (b *_Bond) smallestRing() (uint8, error) {
 
 	return ret, nil
 }
+
+// Bond is a read-only view of a bond in a molecule.  It is the form
+// in which bonds are made available outside this package.
+type Bond struct {
+	b *_Bond
+}
+
+// BondWithId answers a view of the bond with the given ID, if one
+// such exists.
+func (m *Molecule) BondWithId(id uint16) (Bond, bool) {
+	b := m.bondWithId(id)
+	if b == nil {
+		return Bond{}, false
+	}
+
+	return Bond{b}, true
+}
+
+// Bonds answers views of the bonds of this molecule, in the order in
+// which they are held.
+func (m *Molecule) Bonds() []Bond {
+	bonds := make([]Bond, len(m.bonds))
+	for i, b := range m.bonds {
+		bonds[i] = Bond{b}
+	}
+
+	return bonds
+}
+
+// Id answers the unique ID of this bond in its molecule.
+func (bond Bond) Id() uint16 {
+	return bond.b.id
+}
+
+// Atoms answers the input IDs of the two atoms bound by this bond.
+func (bond Bond) Atoms() (uint16, uint16) {
+	return bond.b.a1, bond.b.a2
+}
+
+// Type answers the order of this bond.
+func (bond Bond) Type() cmn.BondType {
+	return bond.b.bType
+}
+
+// Order answers the number of bond orders this bond contributes to
+// the valence of each of its atoms.  A dative bond counts as a single
+// bond, as does an aromatic bond whose order is yet to be assigned.
+func (bond Bond) Order() int {
+	return bond.b.order()
+}
+
+// Stereo answers the 2-D stereo orientation of this bond, as given in
+// the input.
+func (bond Bond) Stereo() cmn.BondStereo {
+	return bond.b.bStereo
+}
+
+// IsAromatic answers if this bond is part of an aromatic ring or ring
+// system.
+func (bond Bond) IsAromatic() bool {
+	return bond.b.isAro
+}
+
+// IsCyclic answers if this bond participates in at least one ring.
+func (bond Bond) IsCyclic() bool {
+	return bond.b.isCyclic()
+}
+
+// SmallestRingSize answers the size of the smallest ring in which this
+// bond participates.  Answers `0` if this bond is acyclic.
+func (bond Bond) SmallestRingSize() int {
+	return bond.b.smallestRingSize()
+}
+
+// DoubleBondConfig answers the E/Z configuration of this bond, if it
+// is a stereogenic double bond.  `StereoParityEven` denotes E, and
+// `StereoParityOdd` denotes Z.  Answers `StereoParityNone` otherwise.
+func (bond Bond) DoubleBondConfig() cmn.StereoParity {
+	return bond.b.stereoParity
+}
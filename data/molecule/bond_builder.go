@@ -18,6 +18,8 @@ import (
 type BondBuilder struct {
 	mol *Molecule // Molecule whose bonds this builder constructs.
 	b   *_Bond    // Bond being built by this builder.
+
+	acceptAromatic bool // Are aromatic bond types accepted?
 }
 
 // New creates a new bond instance in this builder.
@@ -46,13 +48,14 @@ func (bb *BondBuilder) Atoms(aiid1, aiid2 int) (*BondBuilder, error) {
 		return nil, fmt.Errorf("Unknown atom input ID given : %d", aiid2)
 	}
 
-	// We do not add bonds to hydrogen atoms.
-	if a1.atNum == 1 {
+	// We do not add bonds to hydrogen atoms, unless they are specific
+	// isotopes.
+	if a1.isPlainH() {
 		a2.hCount++
 		bb.b = nil
 		return bb, fmt.Errorf("Bond involves a hydrogen atom.")
 	}
-	if a2.atNum == 1 {
+	if a2.isPlainH() {
 		a1.hCount++
 		bb.b = nil
 		return bb, fmt.Errorf("Bond involves a hydrogen atom.")
@@ -63,13 +66,25 @@ func (bb *BondBuilder) Atoms(aiid1, aiid2 int) (*BondBuilder, error) {
 	return bb, nil
 }
 
+// AcceptAromatic sets whether this builder accepts aromatic bonds -
+// those of type `BondTypeAltern` - as given in input formats that
+// mark them so.  They are rejected by default.
+//
+// Aromatic bonds are built without an order.  Orders are assigned to
+// them when the molecule is normalised.  See `Kekulize`.
+func (bb *BondBuilder) AcceptAromatic(ok bool) *BondBuilder {
+	bb.acceptAromatic = ok
+	return bb
+}
+
 // BondType sets the bond order of this bond.
 func (bb *BondBuilder) BondType(bType cmn.BondType) (*BondBuilder, error) {
-	if bType == cmn.BondTypeNone || bType == cmn.BondTypeAltern {
+	if bType == cmn.BondTypeNone || (bType == cmn.BondTypeAltern && !bb.acceptAromatic) {
 		return nil, fmt.Errorf("Unhandled bond type : %v", bType)
 	}
 
 	bb.b.bType = bType
+	bb.b.isAro = bType == cmn.BondTypeAltern
 	return bb, nil
 }
 
@@ -78,3 +93,39 @@ func (bb *BondBuilder) BondStereo(bStereo cmn.BondStereo) *BondBuilder {
 	bb.b.bStereo = bStereo
 	return bb
 }
+
+// Build validates the bond being built, and adds it to the molecule.
+// The bond is also registered with both of its atoms.  Answers the ID
+// of the bond.
+//
+// The bond should have both of its atoms - which should be distinct,
+// and not already bonded - and a valid bond type set.  Once built, the
+// bond is released by this builder; `New` should be called again for
+// the next bond.
+func (bb *BondBuilder) Build() (uint16, error) {
+	b := bb.b
+	if b == nil {
+		return 0, fmt.Errorf("No bond is being built.")
+	}
+
+	switch {
+	case b.a1 == 0 || b.a2 == 0:
+		return 0, fmt.Errorf("Atoms of bond %d have not been set.", b.id)
+	case b.a1 == b.a2:
+		return 0, fmt.Errorf("Bond %d binds atom %d to itself.", b.id, b.a1)
+	case b.bType < cmn.BondTypeSingle || b.bType > cmn.BondTypeDative,
+		b.bType == cmn.BondTypeAltern && !bb.acceptAromatic:
+		return 0, fmt.Errorf("Unhandled bond type : %v", b.bType)
+	}
+
+	mol := bb.mol
+	if mol.bondBetween(b.a1, b.a2) != nil {
+		return 0, fmt.Errorf("Atoms %d and %d are already bonded.", b.a1, b.a2)
+	}
+	if err := mol.addBond(b); err != nil {
+		return 0, err
+	}
+
+	bb.b = nil
+	return b.id, nil
+}
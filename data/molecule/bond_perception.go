@@ -0,0 +1,339 @@
+package molecule
+
+import (
+	"math"
+	"sort"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// BondPerceptionTolerance is the distance, in Angstroms, by which two
+// atoms may be farther apart than the sum of their covalent radii,
+// and still be considered bonded.
+const BondPerceptionTolerance = 0.45
+
+// bondPerceptionMinDistance is the distance, in Angstroms, below which
+// two atoms are considered to overlap, rather than be bonded.
+const bondPerceptionMinDistance = 0.4
+
+// _AtomPair is a pair of atoms, together with the distance between
+// them.
+type _AtomPair struct {
+	a1, a2 *_Atom
+	dist   float64
+}
+
+// _AtomPairs sorts atom pairs in ascending order of distance.  Pairs
+// at equal distances are ordered on the input IDs of their atoms.
+type _AtomPairs []_AtomPair
+
+func (ps _AtomPairs) Len() int {
+	return len(ps)
+}
+
+func (ps _AtomPairs) Swap(i, j int) {
+	ps[i], ps[j] = ps[j], ps[i]
+}
+
+func (ps _AtomPairs) Less(i, j int) bool {
+	if ps[i].dist != ps[j].dist {
+		return ps[i].dist < ps[j].dist
+	}
+	if ps[i].a1.iId != ps[j].a1.iId {
+		return ps[i].a1.iId < ps[j].a1.iId
+	}
+	return ps[i].a2.iId < ps[j].a2.iId
+}
+
+// distanceTo answers the Euclidean distance between this atom and the
+// given atom, using their coordinates.
+func (a *_Atom) distanceTo(other *_Atom) float64 {
+	dx := float64(a.X - other.X)
+	dy := float64(a.Y - other.Y)
+	dz := float64(a.Z - other.Z)
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// PerceiveBondsFromCoordinates creates single bonds between the atoms
+// of this molecule that are close enough to be bonded, judging by
+// their 3-D coordinates.
+//
+// Two atoms are deemed bonded when the distance between them does not
+// exceed the sum of their covalent radii by more than
+// `BondPerceptionTolerance`.  Pairs are considered in ascending order
+// of distance, and no atom is given more than `cmn.MaxBonds` bonds.
+// Existing bonds are retained, and are not duplicated.
+//
+// As elsewhere, bonds to hydrogen atoms - other than specific isotopes
+// - are not created.  Instead, each hydrogen atom is accounted for in
+// the hydrogen count of its nearest bonded heavy atom, and is then
+// removed from the molecule.  The molecule therefore has fewer atoms
+// afterwards than before.  Hydrogen atoms that are not close enough to
+// any heavy atom are retained.  Pairs of hydrogen atoms are skipped.
+//
+// Answers an error if this molecule is frozen.  See `Freeze`.
+func (m *Molecule) PerceiveBondsFromCoordinates() error {
+	if err := m.checkMutable(); err != nil {
+		return err
+	}
+
+	pairs := make([]_AtomPair, 0, 4*len(m.atoms))
+	for i, a1 := range m.atoms {
+		r1 := covalentRadius(a1.atNum)
+		if r1 == 0 {
+			continue
+		}
+		for _, a2 := range m.atoms[i+1:] {
+			if a1.isPlainH() && a2.isPlainH() {
+				continue
+			}
+			l := cmn.CovalentBondLength(a1.atNum, a2.atNum)
+			if l == 0 {
+				continue
+			}
+
+			d := a1.distanceTo(a2)
+			if d < bondPerceptionMinDistance || d > l+BondPerceptionTolerance {
+				continue
+			}
+			pairs = append(pairs, _AtomPair{a1, a2, d})
+		}
+	}
+	sort.Sort(_AtomPairs(pairs))
+
+	hs := make(map[uint16]bool)
+	for _, p := range pairs {
+		a1, a2 := p.a1, p.a2
+		if a2.isPlainH() {
+			a1, a2 = a2, a1
+		}
+
+		if a1.isPlainH() {
+			if hs[a1.iId] || a2.bonds.Count()+uint(a2.hCount) >= cmn.MaxBonds {
+				continue
+			}
+			a2.hCount++
+			hs[a1.iId] = true
+			continue
+		}
+
+		if a1.bondTo(a2.iId) != nil {
+			continue
+		}
+		if a1.bonds.Count() >= cmn.MaxBonds || a2.bonds.Count() >= cmn.MaxBonds {
+			continue
+		}
+
+		b := newBond(m, int(m.nextBondId))
+		b.a1 = a1.iId
+		b.a2 = a2.iId
+		b.bType = cmn.BondTypeSingle
+		if err := m.addBond(b); err != nil {
+			return err
+		}
+	}
+
+	for hid := range hs {
+		m.removeAtom(hid)
+	}
+
+	return nil
+}
+
+// covalentRadius answers the covalent radius of the element with the
+// given atomic number, or `0` if it is not known.
+func covalentRadius(atNum uint8) float64 {
+	if int(atNum) >= len(cmn.ElementSymbols) {
+		return 0
+	}
+
+	return cmn.PeriodicTable[cmn.ElementSymbols[atNum]].CovalentRadius
+}
+
+// PerceiveBondOrders assigns bond orders to the bonds of this
+// molecule, all of which are expected to be single bonds, as created
+// by `PerceiveBondsFromCoordinates`.
+//
+// The valence deficit of each atom is the difference between its
+// expected valence and the sum of its bond orders and hydrogen count.
+// Bonds between deficient atoms are then promoted one order at a
+// time.  A bond that is the only means of satisfying the deficit of
+// one of its atoms is promoted first; otherwise, the shortest bond
+// relative to the covalent radii of its atoms is chosen.  Thus,
+// carbonyls are found before conjugated chains, and rings are
+// assigned a Kekulé structure.
+//
+// Nitro groups are written in their charge-separated form, and any
+// terminal oxygen or sulfur atom still deficient is given a negative
+// charge, as in carboxylates.  Unsaturation is recomputed at the end.
+//
+// Answers an error if this molecule is frozen.  See `Freeze`.
+func (m *Molecule) PerceiveBondOrders() error {
+	if err := m.checkMutable(); err != nil {
+		return err
+	}
+
+	defs := make(map[uint16]int, len(m.atoms))
+	for _, a := range m.atoms {
+		defs[a.iId] = a.valenceDeficit()
+	}
+
+	for {
+		b := m.nextBondToPromote(defs)
+		if b == nil {
+			break
+		}
+		m.promoteBond(b)
+		defs[b.a1]--
+		defs[b.a2]--
+	}
+
+	m.separateNitroCharges(defs)
+
+	for _, a := range m.atoms {
+		if defs[a.iId] == 1 && a.charge == 0 && a.isTerminal() && (a.atNum == 8 || a.atNum == 16) {
+			a.charge = -1
+			defs[a.iId] = 0
+		}
+	}
+
+	for _, a := range m.atoms {
+		if err := a.determineUnsaturation(); err != nil {
+			return err
+		}
+	}
+
+	m.markStale(stageAromaticity | stageHashes)
+	return nil
+}
+
+// valenceDeficit answers the number of additional bond orders this
+// atom needs, to reach its expected valence.
+//
+// The expected valence is normally that answered by `chargedValence`.
+// Neutral atoms of elements that may be hypervalent take the highest
+// of their valences that the terminal hetero atoms around them can
+// satisfy.
+func (a *_Atom) valenceDeficit() int {
+	sum := len(a.nbrs) + int(a.hCount)
+
+	target := a.chargedValence()
+	if a.formalCharge() == 0 {
+		limit := sum + a.terminalHeteroNbrCount()
+		for _, v := range hypervalentValences(a.atNum) {
+			if v >= sum && v <= limit {
+				target = v
+			}
+		}
+	}
+
+	if target <= sum {
+		return 0
+	}
+	return target - sum
+}
+
+// terminalHeteroNbrCount answers the number of neighbours of this atom
+// that are terminal hetero atoms.
+func (a *_Atom) terminalHeteroNbrCount() int {
+	mol := a.mol
+
+	c := 0
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		oa := mol.atomWithIid(mol.bondWithId(uint16(bid)).otherAtomIid(a.iId))
+		if oa.isTerminalHeteroAtom() {
+			c++
+		}
+	}
+
+	return c
+}
+
+// relativeLength answers the length of this bond, relative to the sum
+// of the covalent radii of its atoms.
+func (b *_Bond) relativeLength() float64 {
+	mol := b.mol
+	a1 := mol.atomWithIid(b.a1)
+	a2 := mol.atomWithIid(b.a2)
+
+	d := a1.distanceTo(a2)
+	if l := cmn.CovalentBondLength(a1.atNum, a2.atNum); l > 0 {
+		return d / l
+	}
+	return d
+}
+
+// nextBondToPromote answers the bond that should be promoted next,
+// given the current valence deficits of the atoms.  See
+// `PerceiveBondOrders`.  Answers `nil` if no bond can be promoted.
+func (m *Molecule) nextBondToPromote(defs map[uint16]int) *_Bond {
+	cands := make([]*_Bond, 0, len(m.bonds))
+	counts := make(map[uint16]int)
+	for _, b := range m.bonds {
+		if b.bType >= cmn.BondTypeTriple || defs[b.a1] <= 0 || defs[b.a2] <= 0 {
+			continue
+		}
+		cands = append(cands, b)
+		counts[b.a1]++
+		counts[b.a2]++
+	}
+
+	var best *_Bond
+	bestForced := false
+	bestLen := 0.0
+	for _, b := range cands {
+		forced := counts[b.a1] == 1 || counts[b.a2] == 1
+		l := b.relativeLength()
+		switch {
+		case best == nil:
+		case forced && !bestForced:
+		case forced == bestForced && l < bestLen:
+		default:
+			continue
+		}
+		best, bestForced, bestLen = b, forced, l
+	}
+
+	return best
+}
+
+// promoteBond raises the order of the given bond by one, keeping its
+// atoms consistent.
+func (m *Molecule) promoteBond(b *_Bond) {
+	m.setBondType(b, b.bType+1)
+}
+
+// separateNitroCharges rewrites each nitro group left with deficient
+// oxygen atoms in its charge-separated form: the nitrogen is given a
+// positive charge, its shortest bond to one of the oxygen atoms is
+// made double, and the other oxygen atom is given a negative charge.
+func (m *Molecule) separateNitroCharges(defs map[uint16]int) {
+	for _, a := range m.atoms {
+		if a.atNum != 7 || a.charge != 0 || defs[a.iId] != 0 || len(a.nbrs) != 3 {
+			continue
+		}
+
+		os := make([]*_Bond, 0, 2)
+		for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+			b := m.bondWithId(uint16(bid))
+			oa := m.atomWithIid(b.otherAtomIid(a.iId))
+			if oa.isTerminalO() && defs[oa.iId] == 1 {
+				os = append(os, b)
+			}
+		}
+		if len(os) != 2 {
+			continue
+		}
+		if os[1].relativeLength() < os[0].relativeLength() {
+			os[0], os[1] = os[1], os[0]
+		}
+
+		a.charge = 1
+		m.promoteBond(os[0])
+		defs[os[0].otherAtomIid(a.iId)] = 0
+
+		o2 := m.atomWithIid(os[1].otherAtomIid(a.iId))
+		o2.charge = -1
+		defs[o2.iId] = 0
+	}
+}
@@ -0,0 +1,94 @@
+package molecule
+
+import (
+	"strings"
+	"testing"
+)
+
+// perceiveXYZ reads the given XYZ molecule, perceives its bonds and
+// their orders, and normalises it.
+func perceiveXYZ(t *testing.T, src string) *Molecule {
+	xr := NewXYZReader(strings.NewReader(src))
+	xr.PerceiveBonds = true
+	m, err := xr.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.PerceiveBondOrders(); err != nil {
+		m.discard()
+		t.Fatal(err)
+	}
+	if err := m.Normalise(); err != nil {
+		m.discard()
+		t.Fatal(err)
+	}
+	return m
+}
+
+const acetoneXYZ = `10
+acetone
+C  0.000  0.000  0.000
+O  0.000  1.215  0.000
+C  1.290 -0.800  0.000
+C -1.290 -0.800  0.000
+H  2.150 -0.130  0.000
+H  1.330 -1.440  0.890
+H  1.330 -1.440 -0.890
+H -2.150 -0.130  0.000
+H -1.330 -1.440  0.890
+H -1.330 -1.440 -0.890
+`
+
+const nitromethaneXYZ = `7
+nitromethane
+C  0.000  0.000  0.000
+N  1.490  0.000  0.000
+O  2.100  1.070  0.000
+O  2.100 -1.070  0.000
+H -0.360  1.020  0.000
+H -0.360 -0.510  0.890
+H -0.360 -0.510 -0.890
+`
+
+const acetateXYZ = `7
+acetate
+C  0.000  0.000  0.000
+C  1.520  0.000  0.000
+O  2.150  1.080  0.000
+O  2.150 -1.080  0.000
+H -0.360  1.020  0.000
+H -0.360 -0.510  0.890
+H -0.360 -0.510 -0.890
+`
+
+func TestPerceiveBondOrders(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		formula string
+		doubles int
+		charges []int // Charges of the heavy atoms, in input order.
+	}{
+		{"acetone", acetoneXYZ, "C3H6O", 1, []int{0, 0, 0, 0}},
+		{"nitromethane", nitromethaneXYZ, "CH3NO2", 1, []int{0, 1, 0, -1}},
+		{"acetate", acetateXYZ, "C2H3O2", 1, []int{0, 0, 0, -1}},
+	}
+	for _, c := range cases {
+		m := perceiveXYZ(t, c.src)
+
+		if f := m.Formula(); f != c.formula {
+			t.Errorf("%s : formula %s; want %s", c.name, f, c.formula)
+		}
+		if n := m.doubleBondCount(); n != c.doubles {
+			t.Errorf("%s : %d double bonds; want %d", c.name, n, c.doubles)
+		}
+		chs := make([]int, len(m.atoms))
+		for i, a := range m.atoms {
+			chs[i] = int(a.charge)
+		}
+		if !equalInts(chs, c.charges) {
+			t.Errorf("%s : charges %v; want %v", c.name, chs, c.charges)
+		}
+		m.discard()
+	}
+}
@@ -0,0 +1,416 @@
+package molecule
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"sort"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// CanonicalKey answers a string that represents the structure of this
+// molecule independently of the order in which its atoms and bonds
+// were input.  Two molecules with the same structure have the same
+// key; hence it can be used as a map key.
+//
+// The key has two parts separated by a `/`.  The first lists the
+// atoms in the order of their normalised IDs.  Each atom is written
+// as its symbol - preceded by its mass number, if it is a specific
+// isotope, as in `13C` - followed by its hydrogen count, its residual
+// charge and an `a` if it is aromatic, as applicable.  The second part
+// lists the bonds, each as the normalised IDs of its atoms with its
+// order (`-`, `=`, `#`, or `:` if aromatic) in between, followed by
+// `r` if it is cyclic.  Aromatic bonds are thus independent of the
+// Kekulé structure given in the input.
+//
+// The key is computed during normalisation.  Answers an empty string
+// if this molecule has not been normalised yet.
+func (m *Molecule) CanonicalKey() string {
+	if !m.isNormalised {
+		return ""
+	}
+
+	return m.canonicalKey
+}
+
+// Equals answers if this molecule and the given one have the same
+// structure, regardless of the order in which their atoms were
+// input.
+//
+// Both the molecules should have been normalised.  Answers `false`
+// otherwise.
+func (m *Molecule) Equals(other *Molecule) bool {
+	if other == nil || !m.isNormalised || !other.isNormalised {
+		return false
+	}
+	if len(m.atoms) != len(other.atoms) || len(m.bonds) != len(other.bonds) {
+		return false
+	}
+
+	return m.canonicalKey == other.canonicalKey
+}
+
+// StructureKey answers a layered key of this molecule, in the spirit
+// of - but not compatible with - InChI.  Like `CanonicalKey`, it does
+// not depend on the order in which atoms and bonds were input.
+//
+// The key comprises the following layers, separated by `/`.  Each
+// layer other than the first starts with its identifying letter, and
+// is omitted when empty.
+//
+//   - The molecular formula, as answered by `Formula`.
+//   - `c`: connectivity.  Each bond is written as the normalised IDs
+//     of its atoms, the lower first, joined by `-`.  Bonds are listed
+//     in increasing order of their atoms' IDs, and separated by `,`.
+//   - `h`: hydrogen atoms.  Each atom bearing hydrogen is written as
+//     its normalised ID, followed by `H` and the number of hydrogen
+//     atoms, e.g. `3H2`.
+//   - `q`: charges.  Each charged atom is written as its normalised ID,
+//     followed by its signed residual charge, e.g. `4-1`.
+//
+// Normalised IDs follow the descending order of atomic numbers; hence,
+// the element of each atom follows from the formula layer.  Bond orders
+// are not recorded: for ordinary valences, they follow from the
+// hydrogen and charge layers.  Consequently, Kekulé structures of the
+// same aromatic system share a key, while tautomers - which differ in
+// the positions of their hydrogen atoms - do not.
+//
+// Answers an empty string if this molecule has not been normalised
+// yet.
+func (m *Molecule) StructureKey() string {
+	if !m.isNormalised {
+		return ""
+	}
+
+	nid := func(a *_Atom) uint16 {
+		return a.nId
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(m.Formula())
+	m.writeConnectivityLayer(&buf, nid)
+	m.writeProtonLayers(&buf, nid)
+
+	return buf.String()
+}
+
+// StructureHashKey answers a fixed-length hashed key of this molecule,
+// laid out like - but not compatible with - an InChIKey:
+//
+//	SSSSSSSSSSSSSS-PPPPPPPPRA-C
+//
+// The first block hashes the skeleton of the molecule: the formula of
+// its atoms, less the hydrogen atoms attached to them, and its
+// connectivity layer.  See `StructureKey`.  The second block hashes
+// the hydrogen and charge layers; it is followed by `R`, marking a
+// RxnWeaver key, and `A`, the version of its layout.  The last
+// character gives the net charge: `N` when neutral, `O` for `+1`, `M`
+// for `-1`, and so on.  Each block is the base32 encoding of a SHA-256
+// digest, truncated.  The key is 27 characters long.
+//
+// Unlike in `StructureKey`, atoms are numbered by the ranking of the
+// skeleton alone, and bond orders are ignored.  Hence, the first block
+// is the same for all forms of a molecule that differ only in their
+// protonation and charges - such as an acid and its conjugate base -
+// and can be used to match them.  Counter-ions form part of the
+// skeleton; see `LargestComponent` for stripping them.
+//
+// Answers an empty string if this molecule has not been normalised
+// yet.
+func (m *Molecule) StructureHashKey() string {
+	if !m.isNormalised {
+		return ""
+	}
+
+	sids := m.skeletonIds()
+	sid := func(a *_Atom) uint16 {
+		return sids[a.iId]
+	}
+
+	var skel, prot bytes.Buffer
+	skel.WriteString(m.formula(false))
+	m.writeConnectivityLayer(&skel, sid)
+	m.writeProtonLayers(&prot, sid)
+
+	c := 'N' + m.NetCharge()
+	switch {
+	case c < 'A':
+		c = 'A'
+	case c > 'Z':
+		c = 'Z'
+	}
+
+	return hashBlock(skel.String(), 14) + "-" + hashBlock(prot.String(), 8) + "RA-" + string(rune(c))
+}
+
+// hashBlock answers the first given number of characters of the base32
+// encoding of the SHA-256 digest of the given text.
+func hashBlock(s string, n int) string {
+	sum := sha256.Sum256([]byte(s))
+	return base32.StdEncoding.EncodeToString(sum[:])[:n]
+}
+
+// skeletonIds answers canonical IDs of the atoms of this molecule,
+// keyed by their input IDs, that depend only on the elements of the
+// atoms and their connectivity.  Bond orders, hydrogen counts and
+// charges do not affect the ranking of topologically distinct atoms;
+// they are used only to break ties between equivalent ones.  See
+// `assignNormalisedIds`.
+func (m *Molecule) skeletonIds() map[uint16]uint16 {
+	n := len(m.atoms)
+	ids := make(map[uint16]uint16, n)
+	if n == 0 {
+		return ids
+	}
+
+	adj := m.rankAdjacency(false)
+	keys := make([][]int, n)
+	for i, a := range m.atoms {
+		keys[i] = []int{int(a.atNum)}
+	}
+
+	ranks, c := ranksFromKeys(keys, true)
+	ranks, _ = refineRanks(ranks, c, adj)
+
+	// Higher keys receive lower ranks.
+	for i, a := range m.atoms {
+		keys[i] = []int{-ranks[i], int(a.hCount), int(a.charge)}
+	}
+	for i, r := range canonicalRanks(keys, adj) {
+		ids[m.atoms[i].iId] = uint16(r)
+	}
+
+	return ids
+}
+
+// TopologicalEquivalenceClasses answers the input IDs of the atoms of
+// this molecule, grouped into classes of topologically equivalent
+// atoms: those that some automorphism of the molecule - a permutation
+// of its atoms preserving their attributes and bonds - maps onto one
+// another.  Classes of atoms are in the order of the normalised IDs of
+// their atoms; atoms within a class, in the order in which they are
+// held.
+//
+// Implicit hydrogen atoms have no input IDs.  Those borne by the atoms
+// of a class are equivalent to one another, and form a class of their
+// own.  Such a class lists the input ID of the atom bearing each
+// hydrogen atom - once per hydrogen atom - and follows all the classes
+// of atoms, in the same order.  For example, benzene has two classes:
+// its six carbon atoms, and its six hydrogen atoms.  Toluene has nine:
+// five of carbon atoms, and four of hydrogen atoms.
+//
+// Stale rings and aromaticity are determined afresh; `nil` is answered
+// if that fails.
+func (m *Molecule) TopologicalEquivalenceClasses() [][]uint16 {
+	if m.refresh(stageAromaticity) != nil {
+		return nil
+	}
+
+	if len(m.atoms) == 0 {
+		return nil
+	}
+
+	ranks, c := m.topologicalRanks()
+	classes := make([][]uint16, c)
+	hClasses := make([][]uint16, c)
+	for i, r := range ranks {
+		a := m.atoms[i]
+		classes[r-1] = append(classes[r-1], a.iId)
+		for j := 0; j < int(a.hCount); j++ {
+			hClasses[r-1] = append(hClasses[r-1], a.iId)
+		}
+	}
+
+	for _, hc := range hClasses {
+		if len(hc) > 0 {
+			classes = append(classes, hc)
+		}
+	}
+	return classes
+}
+
+// topologicalRanks answers the rank of each atom of this molecule, in
+// the order in which they are held, together with the number of
+// distinct ranks.  Atoms are ranked equal exactly when some
+// automorphism of the molecule maps one onto the other.  Ranks follow
+// the normalised IDs.  See `TopologicalEquivalenceClasses`.
+func (m *Molecule) topologicalRanks() ([]int, int) {
+	keys := make([][]int, len(m.atoms))
+	for i, a := range m.atoms {
+		keys[i] = a.priorityTuple()
+	}
+	return newRankSearch(keys, m.rankAdjacency(true)).orbitRanks()
+}
+
+// writeConnectivityLayer writes the connectivity layer of this
+// molecule to the given buffer, numbering the atoms with the given
+// function.  See `StructureKey`.
+func (m *Molecule) writeConnectivityLayer(buf *bytes.Buffer, id func(*_Atom) uint16) {
+	cbs := make([]_CanonicalBond, 0, len(m.bonds))
+	for _, b := range m.bonds {
+		id1 := id(m.atomWithIid(b.a1))
+		id2 := id(m.atomWithIid(b.a2))
+		if id1 > id2 {
+			id1, id2 = id2, id1
+		}
+		cbs = append(cbs, _CanonicalBond{id1, id2, b})
+	}
+	sort.Sort(_CanonicalBonds(cbs))
+	for i, cb := range cbs {
+		if i == 0 {
+			buf.WriteString("/c")
+		} else {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(buf, "%d-%d", cb.nid1, cb.nid2)
+	}
+}
+
+// writeProtonLayers writes the hydrogen and charge layers of this
+// molecule to the given buffer, numbering the atoms with the given
+// function.  See `StructureKey`.
+func (m *Molecule) writeProtonLayers(buf *bytes.Buffer, id func(*_Atom) uint16) {
+	atoms := make([]*_Atom, len(m.atoms))
+	for _, a := range m.atoms {
+		atoms[id(a)-1] = a
+	}
+
+	first := true
+	for _, a := range atoms {
+		if a.hCount == 0 {
+			continue
+		}
+		if first {
+			buf.WriteString("/h")
+			first = false
+		} else {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(buf, "%dH%d", id(a), a.hCount)
+	}
+
+	first = true
+	for _, a := range atoms {
+		if a.charge == 0 {
+			continue
+		}
+		if first {
+			buf.WriteString("/q")
+			first = false
+		} else {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(buf, "%d%+d", id(a), a.charge)
+	}
+}
+
+// _CanonicalBond is a bond expressed in terms of the canonical IDs of
+// its atoms - usually, their normalised IDs - with the lower ID first.
+type _CanonicalBond struct {
+	nid1 uint16
+	nid2 uint16
+	b    *_Bond
+}
+
+// _CanonicalBonds sorts canonical bonds on their atoms' normalised
+// IDs.
+type _CanonicalBonds []_CanonicalBond
+
+func (cbs _CanonicalBonds) Len() int {
+	return len(cbs)
+}
+
+func (cbs _CanonicalBonds) Swap(i, j int) {
+	cbs[i], cbs[j] = cbs[j], cbs[i]
+}
+
+func (cbs _CanonicalBonds) Less(i, j int) bool {
+	if cbs[i].nid1 != cbs[j].nid1 {
+		return cbs[i].nid1 < cbs[j].nid1
+	}
+	return cbs[i].nid2 < cbs[j].nid2
+}
+
+// computeCanonicalKey builds the canonical key of this molecule.  See
+// `CanonicalKey` for the format.
+//
+// Normalised IDs must have been assigned to the atoms, before this
+// method is invoked.
+func (m *Molecule) computeCanonicalKey() string {
+	atoms := make([]*_Atom, len(m.atoms))
+	for _, a := range m.atoms {
+		atoms[a.nId-1] = a
+	}
+
+	var buf bytes.Buffer
+	for i, a := range atoms {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if a.isotope != 0 {
+			fmt.Fprintf(&buf, "%d", a.isotope)
+		}
+		buf.WriteString(a.symbol)
+		if a.hCount > 0 {
+			fmt.Fprintf(&buf, "H%d", a.hCount)
+		}
+		if a.charge != 0 {
+			fmt.Fprintf(&buf, "%+d", a.charge)
+		}
+		if a.isInAroRing {
+			buf.WriteByte('a')
+		}
+	}
+
+	buf.WriteByte('/')
+
+	cbs := make([]_CanonicalBond, 0, len(m.bonds))
+	for _, b := range m.bonds {
+		nid1 := m.atomWithIid(b.a1).nId
+		nid2 := m.atomWithIid(b.a2).nId
+		if nid1 > nid2 {
+			nid1, nid2 = nid2, nid1
+		}
+		cbs = append(cbs, _CanonicalBond{nid1, nid2, b})
+	}
+	sort.Sort(_CanonicalBonds(cbs))
+
+	for i, cb := range cbs {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		sym := bondOrderSymbol(cb.b.bType)
+		switch {
+		case cb.b.isAro:
+			sym = ':'
+		case sym == '>' && m.atomWithIid(cb.b.a1).nId != cb.nid1:
+			// The donor is the second atom of the pair.
+			sym = '<'
+		}
+		fmt.Fprintf(&buf, "%d%c%d", cb.nid1, sym, cb.nid2)
+		if cb.b.isCyclic() {
+			buf.WriteByte('r')
+		}
+	}
+
+	return buf.String()
+}
+
+// bondOrderSymbol answers the character conventionally used to depict
+// the given bond type.
+func bondOrderSymbol(bType cmn.BondType) byte {
+	switch bType {
+	case cmn.BondTypeSingle:
+		return '-'
+	case cmn.BondTypeDouble:
+		return '='
+	case cmn.BondTypeTriple:
+		return '#'
+	case cmn.BondTypeDative:
+		return '>'
+	}
+
+	return '~'
+}
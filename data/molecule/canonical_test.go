@@ -0,0 +1,330 @@
+package molecule
+
+import (
+	"strings"
+	"testing"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// buildMethane answers a normalised methane, with its carbon atom of
+// the given mass number; `0` for the natural isotopic composition.
+func buildMethane(t *testing.T, mass int) *Molecule {
+	m := New()
+	ab := m.NewAtomBuilder()
+	if _, err := ab.New("C", 1); err != nil {
+		t.Fatal(err)
+	}
+	ab.Isotope(mass)
+	ab.a.hCount = 4
+	if _, err := ab.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Normalise(); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestCanonicalKeyIsotopes(t *testing.T) {
+	ch4 := buildMethane(t, 0)
+	defer ch4.discard()
+	c13h4 := buildMethane(t, 13)
+	defer c13h4.discard()
+
+	if k := c13h4.CanonicalKey(); !strings.HasPrefix(k, "13CH4") {
+		t.Errorf("canonical key of 13CH4 is %s; want a 13CH4 prefix", k)
+	}
+	if ch4.CanonicalKey() == c13h4.CanonicalKey() {
+		t.Errorf("CH4 and 13CH4 share the canonical key %s", ch4.CanonicalKey())
+	}
+	if ch4.Equals(c13h4) {
+		t.Error("CH4 equals 13CH4")
+	}
+}
+
+// _TestBond is a bond between atoms numbered from `1`, as used to
+// build test molecules.
+type _TestBond struct {
+	a1, a2 int
+	bType  cmn.BondType
+}
+
+// buildInOrder builds the molecule with the given atoms and bonds,
+// adding the atoms in the given order: each element of `order` is the
+// number, less one, of the next atom to add.  Numbers beyond the given
+// atoms are skipped.  Hydrogen atoms are filled from valences, and the
+// molecule is normalised.
+func buildInOrder(t *testing.T, syms []string, bonds []_TestBond, order []int) *Molecule {
+	mb := NewMoleculeBuilder()
+	ids := make([]uint16, len(syms))
+	for _, i := range order {
+		if i >= len(syms) {
+			continue
+		}
+		aid, err := mb.AddAtom(syms[i], 0, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = aid
+	}
+	for _, b := range bonds {
+		if err := mb.AddBond(ids[b.a1-1], ids[b.a2-1], b.bType); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, err := mb.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// buildMolecule builds the molecule with the given atoms and bonds,
+// adding the atoms in the order given.  See `buildInOrder`.
+func buildMolecule(t *testing.T, syms []string, bonds []_TestBond) *Molecule {
+	order := make([]int, len(syms))
+	for i := range order {
+		order[i] = i
+	}
+	return buildInOrder(t, syms, bonds, order)
+}
+
+// testBonds answers bonds between the first two atoms of each of the
+// given triples, of the order given third.
+func testBonds(triples [][3]int) []_TestBond {
+	bs := make([]_TestBond, len(triples))
+	for i, tr := range triples {
+		bs[i] = _TestBond{tr[0], tr[1], cmn.BondType(tr[2])}
+	}
+	return bs
+}
+
+// singleBonds answers single bonds between the given pairs of atoms.
+func singleBonds(pairs [][2]int) []_TestBond {
+	bs := make([]_TestBond, len(pairs))
+	for i, p := range pairs {
+		bs[i] = _TestBond{p[0], p[1], cmn.BondTypeSingle}
+	}
+	return bs
+}
+
+// cuneaneBonds are the bonds of cuneane, C8H8: a cage in which every
+// carbon atom has three ring bonds, but which - unlike cubane - has
+// three kinds of carbon atoms.
+var cuneaneBonds = singleBonds([][2]int{
+	{1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 6}, {6, 7}, {7, 8}, {8, 1},
+	{1, 5}, {2, 4}, {3, 7}, {6, 8},
+})
+
+// cubaneBonds are the bonds of cubane, C8H8.
+var cubaneBonds = singleBonds([][2]int{
+	{1, 2}, {2, 3}, {3, 4}, {4, 1}, {5, 6}, {6, 7}, {7, 8}, {8, 5},
+	{1, 5}, {2, 6}, {3, 7}, {4, 8},
+})
+
+var carbons8 = []string{"C", "C", "C", "C", "C", "C", "C", "C"}
+
+// atomOrders are orders in which to add the atoms of the test
+// molecules.
+var atomOrders = [][]int{
+	{0, 1, 2, 3, 4, 5, 6, 7},
+	{7, 6, 5, 4, 3, 2, 1, 0},
+	{3, 0, 6, 1, 7, 2, 5, 4},
+	{5, 2, 7, 0, 4, 6, 1, 3},
+	{1, 7, 3, 5, 0, 4, 2, 6},
+}
+
+func TestEqualsAtomOrders(t *testing.T) {
+	cases := []struct {
+		name  string
+		syms  []string
+		bonds []_TestBond
+	}{
+		{"ethyl acetate", []string{"C", "C", "O", "O", "C", "C"}, []_TestBond{
+			{1, 2, cmn.BondTypeSingle},
+			{2, 3, cmn.BondTypeDouble},
+			{2, 4, cmn.BondTypeSingle},
+			{4, 5, cmn.BondTypeSingle},
+			{5, 6, cmn.BondTypeSingle},
+		}},
+		{"cuneane", carbons8, cuneaneBonds},
+		{"cubane", carbons8, cubaneBonds},
+	}
+	for _, c := range cases {
+		var ref *Molecule
+		for _, order := range atomOrders {
+			m := buildInOrder(t, c.syms, c.bonds, order)
+			if ref == nil {
+				ref = m
+				continue
+			}
+			if !ref.Equals(m) {
+				t.Errorf("%s in order %v : key %s; want %s", c.name, order, m.CanonicalKey(), ref.CanonicalKey())
+			}
+			m.discard()
+		}
+		ref.discard()
+	}
+}
+
+func TestStructureKeyAtomOrders(t *testing.T) {
+	var want string
+	for _, order := range atomOrders {
+		m := buildInOrder(t, carbons8, cuneaneBonds, order)
+		k := m.StructureKey()
+		m.discard()
+
+		if want == "" {
+			want = k
+		} else if k != want {
+			t.Errorf("cuneane in order %v : key %s; want %s", order, k, want)
+		}
+	}
+}
+
+func TestStructureKeyTautomers(t *testing.T) {
+	pairs := []struct {
+		name   string
+		syms   []string
+		bonds1 []_TestBond
+		bonds2 []_TestBond
+	}{
+		{"acetone / propen-2-ol", []string{"C", "C", "O", "C"},
+			[]_TestBond{{1, 2, cmn.BondTypeSingle}, {2, 3, cmn.BondTypeDouble}, {2, 4, cmn.BondTypeSingle}},
+			[]_TestBond{{1, 2, cmn.BondTypeSingle}, {2, 3, cmn.BondTypeSingle}, {2, 4, cmn.BondTypeDouble}},
+		},
+		{"acetamide / acetimidic acid", []string{"C", "C", "O", "N"},
+			[]_TestBond{{1, 2, cmn.BondTypeSingle}, {2, 3, cmn.BondTypeDouble}, {2, 4, cmn.BondTypeSingle}},
+			[]_TestBond{{1, 2, cmn.BondTypeSingle}, {2, 3, cmn.BondTypeSingle}, {2, 4, cmn.BondTypeDouble}},
+		},
+	}
+	order := []int{0, 1, 2, 3}
+	for _, p := range pairs {
+		m1 := buildInOrder(t, p.syms, p.bonds1, order)
+		m2 := buildInOrder(t, p.syms, p.bonds2, order)
+
+		if m1.Formula() != m2.Formula() {
+			t.Errorf("%s : formulae %s and %s differ", p.name, m1.Formula(), m2.Formula())
+		}
+		if k := m1.StructureKey(); k == m2.StructureKey() {
+			t.Errorf("%s share the structure key %s", p.name, k)
+		}
+		m1.discard()
+		m2.discard()
+	}
+}
+
+func TestTopologicalEquivalenceClasses(t *testing.T) {
+	ring := []_TestBond{
+		{1, 2, cmn.BondTypeDouble}, {2, 3, cmn.BondTypeSingle},
+		{3, 4, cmn.BondTypeDouble}, {4, 5, cmn.BondTypeSingle},
+		{5, 6, cmn.BondTypeDouble}, {6, 1, cmn.BondTypeSingle},
+	}
+	toluene := append([]_TestBond{{1, 7, cmn.BondTypeSingle}}, ring...)
+
+	cases := []struct {
+		name  string
+		syms  []string
+		bonds []_TestBond
+		sizes []int // Sizes of the classes, in order.
+	}{
+		{"benzene", carbons8[:6], ring, []int{6, 6}},
+		{"toluene", carbons8[:7], toluene, []int{1, 2, 2, 1, 1, 2, 2, 1, 3}},
+		{"cubane", carbons8, cubaneBonds, []int{8, 8}},
+		{"cuneane", carbons8, cuneaneBonds, []int{4, 2, 2, 4, 2, 2}},
+	}
+	for _, c := range cases {
+		m := buildInOrder(t, c.syms, c.bonds, atomOrders[2])
+		classes := m.TopologicalEquivalenceClasses()
+		m.discard()
+
+		sizes := make([]int, len(classes))
+		for i, cl := range classes {
+			sizes[i] = len(cl)
+		}
+		if !equalInts(sizes, c.sizes) {
+			t.Errorf("%s : classes %v; want sizes %v", c.name, classes, c.sizes)
+		}
+	}
+}
+
+// sodiumAcetateMolfile is sodium acetate, as a salt of two ions.
+const sodiumAcetateMolfile = `sodium acetate
+  test
+
+  5  3  0  0  0  0  0  0  0  0999 V2000
+    0.0000    0.0000    0.0000 C   0  0  0  0  0  0  0  0  0  0  0  0
+    1.2990    0.7500    0.0000 C   0  0  0  0  0  0  0  0  0  0  0  0
+    1.2990    2.2500    0.0000 O   0  0  0  0  0  0  0  0  0  0  0  0
+    2.5981    0.0000    0.0000 O   0  0  0  0  0  0  0  0  0  0  0  0
+    4.0000    0.0000    0.0000 Na  0  0  0  0  0  0  0  0  0  0  0  0
+  1  2  1  0
+  2  3  2  0
+  2  4  1  0
+M  CHG  2   4  -1   5   1
+M  END`
+
+// TestStructureHashKeySaltForms checks that acetic acid, the acetate
+// anion and the acetate of sodium acetate - its counter-ion stripped -
+// share the skeleton block of their hashed keys, while their other
+// blocks differ.  Methyl formate, an isomer, has a skeleton of its own.
+func TestStructureHashKeySaltForms(t *testing.T) {
+	acid := buildMolecule(t, []string{"C", "C", "O", "O"}, testBonds([][3]int{{1, 2, 1}, {2, 3, 2}, {2, 4, 1}}))
+	defer acid.discard()
+
+	acetate, _, err := ParseMolfile(molfileLines(acetateMolfile), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer acetate.discard()
+	if err := acetate.Normalise(); err != nil {
+		t.Fatal(err)
+	}
+
+	salt, _, err := ParseMolfile(molfileLines(sodiumAcetateMolfile), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer salt.discard()
+	if err := salt.Normalise(); err != nil {
+		t.Fatal(err)
+	}
+	anion, err := salt.LargestComponent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer anion.discard()
+
+	formate := buildMolecule(t, []string{"C", "O", "C", "O"}, testBonds([][3]int{{1, 2, 1}, {2, 3, 1}, {3, 4, 2}}))
+	defer formate.discard()
+
+	key := acid.StructureHashKey()
+	if len(key) != 27 || key[26] != 'N' {
+		t.Fatalf("acetic acid : key %s; want 27 characters, ending in N", key)
+	}
+	for _, c := range []struct {
+		name string
+		m    *Molecule
+	}{
+		{"acetate", acetate},
+		{"acetate of sodium acetate", anion},
+	} {
+		k := c.m.StructureHashKey()
+		if k[:14] != key[:14] {
+			t.Errorf("%s : skeleton block %s; want that of acetic acid, %s", c.name, k[:14], key[:14])
+		}
+		if k[15:] == key[15:] || k[26] != 'M' {
+			t.Errorf("%s : key %s; want other protons and charge than acetic acid, %s", c.name, k, key)
+		}
+	}
+
+	// Unstripped, the counter-ion is part of the skeleton.
+	if k := salt.StructureHashKey(); k[:14] == key[:14] {
+		t.Errorf("sodium acetate : skeleton block %s equals that of acetic acid", k[:14])
+	}
+	if k := formate.StructureHashKey(); k[:14] == key[:14] {
+		t.Errorf("methyl formate : skeleton block %s equals that of acetic acid", k[:14])
+	}
+}
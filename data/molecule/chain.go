@@ -0,0 +1,76 @@
+package molecule
+
+// LongestCarbonChain answers the input IDs of the atoms in a longest
+// chain of carbon atoms in this molecule, in chain order.  Hydrogen
+// atoms are ignored.  Answers an empty slice if this molecule has no
+// carbon atoms.
+//
+// The chain is a simple path: it may run through rings, but does not
+// visit any atom more than once.  When several chains are equally
+// long, the one whose sequence of normalised IDs is lexicographically
+// the lowest is answered.  Hence, this molecule should have been
+// normalised, for the answer to be independent of the input order.
+//
+// Since finding a longest simple path is an exhaustive search in
+// general, this method is meant for molecules of modest size.
+func (m *Molecule) LongestCarbonChain() []uint16 {
+	best := make([]uint16, 0)
+
+	path := make([]uint16, 0, len(m.atoms))
+	onPath := make(map[uint16]bool, len(m.atoms))
+	for _, a := range m.atoms {
+		if a.atNum != 6 {
+			continue
+		}
+		best = m.extendCarbonChain(a, path, onPath, best)
+	}
+
+	return best
+}
+
+// extendCarbonChain extends the given chain through the given carbon
+// atom, exploring all simple carbon paths from it depth-first.
+//
+// Answers the better of the given best chain and the longest chain
+// found.  See `LongestCarbonChain` for how ties are broken.
+func (m *Molecule) extendCarbonChain(a *_Atom, path []uint16, onPath map[uint16]bool, best []uint16) []uint16 {
+	path = append(path, a.iId)
+	onPath[a.iId] = true
+
+	extended := false
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		nid := m.bondWithId(uint16(bid)).otherAtomIid(a.iId)
+		na := m.atomWithIid(nid)
+		if na.atNum != 6 || onPath[nid] {
+			continue
+		}
+		extended = true
+		best = m.extendCarbonChain(na, path, onPath, best)
+	}
+
+	if !extended && m.isBetterChain(path, best) {
+		best = make([]uint16, len(path))
+		copy(best, path)
+	}
+
+	onPath[a.iId] = false
+	return best
+}
+
+// isBetterChain answers if the first given chain is longer than the
+// second, or is of the same length but has a lower sequence of
+// normalised IDs.
+func (m *Molecule) isBetterChain(c1, c2 []uint16) bool {
+	if len(c1) != len(c2) {
+		return len(c1) > len(c2)
+	}
+
+	for i := range c1 {
+		n1 := m.atomWithIid(c1[i]).nId
+		n2 := m.atomWithIid(c2[i]).nId
+		if n1 != n2 {
+			return n1 < n2
+		}
+	}
+	return false
+}
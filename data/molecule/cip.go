@@ -0,0 +1,206 @@
+package molecule
+
+import (
+	"fmt"
+	"sort"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// _CIPNode is a node of the hierarchical digraph used to rank
+// substituents according to CIP rules.  The digraph is rooted at a
+// stereo centre, and is expanded lazily, as comparisons require.
+//
+// A node is a duplicate when it stands for an atom across a multiple
+// bond, or for an atom reached again by closing a ring.  Duplicates
+// have no children; their phantom substituents, of atomic number `0`,
+// are implied.  Implicit hydrogen atoms are nodes without an atom.
+type _CIPNode struct {
+	atom   *_Atom    // Atom of this node; `nil` for an implicit hydrogen.
+	atNum  int       // Atomic number of the atom.
+	parent *_CIPNode // Parent of this node; `nil` at the root.
+	isDup  bool      // Is this node a duplicate?
+
+	kids     []*_CIPNode // Children, in descending order of priority.
+	expanded bool        // Have the children been determined?
+}
+
+// newCIPNode answers a node for the given atom, with the given parent.
+func newCIPNode(a *_Atom, parent *_CIPNode, isDup bool) *_CIPNode {
+	return &_CIPNode{atom: a, atNum: int(a.atNum), parent: parent, isDup: isDup}
+}
+
+// hasAncestor answers if the given atom is that of this node, or of
+// one of its ancestors.
+func (n *_CIPNode) hasAncestor(iId uint16) bool {
+	for p := n; p != nil; p = p.parent {
+		if p.atom != nil && p.atom.iId == iId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// children answers the children of this node, in descending order of
+// their priorities.
+func (n *_CIPNode) children() []*_CIPNode {
+	if n.expanded {
+		return n.kids
+	}
+	n.expanded = true
+	if n.isDup || n.atom == nil {
+		return nil
+	}
+
+	mol := n.atom.mol
+	skipped := false
+	seen := make(map[uint16]bool, len(n.atom.nbrs))
+	for _, nid := range n.atom.nbrs {
+		if n.parent != nil && nid == n.parent.atom.iId && !skipped {
+			skipped = true
+			continue
+		}
+
+		// Repeated neighbours are across multiple bonds.
+		dup := seen[nid] || n.hasAncestor(nid)
+		seen[nid] = true
+		n.kids = append(n.kids, newCIPNode(mol.atomWithIid(nid), n, dup))
+	}
+	for i := 0; i < int(n.atom.hCount); i++ {
+		n.kids = append(n.kids, &_CIPNode{atNum: 1, parent: n, expanded: true})
+	}
+	sort.Sort(_CIPNodes(n.kids))
+
+	return n.kids
+}
+
+// childKey answers the atomic numbers of the children of this node,
+// in descending order.
+func (n *_CIPNode) childKey() []int {
+	kids := n.children()
+	key := make([]int, len(kids))
+	for i, k := range kids {
+		key[i] = k.atNum
+	}
+
+	return key
+}
+
+// compareCIPNodes compares the branches of the digraph at the two
+// given nodes, by CIP rule 1a: atomic numbers, explored hierarchically.
+//
+// The branches are explored sphere by sphere.  Within a sphere, the
+// sets of children of corresponding nodes are compared in the order
+// of the priorities of those nodes, and the first difference decides.
+// A missing child counts as a phantom atom.
+func compareCIPNodes(n1, n2 *_CIPNode) int {
+	if n1.atNum != n2.atNum {
+		return n1.atNum - n2.atNum
+	}
+
+	f1, f2 := []*_CIPNode{n1}, []*_CIPNode{n2}
+	for len(f1) > 0 && len(f2) > 0 {
+		for i := 0; i < len(f1) && i < len(f2); i++ {
+			if c := compareKeys(f1[i].childKey(), f2[i].childKey()); c != 0 {
+				return c
+			}
+		}
+
+		next1 := make([]*_CIPNode, 0, 3*len(f1))
+		for _, n := range f1 {
+			next1 = append(next1, n.children()...)
+		}
+		next2 := make([]*_CIPNode, 0, 3*len(f2))
+		for _, n := range f2 {
+			next2 = append(next2, n.children()...)
+		}
+		f1, f2 = next1, next2
+	}
+
+	return len(f1) - len(f2)
+}
+
+// _CIPNodes sorts nodes of the digraph in descending order of their
+// priorities.
+type _CIPNodes []*_CIPNode
+
+func (ns _CIPNodes) Len() int {
+	return len(ns)
+}
+
+func (ns _CIPNodes) Swap(i, j int) {
+	ns[i], ns[j] = ns[j], ns[i]
+}
+
+func (ns _CIPNodes) Less(i, j int) bool {
+	return compareCIPNodes(ns[i], ns[j]) > 0
+}
+
+// cipNeighbours answers the neighbours of this atom in descending
+// order of their CIP priorities.  It also answers if all of them - and
+// any attached hydrogen atom - are mutually distinguishable by rule 1a.
+func (a *_Atom) cipNeighbours() ([]*_Atom, bool) {
+	root := newCIPNode(a, nil, false)
+	kids := root.children()
+	for i := 1; i < len(kids); i++ {
+		if compareCIPNodes(kids[i-1], kids[i]) == 0 {
+			return nil, false
+		}
+	}
+
+	nbrs := make([]*_Atom, 0, len(kids))
+	for _, k := range kids {
+		if k.atom != nil {
+			nbrs = append(nbrs, k.atom)
+		}
+	}
+
+	return nbrs, true
+}
+
+// AssignCIP determines the CIP descriptor - `R` or `S` - of each
+// tetrahedral stereo centre in this molecule whose parity is defined.
+// See `PerceiveTetrahedralStereo`.
+//
+// Substituents are ranked by CIP rule 1a, over the hierarchical
+// digraph: atomic numbers, with atoms across multiple bonds and at
+// ring closures duplicated.  Isotopes, and the later rules, are not
+// considered; centres whose substituents are not distinguished by
+// rule 1a are left at `CIPLabelNone`.
+//
+// Stereo should have been perceived, and this molecule must have been
+// normalised, before this method is invoked.
+func (m *Molecule) AssignCIP() error {
+	if !m.isNormalised {
+		return fmt.Errorf("Molecule %d has not been normalised.", m.id)
+	}
+
+	for _, a := range m.atoms {
+		a.cipLabel = cmn.CIPLabelNone
+		if a.stereoParity != cmn.StereoParityOdd && a.stereoParity != cmn.StereoParityEven {
+			continue
+		}
+
+		nbrs, ok := a.cipNeighbours()
+		if !ok {
+			continue
+		}
+
+		// With four neighbours, an even parity places the lowest-ranked
+		// one behind a clockwise sequence of the rest.  With an implicit
+		// hydrogen, the centre stands first in its stead, reversing the
+		// sense.
+		p := a.tetrahedralParity(nbrs)
+		switch {
+		case p == cmn.StereoParityNone:
+			continue
+		case (p == cmn.StereoParityEven) == (len(nbrs) == 4):
+			a.cipLabel = cmn.CIPLabelR
+		default:
+			a.cipLabel = cmn.CIPLabelS
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package molecule
+
+import (
+	"fmt"
+	"testing"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// bromochlorofluoromethaneMolfile answers bromochlorofluoromethane,
+// drawn with the bromine atom at the top, chlorine at the lower right
+// and fluorine at the lower left.  The bond to bromine carries the
+// given wedge code: `1` for up, and `6` for down.
+func bromochlorofluoromethaneMolfile(wedge int) string {
+	return fmt.Sprintf(`CHBrClF
+  test
+
+  4  3  0  0  0  0  0  0  0  0999 V2000
+    0.0000    0.0000    0.0000 C   0  0  0  0  0  0  0  0  0  0  0  0
+    0.0000    1.0000    0.0000 Br  0  0  0  0  0  0  0  0  0  0  0  0
+    0.8660   -0.5000    0.0000 Cl  0  0  0  0  0  0  0  0  0  0  0  0
+   -0.8660   -0.5000    0.0000 F   0  0  0  0  0  0  0  0  0  0  0  0
+  1  2  1  %d
+  1  3  1  0
+  1  4  1  0
+M  END`, wedge)
+}
+
+// TestAssignCIPBromochlorofluoromethane checks both enantiomers of
+// bromochlorofluoromethane.  With the bromine atom wedged up, the
+// hydrogen atom points away from the viewer, and Br, Cl and F - in
+// descending priority - run clockwise: the centre is R.  Wedged down,
+// it is S.
+func TestAssignCIPBromochlorofluoromethane(t *testing.T) {
+	cases := []struct {
+		name  string
+		wedge int
+		label cmn.CIPLabel
+	}{
+		{"(R)", 1, cmn.CIPLabelR},
+		{"(S)", 6, cmn.CIPLabelS},
+	}
+	for _, c := range cases {
+		m, _, err := ParseMolfile(molfileLines(bromochlorofluoromethaneMolfile(c.wedge)), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Normalise(); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.PerceiveTetrahedralStereo(); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.AssignCIP(); err != nil {
+			t.Fatal(err)
+		}
+
+		a, _ := m.AtomWithIid(1)
+		if l := a.CIPLabel(); l != c.label {
+			t.Errorf("%s : C has label %v; want %v", c.name, l, c.label)
+		}
+		m.discard()
+	}
+
+	// Without a wedge, the configuration is not defined.
+	m, _, err := ParseMolfile(molfileLines(bromochlorofluoromethaneMolfile(0)), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.discard()
+	if err := m.Normalise(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.PerceiveTetrahedralStereo(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AssignCIP(); err != nil {
+		t.Fatal(err)
+	}
+	if a, _ := m.AtomWithIid(1); a.CIPLabel() != cmn.CIPLabelNone {
+		t.Errorf("unwedged : C has label %v; want none", a.CIPLabel())
+	}
+}
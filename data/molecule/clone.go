@@ -0,0 +1,145 @@
+package molecule
+
+// Clone answers a deep copy of this molecule, with a fresh molecule
+// ID.
+//
+// The copy has the same atoms, bonds, rings and ring systems - with the
+// same IDs - as this molecule, as well as the same vendor information
+// and attributes.  All computed state, such as normalised IDs,
+// aromaticity, functional groups and distances, is carried over; a
+// normalised molecule answers a normalised copy.  So is being frozen;
+// see `Freeze`.
+//
+// The copy shares nothing with this molecule; either can be modified
+// without affecting the other.
+func (m *Molecule) Clone() *Molecule {
+	mol := New()
+	mol.copyStructure(m)
+
+	mol.vendor = m.vendor
+	mol.vendorMoleculeId = m.vendorMoleculeId
+	mol.attributes = append(mol.attributes, m.attributes...)
+	mol.frozen = m.frozen
+
+	return mol
+}
+
+// adopt replaces the structure of this molecule with that of the given
+// one, together with its computed state.  See `copyStructure`.  The
+// ID, vendor information and attributes of this molecule are retained,
+// as is its being frozen.
+//
+// The given molecule is discarded; it should not be used afterwards.
+func (m *Molecule) adopt(other *Molecule) {
+	m.copyStructure(other)
+	other.discard()
+}
+
+// copyStructure replaces the atoms, bonds, rings and ring systems of
+// this molecule with copies of those of the given molecule, and
+// carries its computed state over.
+func (m *Molecule) copyStructure(other *Molecule) {
+	m.atoms = make([]*_Atom, 0, len(other.atoms))
+	m.bonds = make([]*_Bond, 0, len(other.bonds))
+	m.rings = make([]*_Ring, 0, len(other.rings))
+	m.ringSystems = make([]*_RingSystem, 0, len(other.ringSystems))
+	m.atomsByIid = make(map[uint16]*_Atom, len(other.atoms))
+	m.bondsById = make(map[uint16]*_Bond, len(other.bonds))
+
+	for _, a := range other.atoms {
+		na := a.clone(m)
+		m.atoms = append(m.atoms, na)
+		m.atomsByIid[na.iId] = na
+	}
+	for _, b := range other.bonds {
+		nb := b.clone(m)
+		m.bonds = append(m.bonds, nb)
+		m.bondsById[nb.id] = nb
+	}
+	for _, r := range other.rings {
+		m.rings = append(m.rings, r.clone(m))
+	}
+	for _, rs := range other.ringSystems {
+		m.ringSystems = append(m.ringSystems, rs.clone(m))
+	}
+
+	m.nextAtomIid = other.nextAtomIid
+	m.nextBondId = other.nextBondId
+	m.nextRingId = other.nextRingId
+	m.nextRingSystemId = other.nextRingSystemId
+
+	m.dists = cloneIntMatrix(other.dists)
+	m.paths = cloneIntMatrix(other.paths)
+
+	m.aroModel = other.aroModel
+	m.isNormalised = other.isNormalised
+	m.canonicalKey = other.canonicalKey
+	m.dirty = other.dirty
+}
+
+// clone answers a copy of this atom, belonging to the given molecule.
+func (a *_Atom) clone(mol *Molecule) *_Atom {
+	na := new(_Atom)
+	*na = *a
+	na.mol = mol
+
+	na.bonds = a.bonds.Clone()
+	na.nbrs = append(make([]uint16, 0, cap(a.nbrs)), a.nbrs...)
+	na.rings = a.rings.Clone()
+	na.features = append(make([]uint16, 0, cap(a.features)), a.features...)
+
+	return na
+}
+
+// clone answers a copy of this bond, belonging to the given molecule.
+func (b *_Bond) clone(mol *Molecule) *_Bond {
+	nb := new(_Bond)
+	*nb = *b
+	nb.mol = mol
+
+	nb.rings = append(make([]uint8, 0, cap(b.rings)), b.rings...)
+
+	return nb
+}
+
+// clone answers a copy of this ring, belonging to the given molecule.
+func (r *_Ring) clone(mol *Molecule) *_Ring {
+	nr := new(_Ring)
+	*nr = *r
+	nr.mol = mol
+
+	nr.atoms = append(make([]uint16, 0, cap(r.atoms)), r.atoms...)
+	nr.bonds = append(make([]uint16, 0, cap(r.bonds)), r.bonds...)
+	nr.nbrs = append(make([]uint8, 0, cap(r.nbrs)), r.nbrs...)
+	nr.atomBitSet = r.atomBitSet.Clone()
+	nr.bondBitSet = r.bondBitSet.Clone()
+
+	return nr
+}
+
+// clone answers a copy of this ring system, belonging to the given
+// molecule.
+func (rs *_RingSystem) clone(mol *Molecule) *_RingSystem {
+	nrs := new(_RingSystem)
+	*nrs = *rs
+	nrs.mol = mol
+
+	nrs.rings = append(make([]uint8, 0, cap(rs.rings)), rs.rings...)
+	nrs.atomBitSet = rs.atomBitSet.Clone()
+	nrs.bondBitSet = rs.bondBitSet.Clone()
+
+	return nrs
+}
+
+// cloneIntMatrix answers a deep copy of the given matrix.
+func cloneIntMatrix(mat [][]int) [][]int {
+	if mat == nil {
+		return nil
+	}
+
+	nm := make([][]int, len(mat))
+	for i, row := range mat {
+		nm[i] = append([]int(nil), row...)
+	}
+	return nm
+}
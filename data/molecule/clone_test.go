@@ -0,0 +1,75 @@
+package molecule
+
+import (
+	"testing"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// TestCloneEditDiff clones phenol, edits the clone, and checks that the
+// edits show in a diff against the original, while the original is
+// left as it was.
+func TestCloneEditDiff(t *testing.T) {
+	// C1 to C6 form the ring; O7 is on C6.
+	m := buildMolecule(t, []string{"C", "C", "C", "C", "C", "C", "O"}, testBonds([][3]int{
+		{1, 2, 2}, {2, 3, 1}, {3, 4, 2}, {4, 5, 1}, {5, 6, 2}, {6, 1, 1}, {6, 7, 1},
+	}))
+	defer m.discard()
+	key := m.CanonicalKey()
+
+	c := m.Clone()
+	defer c.discard()
+	if c.Id() == m.Id() {
+		t.Errorf("clone has the ID %d of the original", c.Id())
+	}
+	if d, err := Diff(m, c); err != nil || !d.IsEmpty() {
+		t.Fatalf("unedited clone : diff %+v, error %v; want no differences", d, err)
+	}
+
+	// Remove the C-O bond and a ring bond of the clone.
+	c.Unfreeze()
+	co := c.atomWithIid(6).bondTo(7)
+	ring := c.atomWithIid(1).bondTo(2)
+	for _, b := range []*_Bond{co, ring} {
+		if err := c.RemoveBond(b.id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d, err := Diff(m, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []BondDiff{
+		{1, 2, cmn.BondTypeDouble, cmn.BondTypeNone, cmn.StereoParityNone, cmn.StereoParityNone},
+		{6, 7, cmn.BondTypeSingle, cmn.BondTypeNone, cmn.StereoParityNone, cmn.StereoParityNone},
+	}
+	if len(d.RemovedBonds) != len(want) || d.RemovedBonds[0] != want[0] || d.RemovedBonds[1] != want[1] {
+		t.Errorf("removed bonds %+v; want %+v", d.RemovedBonds, want)
+	}
+	if len(d.AddedBonds) != 0 || len(d.ChangedBonds) != 0 || len(d.AddedAtoms) != 0 || len(d.RemovedAtoms) != 0 {
+		t.Errorf("diff %+v; want only removed bonds", d)
+	}
+
+	// The original keeps its bonds, neighbours, bond counts and ring.
+	if len(m.bonds) != 7 || len(m.rings) != 1 || !m.IsFrozen() {
+		t.Errorf("original : %d bonds, %d rings, frozen %v; want 7, 1 and true", len(m.bonds), len(m.rings), m.IsFrozen())
+	}
+	for _, id := range []uint16{1, 2, 6, 7} {
+		a, ca := m.atomWithIid(id), c.atomWithIid(id)
+		if a.bonds == ca.bonds || a.rings == ca.rings {
+			t.Errorf("atom %d : bit sets shared with the clone", id)
+		}
+	}
+	c1, c6, o7 := m.atomWithIid(1), m.atomWithIid(6), m.atomWithIid(7)
+	if c1.bonds.Count() != 2 || c1.doubleBondCount != 1 || !c1.isCyclic() {
+		t.Errorf("original C1 : %d bonds, %d double, cyclic %v; want 2, 1 and true",
+			c1.bonds.Count(), c1.doubleBondCount, c1.isCyclic())
+	}
+	if len(c6.nbrs) != 4 || o7.bonds.Count() != 1 {
+		t.Errorf("original C6 : neighbours %v; O7 : %d bonds; want 4 entries and 1 bond", c6.nbrs, o7.bonds.Count())
+	}
+	if k := m.CanonicalKey(); k != key {
+		t.Errorf("original canonical key %s; want %s", k, key)
+	}
+}
@@ -0,0 +1,210 @@
+package molecule
+
+import (
+	"fmt"
+	"sort"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// IsConnected answers if all the atoms of this molecule are reachable
+// from one another through its bonds.  An empty molecule is deemed
+// connected.
+//
+// Several routines - ring detection and distance computations, in
+// particular - assume a connected molecule.  Inputs such as salts and
+// mixtures are usually not, and should be split first.
+func (m *Molecule) IsConnected() bool {
+	return m.ComponentCount() <= 1
+}
+
+// ComponentCount answers the number of connected components in this
+// molecule.
+func (m *Molecule) ComponentCount() int {
+	return len(m.components())
+}
+
+// components answers the input IDs of the atoms in each connected
+// component of this molecule.  The IDs in each component are in
+// increasing order, and the components are in increasing order of
+// their first atoms.
+func (m *Molecule) components() [][]uint16 {
+	comps := make([][]uint16, 0, 1)
+	seen := make(map[uint16]bool, len(m.atoms))
+
+	for _, root := range m.atoms {
+		if seen[root.iId] {
+			continue
+		}
+
+		comp := []uint16{root.iId}
+		seen[root.iId] = true
+		for i := 0; i < len(comp); i++ {
+			a := m.atomWithIid(comp[i])
+			for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+				nid := m.bondWithId(uint16(bid)).otherAtomIid(a.iId)
+				if seen[nid] {
+					continue
+				}
+				seen[nid] = true
+				comp = append(comp, nid)
+			}
+		}
+
+		sort.Sort(_Uint16s(comp))
+		comps = append(comps, comp)
+	}
+
+	sort.Sort(_Components(comps))
+	return comps
+}
+
+// _Components sorts connected components in increasing order of their
+// first atoms.
+type _Components [][]uint16
+
+func (cs _Components) Len() int {
+	return len(cs)
+}
+
+func (cs _Components) Swap(i, j int) {
+	cs[i], cs[j] = cs[j], cs[i]
+}
+
+func (cs _Components) Less(i, j int) bool {
+	return cs[i][0] < cs[j][0]
+}
+
+// SplitComponents answers a new molecule for each connected component
+// of this molecule, in increasing order of their first atoms.  This
+// molecule itself is left unchanged.
+//
+// Each fragment retains the elements, charges, hydrogen counts and
+// coordinates of its atoms, and the orders and stereo of its bonds.
+// Its atoms and bonds are assigned fresh IDs, starting at `1`, in
+// their original order.  The vendor information and the
+// attributes of this molecule are copied to every fragment.
+//
+// Each fragment is normalised, so that its rings and aromaticity are
+// determined afresh.
+func (m *Molecule) SplitComponents() ([]*Molecule, error) {
+	comps := m.components()
+	frags := make([]*Molecule, 0, len(comps))
+	for _, comp := range comps {
+		frag, err := m.fragment(comp)
+		if err != nil {
+			discardAll(frags)
+			return nil, err
+		}
+		frags = append(frags, frag)
+	}
+
+	return frags, nil
+}
+
+// LargestComponent answers a new molecule comprising the largest
+// connected component of this molecule, as judged by its number of
+// atoms.  Ties are broken in favour of the component with more carbon
+// atoms, and then the one appearing first.  This is typically used to
+// discard counter-ions of salts, and solvents of crystallisation.
+//
+// See `SplitComponents` for how the new molecule is constructed.
+func (m *Molecule) LargestComponent() (*Molecule, error) {
+	comps := m.components()
+	if len(comps) == 0 {
+		return nil, fmt.Errorf("Molecule %d has no atoms.", m.id)
+	}
+
+	best, bestCs := 0, m.carbonCount(comps[0])
+	for i, comp := range comps[1:] {
+		cs := m.carbonCount(comp)
+		switch {
+		case len(comp) > len(comps[best]):
+		case len(comp) == len(comps[best]) && cs > bestCs:
+		default:
+			continue
+		}
+		best, bestCs = i+1, cs
+	}
+
+	return m.fragment(comps[best])
+}
+
+// carbonCount answers the number of carbon atoms amongst the given
+// atoms.
+func (m *Molecule) carbonCount(iIds []uint16) int {
+	c := 0
+	for _, aid := range iIds {
+		if m.atomWithIid(aid).atNum == 6 {
+			c++
+		}
+	}
+
+	return c
+}
+
+// fragment answers a new, normalised molecule comprising the given
+// atoms of this molecule, and the bonds amongst them.  See
+// `SplitComponents`.
+//
+// Should its construction fail, the new molecule is discarded.
+func (m *Molecule) fragment(iIds []uint16) (*Molecule, error) {
+	frag := New()
+	if err := m.buildFragment(frag, iIds); err != nil {
+		frag.discard()
+		return nil, err
+	}
+
+	return frag, nil
+}
+
+// buildFragment populates the given empty molecule with the given
+// atoms of this molecule, and the bonds amongst them, and normalises
+// it.  See `fragment`.
+func (m *Molecule) buildFragment(frag *Molecule, iIds []uint16) error {
+	frag.vendor = m.vendor
+	frag.vendorMoleculeId = m.vendorMoleculeId
+	frag.aroModel = m.aroModel
+	for _, attr := range m.attributes {
+		frag.SetAttribute(attr.Name, attr.Value)
+	}
+
+	newIds := make(map[uint16]uint16, len(iIds))
+	for i, aid := range iIds {
+		oa := m.atomWithIid(aid)
+		a := newAtom(frag, oa.atNum, i+1)
+		a.symbol = oa.symbol
+		a.X, a.Y, a.Z = oa.X, oa.Y, oa.Z
+		a.hCount = oa.hCount
+		a.charge = oa.charge
+		a.radical = oa.radical
+		a.isotope = oa.isotope
+		a.mapNum = oa.mapNum
+		if err := frag.addAtom(a); err != nil {
+			return err
+		}
+		newIds[aid] = a.iId
+	}
+
+	bid := 0
+	for _, ob := range m.bonds {
+		a1, ok1 := newIds[ob.a1]
+		a2, ok2 := newIds[ob.a2]
+		if !ok1 || !ok2 {
+			continue
+		}
+
+		bid++
+		b := newBond(frag, bid)
+		b.a1, b.a2 = a1, a2
+		b.bType = ob.bType
+		b.bStereo = ob.bStereo
+		// Bonds yet to be assigned orders are known to be aromatic.
+		b.isAro = ob.bType == cmn.BondTypeAltern
+		if err := frag.addBond(b); err != nil {
+			return err
+		}
+	}
+
+	return frag.Normalise()
+}
@@ -0,0 +1,71 @@
+package molecule
+
+import (
+	"testing"
+	"time"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// liveMoleculesAfter answers the number of molecules alive, with IDs
+// greater than the given one.  Since molecules register and
+// unregister themselves asynchronously, it waits for their number to
+// fall to zero, for a while.
+func liveMoleculesAfter(id uint64) int {
+	count := func() int {
+		AllMolecules.mu.RLock()
+		defer AllMolecules.mu.RUnlock()
+
+		n := 0
+		for mid := range AllMolecules.allMolecules {
+			if mid > id {
+				n++
+			}
+		}
+		return n
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	deadline := time.Now().Add(2 * time.Second)
+	n := count()
+	for n > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		n = count()
+	}
+	return n
+}
+
+func TestSplitComponentsDiscardsOnError(t *testing.T) {
+	// Methane, and a ring of five aromatic CH atoms, which has no
+	// Kekulé structure.
+	m := New()
+	defer m.discard()
+	for i := 1; i <= 6; i++ {
+		a := newAtom(m, 6, i)
+		a.hCount = 1
+		if i == 1 {
+			a.hCount = 4
+		}
+		if err := m.addAtom(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 1; i <= 5; i++ {
+		b := newBond(m, i)
+		b.a1, b.a2 = uint16(i+1), uint16(i%5+2)
+		b.bType, b.isAro = cmn.BondTypeAltern, true
+		if err := m.addBond(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	last := m.id
+	frags, err := m.SplitComponents()
+	if err == nil {
+		discardAll(frags)
+		t.Fatal("components of an invalid ring were normalised")
+	}
+	if n := liveMoleculesAfter(last); n != 0 {
+		t.Errorf("%d fragments left alive", n)
+	}
+}
@@ -0,0 +1,641 @@
+package molecule
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// RotatableBondCount answers the number of rotatable bonds in this
+// molecule.
+//
+// A bond is rotatable if it is a single, acyclic bond between two
+// non-terminal atoms.  Amide C-N bonds are excluded, since their
+// partial double bond character restricts rotation.
+//
+// This molecule should have been normalised, for ring membership and
+// carbonyl detection to be available.
+func (m *Molecule) RotatableBondCount() int {
+	c := 0
+	for _, b := range m.bonds {
+		if b.isRotatable() {
+			c++
+		}
+	}
+
+	return c
+}
+
+// isRotatable answers if this bond is a rotatable bond.  See
+// `RotatableBondCount` for the criteria.
+func (b *_Bond) isRotatable() bool {
+	if b.bType != cmn.BondTypeSingle || b.isAro || b.isCyclic() {
+		return false
+	}
+
+	mol := b.mol
+	a1 := mol.atomWithIid(b.a1)
+	a2 := mol.atomWithIid(b.a2)
+	if a1.isTerminal() || a2.isTerminal() {
+		return false
+	}
+
+	return !b.isAmideCN()
+}
+
+// isAmideCN answers if this bond is an amide linkage: a single bond
+// binding a carbonyl carbon to an amide nitrogen.
+func (b *_Bond) isAmideCN() bool {
+	if b.bType != cmn.BondTypeSingle || b.isAro {
+		return false
+	}
+
+	mol := b.mol
+	a1 := mol.atomWithIid(b.a1)
+	a2 := mol.atomWithIid(b.a2)
+
+	switch {
+	case a1.atNum == 7:
+		return a2.isCarbonylC() && a1.isAmideN()
+	case a2.atNum == 7:
+		return a1.isCarbonylC() && a2.isAmideN()
+	}
+
+	return false
+}
+
+// TPSA answers the topological polar surface area of this molecule,
+// in square Angstroms.
+//
+// It follows the fragment contribution scheme of Ertl et al.,
+// J. Med. Chem. 43, 3714-3717 (2000).  Each nitrogen, oxygen, sulfur
+// and phosphorus atom is classified by its bonds, attached hydrogen
+// atoms and charge, and the corresponding contributions are summed.
+// Nitrogen and oxygen environments not in the table are approximated
+// from their numbers of heavy and hydrogen neighbours.
+//
+// Aromatic atoms are classified using their aromatic bonds.  Hence,
+// aromaticity must have been determined - usually by normalising this
+// molecule - before this method is invoked.
+func (m *Molecule) TPSA() float64 {
+	sum := 0.0
+	for _, a := range m.atoms {
+		sum += a.polarSurfaceContribution()
+	}
+
+	return sum
+}
+
+// _BondProfile summarises the bonds of an atom, for classification
+// purposes.
+type _BondProfile struct {
+	single int // Number of non-aromatic single bonds.
+	double int // Number of non-aromatic double bonds.
+	triple int // Number of non-aromatic triple bonds.
+	aro    int // Number of aromatic bonds.
+}
+
+// bondProfile answers a summary of the bonds of this atom.
+func (a *_Atom) bondProfile() _BondProfile {
+	p := _BondProfile{}
+
+	mol := a.mol
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		b := mol.bondWithId(uint16(bid))
+		switch {
+		case b.isAro:
+			p.aro++
+		case b.bType == cmn.BondTypeSingle:
+			p.single++
+		case b.bType == cmn.BondTypeDouble:
+			p.double++
+		case b.bType == cmn.BondTypeTriple:
+			p.triple++
+		}
+	}
+
+	return p
+}
+
+// polarSurfaceContribution answers the contribution of this atom to
+// the topological polar surface area of its molecule.  See `TPSA`.
+func (a *_Atom) polarSurfaceContribution() float64 {
+	p := a.bondProfile()
+	h := a.hCount
+	in3Ring := a.isInRingOfSize(3)
+	nHeavy := float64(a.bonds.Count())
+
+	switch a.atNum {
+	case 7:
+		if v, ok := nitrogenPolarSurface(p, h, a.charge, in3Ring); ok {
+			return v
+		}
+		return math.Max(0, 30.5-8.2*nHeavy+1.5*float64(h))
+
+	case 8:
+		if v, ok := oxygenPolarSurface(p, h, a.charge, in3Ring); ok {
+			return v
+		}
+		return math.Max(0, 28.5-8.6*nHeavy+1.5*float64(h))
+
+	case 16:
+		if a.charge != 0 {
+			return 0
+		}
+		switch {
+		case p == _BondProfile{aro: 2} && h == 0:
+			return 28.24
+		case p == _BondProfile{double: 1, aro: 2} && h == 0:
+			return 21.70
+		case p == _BondProfile{single: 2} && h == 0:
+			return 25.30
+		case p == _BondProfile{double: 1} && h == 0:
+			return 32.09
+		case p == _BondProfile{single: 2, double: 1} && h == 0:
+			return 19.21
+		case p == _BondProfile{single: 2, double: 2} && h == 0:
+			return 8.38
+		case p == _BondProfile{single: 1} && h == 1:
+			return 38.80
+		}
+
+	case 15:
+		if a.charge != 0 {
+			return 0
+		}
+		switch {
+		case p == _BondProfile{single: 3} && h == 0:
+			return 13.59
+		case p == _BondProfile{single: 1, double: 1} && h == 0:
+			return 34.14
+		case p == _BondProfile{single: 3, double: 1} && h == 0:
+			return 9.81
+		case p == _BondProfile{single: 2, double: 1} && h == 1:
+			return 23.47
+		}
+	}
+
+	return 0
+}
+
+// nitrogenPolarSurface answers the tabulated polar surface
+// contribution of a nitrogen atom with the given environment.
+// Answers `false` if the environment is not tabulated.
+func nitrogenPolarSurface(p _BondProfile, h uint8, charge int8, in3Ring bool) (float64, bool) {
+	switch charge {
+	case 0:
+		switch {
+		case p == _BondProfile{aro: 2} && h == 0:
+			return 12.89, true
+		case p == _BondProfile{aro: 3} && h == 0:
+			return 4.41, true
+		case p == _BondProfile{single: 1, aro: 2} && h == 0:
+			return 4.93, true
+		case p == _BondProfile{double: 1, aro: 2} && h == 0:
+			return 8.39, true
+		case p == _BondProfile{aro: 2} && h == 1:
+			return 15.79, true
+		case p == _BondProfile{single: 3} && h == 0:
+			if in3Ring {
+				return 3.01, true
+			}
+			return 3.24, true
+		case p == _BondProfile{single: 1, double: 1} && h == 0:
+			return 12.36, true
+		case p == _BondProfile{triple: 1} && h == 0:
+			return 23.79, true
+		case p == _BondProfile{single: 1, double: 2} && h == 0:
+			return 11.68, true
+		case p == _BondProfile{double: 1, triple: 1} && h == 0:
+			return 13.60, true
+		case p == _BondProfile{single: 2} && h == 1:
+			if in3Ring {
+				return 21.94, true
+			}
+			return 12.03, true
+		case p == _BondProfile{double: 1} && h == 1:
+			return 23.85, true
+		case p == _BondProfile{single: 1} && h == 2:
+			return 26.02, true
+		}
+
+	case 1:
+		switch {
+		case p == _BondProfile{aro: 3} && h == 0:
+			return 4.10, true
+		case p == _BondProfile{single: 1, aro: 2} && h == 0:
+			return 3.88, true
+		case p == _BondProfile{aro: 2} && h == 1:
+			return 14.14, true
+		case p == _BondProfile{single: 4} && h == 0:
+			return 0.00, true
+		case p == _BondProfile{single: 2, double: 1} && h == 0:
+			return 3.01, true
+		case p == _BondProfile{single: 1, triple: 1} && h == 0:
+			return 4.36, true
+		case p == _BondProfile{single: 3} && h == 1:
+			return 4.44, true
+		case p == _BondProfile{single: 1, double: 1} && h == 1:
+			return 13.97, true
+		case p == _BondProfile{single: 2} && h == 2:
+			return 16.61, true
+		case p == _BondProfile{double: 1} && h == 2:
+			return 25.59, true
+		case p == _BondProfile{single: 1} && h == 3:
+			return 27.64, true
+		}
+	}
+
+	return 0, false
+}
+
+// oxygenPolarSurface answers the tabulated polar surface contribution
+// of an oxygen atom with the given environment.  Answers `false` if
+// the environment is not tabulated.
+func oxygenPolarSurface(p _BondProfile, h uint8, charge int8, in3Ring bool) (float64, bool) {
+	switch charge {
+	case 0:
+		switch {
+		case p == _BondProfile{aro: 2} && h == 0:
+			return 13.14, true
+		case p == _BondProfile{single: 2} && h == 0:
+			if in3Ring {
+				return 12.53, true
+			}
+			return 9.23, true
+		case p == _BondProfile{double: 1} && h == 0:
+			return 17.07, true
+		case p == _BondProfile{single: 1} && h == 1:
+			return 20.23, true
+		}
+
+	case -1:
+		if p == (_BondProfile{single: 1}) && h == 0 {
+			return 23.06, true
+		}
+	}
+
+	return 0, false
+}
+
+// RingSystemCount answers the number of ring systems detected in this
+// molecule.  Stale rings are perceived afresh; `0` is answered if that
+// fails.
+func (m *Molecule) RingSystemCount() int {
+	if m.refresh(stageRings) != nil {
+		return 0
+	}
+
+	return len(m.ringSystems)
+}
+
+// SmallestRingSize answers the size of the smallest ring detected in
+// this molecule.  Answers `0` if this molecule is acyclic.  Stale
+// rings are perceived afresh; `0` is answered if that fails.
+func (m *Molecule) SmallestRingSize() int {
+	if m.refresh(stageRings) != nil {
+		return 0
+	}
+
+	min := 0
+	for _, r := range m.rings {
+		if min == 0 || r.size() < min {
+			min = r.size()
+		}
+	}
+
+	return min
+}
+
+// LargestRingSize answers the size of the largest ring detected in
+// this molecule.  Answers `0` if this molecule is acyclic.  Stale
+// rings are perceived afresh; `0` is answered if that fails.
+func (m *Molecule) LargestRingSize() int {
+	if m.refresh(stageRings) != nil {
+		return 0
+	}
+
+	max := 0
+	for _, r := range m.rings {
+		if r.size() > max {
+			max = r.size()
+		}
+	}
+
+	return max
+}
+
+// HasRingOfSize answers if this molecule has at least one ring of the
+// given size.  Stale rings are perceived afresh; `false` is answered
+// if that fails.
+func (m *Molecule) HasRingOfSize(n int) bool {
+	return m.CountRingsOfSize(n) > 0
+}
+
+// CountRingsOfSize answers the number of rings of the given size
+// detected in this molecule.  Stale rings are perceived afresh; `0` is
+// answered if that fails.
+func (m *Molecule) CountRingsOfSize(n int) int {
+	if m.refresh(stageRings) != nil {
+		return 0
+	}
+
+	c := 0
+	for _, r := range m.rings {
+		if r.size() == n {
+			c++
+		}
+	}
+
+	return c
+}
+
+// HasAromaticRingOfSize answers if this molecule has at least one
+// aromatic ring of the given size.  Stale rings and aromaticity are
+// determined afresh; `false` is answered if that fails.
+func (m *Molecule) HasAromaticRingOfSize(n int) bool {
+	if m.refresh(stageAromaticity) != nil {
+		return false
+	}
+
+	for _, r := range m.rings {
+		if r.isAro && r.size() == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RingSizeHistogram answers the number of rings detected in this
+// molecule, keyed by their sizes.  Stale rings are perceived afresh;
+// an empty histogram is answered if that fails.
+func (m *Molecule) RingSizeHistogram() map[int]int {
+	h := make(map[int]int)
+	if m.refresh(stageRings) != nil {
+		return h
+	}
+
+	for _, r := range m.rings {
+		h[r.size()]++
+	}
+
+	return h
+}
+
+// IsolatedAromaticRingCount answers the number of aromatic rings in
+// this molecule that share no bond with any other aromatic ring, as
+// in benzene or biphenyl.
+//
+// Stale rings and aromaticity are determined afresh; `0` is answered
+// if that fails.
+func (m *Molecule) IsolatedAromaticRingCount() int {
+	if m.refresh(stageAromaticity) != nil {
+		return 0
+	}
+
+	c := 0
+	for _, r := range m.rings {
+		if r.isAro && !r.isFusedAromatic() {
+			c++
+		}
+	}
+
+	return c
+}
+
+// FusedAromaticRingCount answers the number of aromatic rings in this
+// molecule that share at least one bond with another aromatic ring, as
+// in naphthalene.  Each ring of a fused system is counted.
+//
+// Stale rings and aromaticity are determined afresh; `0` is answered
+// if that fails.
+func (m *Molecule) FusedAromaticRingCount() int {
+	if m.refresh(stageAromaticity) != nil {
+		return 0
+	}
+
+	c := 0
+	for _, r := range m.rings {
+		if r.isFusedAromatic() {
+			c++
+		}
+	}
+
+	return c
+}
+
+// Formula answers the molecular formula of this molecule, in Hill
+// order: carbon first, hydrogen next, and then the remaining elements
+// in alphabetical order of their symbols.  When carbon is absent, all
+// the elements - including hydrogen - are in alphabetical order.
+//
+// A count of `1` is omitted, as is conventional.  Residual charges are
+// not reflected in the formula.
+func (m *Molecule) Formula() string {
+	return m.formula(true)
+}
+
+// formula answers the molecular formula of this molecule, as described
+// in `Formula`.  The hydrogen atoms attached to the atoms are counted
+// only when `withH` is `true`; hydrogen atoms held as atoms are always
+// counted.
+func (m *Molecule) formula(withH bool) string {
+	counts := make(map[string]int)
+	for _, a := range m.atoms {
+		counts[cmn.ElementSymbols[a.atNum]]++
+		if withH && a.hCount > 0 {
+			counts["H"] += int(a.hCount)
+		}
+	}
+
+	syms := make([]string, 0, len(counts))
+	for sym := range counts {
+		if counts["C"] > 0 && (sym == "C" || sym == "H") {
+			continue
+		}
+		syms = append(syms, sym)
+	}
+	sort.Strings(syms)
+	if counts["C"] > 0 {
+		syms = append([]string{"C", "H"}, syms...)
+	}
+
+	var buf bytes.Buffer
+	for _, sym := range syms {
+		c := counts[sym]
+		if c == 0 {
+			continue
+		}
+		buf.WriteString(sym)
+		if c > 1 {
+			fmt.Fprintf(&buf, "%d", c)
+		}
+	}
+
+	return buf.String()
+}
+
+// Sp3CarbonFraction answers the fraction of the carbon atoms of this
+// molecule that are sp3-hybridised (Fsp3), a common measure of
+// drug-likeness.  Answers `0` if this molecule has no carbon atoms.
+// See `Atom.Hybridization`.
+//
+// Stale rings and aromaticity are determined afresh, for aromatic
+// carbon atoms to be recognised as such; `0` is answered if that
+// fails.
+func (m *Molecule) Sp3CarbonFraction() float64 {
+	if m.refresh(stageAromaticity) != nil {
+		return 0
+	}
+
+	nc, nsp3 := 0, 0
+	for _, a := range m.atoms {
+		if a.atNum != 6 {
+			continue
+		}
+		nc++
+		if a.hybridization() == cmn.HybridizationSp3 {
+			nsp3++
+		}
+	}
+
+	if nc == 0 {
+		return 0
+	}
+	return float64(nsp3) / float64(nc)
+}
+
+// AtomWithMaxDegree answers a view of the atom having the largest
+// number of bonded atoms in this molecule, together with that number.
+// Ties are broken in favour of the atom input first.  Answers a zero
+// `Atom` and `0` if this molecule has no atoms.
+func (m *Molecule) AtomWithMaxDegree() (Atom, int) {
+	best := Atom{}
+	max := -1
+	for _, a := range m.atoms {
+		if d := int(a.bonds.Count()); d > max {
+			best, max = Atom{a}, d
+		}
+	}
+
+	if max < 0 {
+		return Atom{}, 0
+	}
+	return best, max
+}
+
+// WienerIndex answers the Wiener index of this molecule: the sum of
+// the topological distances between all pairs of its atoms, each pair
+// counted once.
+//
+// Answers an error if this molecule has not been normalised, or if it
+// is not connected.
+func (m *Molecule) WienerIndex() (int, error) {
+	if !m.isNormalised {
+		return 0, fmt.Errorf("Molecule %d has not been normalised.", m.id)
+	}
+
+	sum := 0
+	for i, row := range m.dists {
+		for _, d := range row[i+1:] {
+			if d < 0 {
+				return 0, fmt.Errorf("Molecule %d is not connected.", m.id)
+			}
+			sum += d
+		}
+	}
+
+	return sum, nil
+}
+
+// RandicIndex answers the Randić connectivity index of this molecule:
+// the sum, over its bonds, of `1/sqrt(d1*d2)`, where `d1` and `d2` are
+// the heavy-atom degrees of the atoms of the bond.
+func (m *Molecule) RandicIndex() float64 {
+	sum := 0.0
+	for _, b := range m.bonds {
+		d1 := m.atomWithIid(b.a1).heavyDegree()
+		d2 := m.atomWithIid(b.a2).heavyDegree()
+		sum += 1 / math.Sqrt(float64(d1*d2))
+	}
+
+	return sum
+}
+
+// BalabanIndex answers the Balaban J index of this molecule:
+//
+//	J = M / (μ + 1) * Σ 1/sqrt(s1*s2)
+//
+// where `M` is the number of bonds, `μ = M - N + 1` is the cyclomatic
+// number for `N` atoms, and the sum runs over the bonds, with `s1` and
+// `s2` the sums of the topological distances from the atoms of the
+// bond to all the atoms.  Bond orders are not taken into account.  A
+// molecule without bonds has an index of `0`.
+//
+// Answers an error if this molecule has not been normalised, or if it
+// is not connected.
+func (m *Molecule) BalabanIndex() (float64, error) {
+	if !m.isNormalised {
+		return 0, fmt.Errorf("Molecule %d has not been normalised.", m.id)
+	}
+	if !m.IsConnected() {
+		return 0, fmt.Errorf("Molecule %d is not connected.", m.id)
+	}
+
+	// For a connected molecule, `μ + 1` is at least `1`.  The distance
+	// sums vanish only for a lone atom, which has no bonds.
+	nb := len(m.bonds)
+	if nb == 0 {
+		return 0, nil
+	}
+	mu := nb - len(m.atoms) + 1
+
+	sums := make([]int, len(m.dists))
+	for i, row := range m.dists {
+		for _, d := range row {
+			sums[i] += d
+		}
+	}
+
+	sum := 0.0
+	for _, b := range m.bonds {
+		s1 := sums[m.atomWithIid(b.a1).nId-1]
+		s2 := sums[m.atomWithIid(b.a2).nId-1]
+		sum += 1 / math.Sqrt(float64(s1*s2))
+	}
+
+	return float64(nb) / float64(mu+1) * sum, nil
+}
+
+// AverageWeight answers the molecular weight of this molecule, using
+// the average atomic weights of the elements.  Atoms marked as
+// specific isotopes contribute the masses of those isotopes.
+func (m *Molecule) AverageWeight() float64 {
+	hw := cmn.PeriodicTable["H"].Weight
+
+	sum := 0.0
+	for _, a := range m.atoms {
+		sum += a.mass(false) + float64(a.hCount)*hw
+	}
+
+	return sum
+}
+
+// MonoisotopicWeight answers the monoisotopic mass of this molecule,
+// using the masses of the most abundant isotopes of the elements.
+// Atoms marked as specific isotopes contribute the masses of those
+// isotopes.
+func (m *Molecule) MonoisotopicWeight() float64 {
+	hw := cmn.MonoisotopicMass(1)
+
+	sum := 0.0
+	for _, a := range m.atoms {
+		sum += a.mass(true) + float64(a.hCount)*hw
+	}
+
+	return sum
+}
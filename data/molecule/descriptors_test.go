@@ -0,0 +1,88 @@
+package molecule
+
+import (
+	"math"
+	"testing"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// buildAlkane answers the unbranched alkane with the given number of
+// carbon atoms, normalised.
+func buildAlkane(t *testing.T, n int) *Molecule {
+	mb := NewMoleculeBuilder()
+	var prev uint16
+	for i := 0; i < n; i++ {
+		aid, err := mb.AddAtom("C", float32(i)*1.3, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i > 0 {
+			if err := mb.AddBond(prev, aid, cmn.BondTypeSingle); err != nil {
+				t.Fatal(err)
+			}
+		}
+		prev = aid
+	}
+
+	m, err := mb.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestWienerIndexAlkanes(t *testing.T) {
+	// The Wiener index of the n-alkane with `n` carbon atoms is
+	// `(n - 1) n (n + 1) / 6`.
+	cases := []struct{ n, w int }{
+		{1, 0}, {2, 1}, {3, 4}, {4, 10}, {5, 20}, {6, 35}, {8, 84}, {10, 165},
+	}
+	for _, c := range cases {
+		m := buildAlkane(t, c.n)
+		w, err := m.WienerIndex()
+		if err != nil {
+			t.Errorf("C%d : %v", c.n, err)
+		} else if w != c.w {
+			t.Errorf("C%d : Wiener index %d; want %d", c.n, w, c.w)
+		}
+		m.discard()
+	}
+}
+
+func TestWienerIndexDisconnected(t *testing.T) {
+	mb := NewMoleculeBuilder()
+	for _, sym := range []string{"Na", "Cl"} {
+		if _, err := mb.AddAtom(sym, 0, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m, err := mb.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.discard()
+
+	if _, err := m.WienerIndex(); err == nil {
+		t.Error("Wiener index answered for a disconnected molecule")
+	}
+}
+
+func TestTPSA(t *testing.T) {
+	// Reference values are those of Ertl et al.
+	cases := []struct {
+		name  string
+		syms  []string
+		bonds []_TestBond
+		tpsa  float64
+	}{
+		{"ethanol", []string{"C", "C", "O"}, testBonds([][3]int{{1, 2, 1}, {2, 3, 1}}), 20.23},
+		{"acetic acid", []string{"C", "C", "O", "O"}, testBonds([][3]int{{1, 2, 1}, {2, 3, 2}, {2, 4, 1}}), 37.3},
+	}
+	for _, c := range cases {
+		m := buildMolecule(t, c.syms, c.bonds)
+		if tpsa := m.TPSA(); math.Abs(tpsa-c.tpsa) > 0.005 {
+			t.Errorf("%s : TPSA %.2f; want %.2f", c.name, tpsa, c.tpsa)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package molecule
+
+import (
+	"fmt"
+	"sort"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// MoleculeDiff describes the differences between two molecules whose
+// atoms are numbered comparably: an atom in one molecule corresponds
+// to that with the same input ID in the other.  See `Diff`.
+type MoleculeDiff struct {
+	AddedAtoms   []uint16 // Input IDs of atoms only in the second molecule.
+	RemovedAtoms []uint16 // Input IDs of atoms only in the first molecule.
+
+	AddedBonds   []BondDiff // Bonds only in the second molecule.
+	RemovedBonds []BondDiff // Bonds only in the first molecule.
+	ChangedBonds []BondDiff // Bonds whose order or stereo changed.
+}
+
+// IsEmpty answers if this diff reports no differences.
+func (d MoleculeDiff) IsEmpty() bool {
+	return len(d.AddedAtoms) == 0 && len(d.RemovedAtoms) == 0 &&
+		len(d.AddedBonds) == 0 && len(d.RemovedBonds) == 0 && len(d.ChangedBonds) == 0
+}
+
+// BondDiff describes a bond between two atoms, as it is in each of two
+// molecules.
+type BondDiff struct {
+	Atom1 uint16 // Smaller of the input IDs of the two atoms.
+	Atom2 uint16 // Larger of the input IDs of the two atoms.
+
+	Before cmn.BondType // Bond type in the first molecule; `BondTypeNone` if absent.
+	After  cmn.BondType // Bond type in the second molecule; `BondTypeNone` if absent.
+
+	StereoBefore cmn.StereoParity // E/Z configuration in the first molecule.
+	StereoAfter  cmn.StereoParity // E/Z configuration in the second molecule.
+}
+
+// _BondDiffs orders bond diffs by the input IDs of their atoms.
+type _BondDiffs []BondDiff
+
+func (bds _BondDiffs) Len() int {
+	return len(bds)
+}
+
+func (bds _BondDiffs) Swap(i, j int) {
+	bds[i], bds[j] = bds[j], bds[i]
+}
+
+func (bds _BondDiffs) Less(i, j int) bool {
+	if bds[i].Atom1 != bds[j].Atom1 {
+		return bds[i].Atom1 < bds[j].Atom1
+	}
+	return bds[i].Atom2 < bds[j].Atom2
+}
+
+// Diff answers the differences between the two given molecules, which
+// should number their atoms comparably, such as a molecule before and
+// after an edit.  No attempt is made to match atoms otherwise.
+//
+// Atoms are compared by their input IDs alone.  Bonds are compared by
+// the input IDs of their atoms; a bond present in both molecules is
+// changed if its type or E/Z configuration differs.  All lists are in
+// increasing order of input IDs.
+//
+// Answers an error if the molecules have no input ID in common.
+func Diff(a, b *Molecule) (MoleculeDiff, error) {
+	var d MoleculeDiff
+
+	common := 0
+	for _, at := range a.atoms {
+		if b.atomWithIid(at.iId) == nil {
+			d.RemovedAtoms = append(d.RemovedAtoms, at.iId)
+		} else {
+			common++
+		}
+	}
+	if common == 0 {
+		return d, fmt.Errorf("Molecules %d and %d have no atom input IDs in common.", a.id, b.id)
+	}
+	for _, bt := range b.atoms {
+		if a.atomWithIid(bt.iId) == nil {
+			d.AddedAtoms = append(d.AddedAtoms, bt.iId)
+		}
+	}
+
+	aBonds, bBonds := a.bondsByAtoms(), b.bondsByAtoms()
+	for ids, ab := range aBonds {
+		bb, ok := bBonds[ids]
+		switch {
+		case !ok:
+			d.RemovedBonds = append(d.RemovedBonds, BondDiff{ids[0], ids[1], ab.bType, cmn.BondTypeNone, ab.stereoParity, cmn.StereoParityNone})
+		case ab.bType != bb.bType || ab.stereoParity != bb.stereoParity:
+			d.ChangedBonds = append(d.ChangedBonds, BondDiff{ids[0], ids[1], ab.bType, bb.bType, ab.stereoParity, bb.stereoParity})
+		}
+	}
+	for ids, bb := range bBonds {
+		if _, ok := aBonds[ids]; !ok {
+			d.AddedBonds = append(d.AddedBonds, BondDiff{ids[0], ids[1], cmn.BondTypeNone, bb.bType, cmn.StereoParityNone, bb.stereoParity})
+		}
+	}
+
+	sort.Sort(_Uint16s(d.AddedAtoms))
+	sort.Sort(_Uint16s(d.RemovedAtoms))
+	sort.Sort(_BondDiffs(d.AddedBonds))
+	sort.Sort(_BondDiffs(d.RemovedBonds))
+	sort.Sort(_BondDiffs(d.ChangedBonds))
+	return d, nil
+}
+
+// bondsByAtoms answers the bonds of this molecule, keyed by the input
+// IDs of their atoms, the smaller first.
+func (m *Molecule) bondsByAtoms() map[[2]uint16]*_Bond {
+	bonds := make(map[[2]uint16]*_Bond, len(m.bonds))
+	for _, b := range m.bonds {
+		a1, a2 := b.a1, b.a2
+		if a1 > a2 {
+			a1, a2 = a2, a1
+		}
+		bonds[[2]uint16{a1, a2}] = b
+	}
+
+	return bonds
+}
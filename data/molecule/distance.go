@@ -0,0 +1,101 @@
+package molecule
+
+import (
+	"fmt"
+	"sort"
+)
+
+// computeDistances populates the matrix of topological distances
+// between the atoms of this molecule.  The distance between two atoms
+// is the number of bonds in a shortest path between them.  Atoms in
+// different connected components are at a distance of `-1`.
+//
+// The matrix is indexed by the normalised IDs of the atoms, less one.
+// Hence, normalised IDs must have been assigned to the atoms, before
+// this method is invoked.
+func (m *Molecule) computeDistances() {
+	n := len(m.atoms)
+	m.dists = make([][]int, n)
+
+	for _, root := range m.atoms {
+		row := make([]int, n)
+		for i := range row {
+			row[i] = -1
+		}
+		row[root.nId-1] = 0
+
+		queue := []*_Atom{root}
+		for len(queue) > 0 {
+			a := queue[0]
+			queue = queue[1:]
+
+			d := row[a.nId-1]
+			for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+				oa := m.atomWithIid(m.bondWithId(uint16(bid)).otherAtomIid(a.iId))
+				if row[oa.nId-1] >= 0 {
+					continue
+				}
+				row[oa.nId-1] = d + 1
+				queue = append(queue, oa)
+			}
+		}
+
+		m.dists[root.nId-1] = row
+	}
+}
+
+// distanceBetween answers the topological distance between the two
+// given atoms.  See `computeDistances`.
+func (m *Molecule) distanceBetween(a1, a2 *_Atom) int {
+	return m.dists[a1.nId-1][a2.nId-1]
+}
+
+// Neighbourhood answers the input IDs of the atoms within the given
+// number of bonds of the given centre atom, including the centre
+// itself.  The atoms are answered in the order of their normalised
+// IDs; the answer, therefore, does not depend on the input order of
+// the atoms.
+//
+// A radius of `0` answers only the centre.  This molecule should have
+// been normalised.
+func (m *Molecule) Neighbourhood(center uint16, radius int) ([]uint16, error) {
+	if !m.isNormalised {
+		return nil, fmt.Errorf("Molecule %d has not been normalised.", m.id)
+	}
+	root := m.atomWithIid(center)
+	if root == nil {
+		return nil, fmt.Errorf("Unknown atom input ID given : %d", center)
+	}
+	if radius < 0 {
+		return nil, fmt.Errorf("Invalid radius given : %d", radius)
+	}
+
+	depth := map[uint16]int{root.iId: 0}
+	nids := []uint16{root.nId}
+	queue := []*_Atom{root}
+	for len(queue) > 0 {
+		a := queue[0]
+		queue = queue[1:]
+
+		d := depth[a.iId]
+		if d == radius {
+			continue
+		}
+		for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+			oa := m.atomWithIid(m.bondWithId(uint16(bid)).otherAtomIid(a.iId))
+			if _, ok := depth[oa.iId]; ok {
+				continue
+			}
+			depth[oa.iId] = d + 1
+			nids = append(nids, oa.nId)
+			queue = append(queue, oa)
+		}
+	}
+
+	sort.Sort(_Uint16s(nids))
+	ids := make([]uint16, len(nids))
+	for i, nid := range nids {
+		ids[i] = m.atomWithNid(nid).iId
+	}
+	return ids, nil
+}
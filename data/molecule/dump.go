@@ -0,0 +1,75 @@
+package molecule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Dump answers a multi-line, human-readable description of the
+// structure of this molecule, for debugging and for golden-file
+// tests.  It lists the atoms, the bonds, the rings and the ring
+// systems, each in increasing order of their IDs, one per line.
+//
+// The layout is fixed, and does not depend on the molecule ID; two
+// molecules with the same atoms, bonds and perceived properties answer
+// the same text.  Normalised IDs are shown as `-` until assigned.
+func (m *Molecule) Dump() string {
+	var sb strings.Builder
+
+	aids := make([]uint16, 0, len(m.atoms))
+	for _, a := range m.atoms {
+		aids = append(aids, a.iId)
+	}
+	sort.Sort(_Uint16s(aids))
+
+	fmt.Fprintf(&sb, "Atoms : %d\n", len(aids))
+	fmt.Fprintf(&sb, "%5s %5s %-3s %5s %3s %4s %-13s %-5s %s\n", "iId", "nId", "El", "Bonds", "H", "Chg", "Unsat", "Aro", "Rings")
+	for _, aid := range aids {
+		a := m.atomWithIid(aid)
+		nid := "-"
+		if a.nId != 0 {
+			nid = fmt.Sprint(a.nId)
+		}
+
+		var rings strings.Builder
+		writeBitSet(&rings, a.rings)
+		fmt.Fprintf(&sb, "%5d %5s %-3s %5d %3d %4d %-13s %-5t %s\n",
+			a.iId, nid, a.symbol, a.bonds.Count(), a.hCount, a.charge, a.unsaturation, a.isInAroRing, rings.String())
+	}
+
+	bids := make([]uint16, 0, len(m.bonds))
+	for _, b := range m.bonds {
+		bids = append(bids, b.id)
+	}
+	sort.Sort(_Uint16s(bids))
+
+	fmt.Fprintf(&sb, "Bonds : %d\n", len(bids))
+	fmt.Fprintf(&sb, "%5s %5s %5s %4s %6s %-5s %s\n", "Id", "Atom1", "Atom2", "Type", "Stereo", "Aro", "Rings")
+	for _, bid := range bids {
+		b := m.bondWithId(bid)
+
+		var rings strings.Builder
+		writeIds(&rings, len(b.rings), func(i int) int { return int(b.rings[i]) })
+		fmt.Fprintf(&sb, "%5d %5d %5d %4c %6d %-5t %s\n",
+			b.id, b.a1, b.a2, bondOrderSymbol(b.bType), b.bStereo, b.isAro, rings.String())
+	}
+
+	// Rings and ring systems are held in the order of their IDs.
+	fmt.Fprintf(&sb, "Rings : %d\n", len(m.rings))
+	for _, r := range m.rings {
+		sb.WriteString(r.String())
+		sb.WriteByte('\n')
+	}
+
+	fmt.Fprintf(&sb, "Ring systems : %d\n", len(m.ringSystems))
+	for _, rs := range m.ringSystems {
+		fmt.Fprintf(&sb, "S#%d rings=", rs.id)
+		writeIds(&sb, len(rs.rings), func(i int) int { return int(rs.rings[i]) })
+		sb.WriteString(" atoms=")
+		writeBitSet(&sb, rs.atomBitSet)
+		fmt.Fprintf(&sb, " aromatic=%t\n", rs.isAro)
+	}
+
+	return sb.String()
+}
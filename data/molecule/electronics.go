@@ -0,0 +1,120 @@
+package molecule
+
+import (
+	"fmt"
+)
+
+// computeNeighbourElectronics classifies the neighbours of each atom
+// of this molecule as electron-donating or electron-withdrawing, and
+// records their counts in the atom.  Any previously-recorded counts are
+// discarded.
+//
+// An electron-withdrawing neighbour is counted as saturated when it is
+// singly-bonded to the atom, and as unsaturated otherwise.  Thus, the
+// alpha carbon of a ketone has a saturated electron-withdrawing
+// neighbour in its carbonyl carbon, while the carbonyl carbon has an
+// unsaturated one in its oxygen.  See `isElectronWithdrawingAcross`.
+//
+// Since whether an atom can donate electrons depends on its own
+// electron-withdrawing neighbours, those are counted for all the atoms
+// first.  See `isElectronDonating`.
+//
+// Unsaturation and aromaticity must have been determined - usually by
+// normalising this molecule - before this method is invoked.
+func (m *Molecule) computeNeighbourElectronics() error {
+	for _, a := range m.atoms {
+		a.unsatEwNbrCount = 0
+		a.satEwNbrCount = 0
+		for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+			b := m.bondWithId(uint16(bid))
+			oa := m.atomWithIid(b.otherAtomIid(a.iId))
+			if oa == nil {
+				return fmt.Errorf("Bond %d of atom %d has an unknown atom.", b.id, a.iId)
+			}
+			if !oa.isElectronWithdrawingAcross(b) {
+				continue
+			}
+
+			if b.order() == 1 && !b.isAro {
+				a.satEwNbrCount++
+			} else {
+				a.unsatEwNbrCount++
+			}
+		}
+	}
+
+	for _, a := range m.atoms {
+		a.edNbrCount = 0
+		for _, nid := range a.distinctNeighbours() {
+			if m.atomWithIid(nid).isElectronDonating() {
+				a.edNbrCount++
+			}
+		}
+	}
+
+	return nil
+}
+
+// isElectronWithdrawingAcross answers if this atom withdraws electrons
+// from the neighbour to which it is bound by the given bond.
+//
+// An atom is electron-withdrawing when any of the following holds.
+//
+//   - It is a halogen.
+//   - It carries a positive charge, possibly by way of a dative bond.
+//   - It is a hetero atom bound to the neighbour by a multiple bond, as
+//     the oxygen of a carbonyl group is.
+//   - It is multiply-bonded to a hetero atom other than the neighbour,
+//     as the carbon of a carbonyl or a nitrile group is.
+//   - It is a nitro nitrogen, however depicted.
+//   - It is an aromatic nitrogen with no hydrogen, as in pyridine.
+func (a *_Atom) isElectronWithdrawingAcross(b *_Bond) bool {
+	switch {
+	case a.isHalogen():
+		return true
+	case a.formalCharge() > 0:
+		return true
+	case a.atNum != 6 && b.order() > 1 && !b.isAro:
+		return true
+	case a.isNitroN():
+		return true
+	case a.atNum == 7 && a.isInAroRing && a.hCount == 0 && a.bonds.Count() == 2:
+		return true
+	}
+
+	mol := a.mol
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		ob := mol.bondWithId(uint16(bid))
+		if ob == b || ob.isAro || ob.order() == 1 {
+			continue
+		}
+		if oa := mol.atomWithIid(ob.otherAtomIid(a.iId)); oa.atNum != 6 && oa.atNum != 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MostPolarBond answers the bond of this molecule across which the
+// difference in electronegativities is the largest, together with
+// that difference.  Of several such bonds, the one held first is
+// answered.  See `Bonds`.
+//
+// A molecule with no bonds answers an empty bond and `0`.  Bonds
+// involving atoms of elements whose electronegativity is not known
+// have a polarity of `0`.
+func (m *Molecule) MostPolarBond() (Bond, float64) {
+	var best *_Bond
+	max := 0.0
+	for _, b := range m.bonds {
+		if p := b.polarity(); best == nil || p > max {
+			best, max = b, p
+		}
+	}
+
+	if best == nil {
+		return Bond{}, 0
+	}
+	return Bond{best}, max
+}
@@ -0,0 +1,167 @@
+package molecule
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	bits "github.com/willf/bitset"
+)
+
+// ECFP answers an extended-connectivity (circular) fingerprint of
+// this molecule, in the manner of Morgan, folded into a set of the
+// given number of bits.
+//
+// Each atom is first identified by its pseudo-hash.  In each of the
+// given number of iterations, the identifier of each atom is replaced
+// by a hash of its current identifier, and the pairs of bond order and
+// current identifier of its neighbours, in sorted order.  The
+// identifier of an atom after `i` iterations thus describes its
+// environment out to `i` bonds.  Every identifier so computed sets a
+// bit, modulo the size of the set, unless its environment covers the
+// same atoms as one already recorded.  See `Neighbourhood`.
+//
+// Since the identifiers are derived from invariant attributes, and the
+// atoms are visited in the order of their normalised IDs, the answer
+// does not depend on the input order of the atoms.
+//
+// This molecule should have been normalised; `nil` is answered
+// otherwise, and for a non-positive number of bits.
+func (m *Molecule) ECFP(radius int, nBits int) *bits.BitSet {
+	if !m.isNormalised || nBits <= 0 {
+		return nil
+	}
+
+	n := len(m.atoms)
+	atoms := make([]*_Atom, n)
+	for _, a := range m.atoms {
+		atoms[a.nId-1] = a
+	}
+
+	fp := bits.New(uint(nBits))
+	ids := make([]uint64, n)
+	seen := make(map[string]bool, n*(radius+1))
+	for i, a := range atoms {
+		ids[i] = a.pHash
+		fp.Set(uint(ids[i] % uint64(nBits)))
+		seen[fmt.Sprint([]uint16{a.iId})] = true
+	}
+
+	for iter := 1; iter <= radius; iter++ {
+		next := make([]uint64, n)
+		for i, a := range atoms {
+			pairs := make(_EnvPairs, 0, len(a.nbrs))
+			for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+				b := m.bondWithId(uint16(bid))
+				oa := m.atomWithIid(b.otherAtomIid(a.iId))
+				pairs = append(pairs, _EnvPair{b.rankOrder(), ids[oa.nId-1]})
+			}
+			sort.Sort(pairs)
+
+			vals := make([]int, 0, 2+2*len(pairs))
+			vals = append(vals, iter, int(ids[i]))
+			for _, p := range pairs {
+				vals = append(vals, p.order, int(p.id))
+			}
+			next[i] = hashInts(vals)
+		}
+		ids = next
+
+		for i, a := range atoms {
+			env, err := m.Neighbourhood(a.iId, iter)
+			if err != nil {
+				return nil
+			}
+			key := fmt.Sprint(env)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			fp.Set(uint(ids[i] % uint64(nBits)))
+		}
+	}
+
+	return fp
+}
+
+// Tanimoto answers the Tanimoto (Jaccard) similarity of the two given
+// fingerprints: the number of bits set in both, divided by the number
+// of bits set in either.  Answers `0` when no bit is set in either.
+func Tanimoto(a, b *bits.BitSet) float64 {
+	na, nb, nc := bitCounts(a, b)
+	if na+nb-nc == 0 {
+		return 0
+	}
+
+	return float64(nc) / float64(na+nb-nc)
+}
+
+// Dice answers the Dice similarity of the two given fingerprints:
+// twice the number of bits set in both, divided by the total number of
+// bits set in each.  Answers `0` when no bit is set in either.
+//
+// For any pair of fingerprints, it is at least their Tanimoto
+// similarity.
+func Dice(a, b *bits.BitSet) float64 {
+	na, nb, nc := bitCounts(a, b)
+	if na+nb == 0 {
+		return 0
+	}
+
+	return 2 * float64(nc) / float64(na+nb)
+}
+
+// Cosine answers the cosine similarity of the two given fingerprints:
+// the number of bits set in both, divided by the geometric mean of the
+// numbers of bits set in each.  Answers `0` when either has no bit
+// set.
+func Cosine(a, b *bits.BitSet) float64 {
+	na, nb, nc := bitCounts(a, b)
+	if na == 0 || nb == 0 {
+		return 0
+	}
+
+	return float64(nc) / math.Sqrt(float64(na)*float64(nb))
+}
+
+// bitCounts answers the numbers of bits set in each of the given
+// fingerprints, and in both.  A `nil` fingerprint has no bits set.
+func bitCounts(a, b *bits.BitSet) (na, nb, nc uint) {
+	if a != nil {
+		na = a.Count()
+	}
+	if b != nil {
+		nb = b.Count()
+	}
+	if a != nil && b != nil {
+		nc = a.IntersectionCardinality(b)
+	}
+	return na, nb, nc
+}
+
+// _EnvPair is a neighbour of an atom, as seen when computing circular
+// fingerprints.  It holds the order of the bond to the neighbour, and
+// the current identifier of the neighbour.
+type _EnvPair struct {
+	order int
+	id    uint64
+}
+
+// _EnvPairs sorts neighbours on their bond orders, and then on their
+// identifiers.
+type _EnvPairs []_EnvPair
+
+func (ps _EnvPairs) Len() int {
+	return len(ps)
+}
+
+func (ps _EnvPairs) Less(i, j int) bool {
+	if ps[i].order != ps[j].order {
+		return ps[i].order < ps[j].order
+	}
+	return ps[i].id < ps[j].id
+}
+
+func (ps _EnvPairs) Swap(i, j int) {
+	ps[i], ps[j] = ps[j], ps[i]
+}
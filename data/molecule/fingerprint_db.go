@@ -0,0 +1,160 @@
+package molecule
+
+import (
+	bits "github.com/willf/bitset"
+)
+
+// Hit is a result of a similarity search: the ID of a fingerprint
+// found, and its similarity to the query.
+type Hit struct {
+	Id         string  // ID with which the fingerprint was added.
+	Similarity float64 // Tanimoto similarity to the query.
+}
+
+// FingerprintDB is an in-memory collection of fingerprints, that can
+// be searched for those most similar to a query.
+//
+// Fingerprints are grouped by the number of bits set in them.  The
+// Tanimoto similarity of two fingerprints with `a` and `b` bits set
+// can not exceed `min(a, b) / max(a, b)`; hence, groups that can not
+// improve upon the hits found so far are skipped altogether.
+//
+// A database is not safe for concurrent modification.  Searches may
+// run concurrently with one another.
+type FingerprintDB struct {
+	byCount [][]_FpEntry // Fingerprints, indexed by the number of bits set.
+	size    int          // Total number of fingerprints.
+}
+
+// _FpEntry is a fingerprint held in a database.
+type _FpEntry struct {
+	id  string       // ID with which the fingerprint was added.
+	seq int          // Order in which the fingerprint was added.
+	fp  *bits.BitSet // The fingerprint itself.
+}
+
+// NewFingerprintDB creates and initialises an empty fingerprint
+// database.
+func NewFingerprintDB() *FingerprintDB {
+	return new(FingerprintDB)
+}
+
+// Add adds the given fingerprint to this database, under the given
+// ID.  IDs are not checked for uniqueness.  A `nil` fingerprint is
+// treated as one with no bits set.
+//
+// The fingerprint is referred to, not copied; it should not be
+// modified afterwards.
+func (db *FingerprintDB) Add(id string, fp *bits.BitSet) {
+	if fp == nil {
+		fp = bits.New(0)
+	}
+
+	c := int(fp.Count())
+	for len(db.byCount) <= c {
+		db.byCount = append(db.byCount, nil)
+	}
+	db.byCount[c] = append(db.byCount[c], _FpEntry{id, db.size, fp})
+	db.size++
+}
+
+// Len answers the number of fingerprints in this database.
+func (db *FingerprintDB) Len() int {
+	return db.size
+}
+
+// NearestNeighbours answers the given number of fingerprints in this
+// database that are most similar to the given query, by Tanimoto
+// similarity.  See `Tanimoto`.
+//
+// Hits are answered in descending order of similarity; fingerprints
+// equally similar are answered in the order in which they were added.
+// Fewer hits are answered if the database holds fewer fingerprints.
+func (db *FingerprintDB) NearestNeighbours(query *bits.BitSet, k int) []Hit {
+	if k <= 0 || db.size == 0 {
+		return nil
+	}
+
+	q := 0
+	if query != nil {
+		q = int(query.Count())
+	}
+
+	// Visit the groups in the order of decreasing upper bounds, which
+	// is that of increasing distance from the query's bit count.
+	top := make([]_FpHit, 0, k+1)
+	for d := 0; q-d >= 0 || q+d < len(db.byCount); d++ {
+		cs := []int{q - d, q + d}
+		if d == 0 {
+			cs = cs[:1]
+		}
+
+		visited := false
+		for _, c := range cs {
+			if c < 0 || c >= len(db.byCount) {
+				continue
+			}
+			if len(top) == k && tanimotoBound(q, c) < top[k-1].Similarity {
+				continue
+			}
+
+			visited = true
+			for _, e := range db.byCount[c] {
+				top = insertHit(top, _FpHit{Hit{e.id, Tanimoto(query, e.fp)}, e.seq}, k)
+			}
+		}
+
+		// Bounds only decrease farther away from the query.
+		if !visited && len(top) == k {
+			break
+		}
+	}
+
+	hits := make([]Hit, len(top))
+	for i, h := range top {
+		hits[i] = h.Hit
+	}
+	return hits
+}
+
+// _FpHit is a hit, together with the order in which its fingerprint
+// was added.
+type _FpHit struct {
+	Hit
+	seq int
+}
+
+// insertHit inserts the given hit into the given list, which is in
+// descending order of similarity, retaining at most the given number
+// of hits.
+func insertHit(top []_FpHit, h _FpHit, k int) []_FpHit {
+	i := len(top)
+	for i > 0 && (top[i-1].Similarity < h.Similarity ||
+		(top[i-1].Similarity == h.Similarity && top[i-1].seq > h.seq)) {
+		i--
+	}
+	if i >= k {
+		return top
+	}
+
+	top = append(top, _FpHit{})
+	copy(top[i+1:], top[i:])
+	top[i] = h
+	if len(top) > k {
+		top = top[:k]
+	}
+	return top
+}
+
+// tanimotoBound answers the highest Tanimoto similarity possible
+// between fingerprints with the given numbers of bits set.
+func tanimotoBound(a, b int) float64 {
+	if a > b {
+		a, b = b, a
+	}
+	if b == 0 {
+		return 0
+	}
+
+	return float64(a) / float64(b)
+}
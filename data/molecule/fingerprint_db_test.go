@@ -0,0 +1,137 @@
+package molecule
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+	bits "github.com/willf/bitset"
+)
+
+// syntheticFingerprints answers the given number of random 512-bit
+// fingerprints, of widely varying densities.
+func syntheticFingerprints(n int, r *rand.Rand) []*bits.BitSet {
+	fps := make([]*bits.BitSet, n)
+	for i := range fps {
+		fp := bits.New(512)
+		for j := r.Intn(120); j > 0; j-- {
+			fp.Set(uint(r.Intn(512)))
+		}
+		fps[i] = fp
+	}
+	return fps
+}
+
+func newSyntheticDB(fps []*bits.BitSet) *FingerprintDB {
+	db := NewFingerprintDB()
+	for i, fp := range fps {
+		db.Add(strconv.Itoa(i), fp)
+	}
+	return db
+}
+
+// TestNearestNeighbours checks the pruned search against an exhaustive
+// one.
+func TestNearestNeighbours(t *testing.T) {
+	const k = 10
+
+	r := rand.New(rand.NewSource(1))
+	fps := syntheticFingerprints(3000, r)
+	db := newSyntheticDB(fps)
+	if db.Len() != len(fps) {
+		t.Fatalf("%d fingerprints held; want %d", db.Len(), len(fps))
+	}
+
+	for _, q := range syntheticFingerprints(20, r) {
+		sims := make([]float64, len(fps))
+		for i, fp := range fps {
+			sims[i] = Tanimoto(q, fp)
+		}
+		sort.Sort(sort.Reverse(sort.Float64Slice(sims)))
+
+		hits := db.NearestNeighbours(q, k)
+		if len(hits) != k {
+			t.Fatalf("%d hits; want %d", len(hits), k)
+		}
+		for i, h := range hits {
+			if h.Similarity != sims[i] {
+				t.Errorf("hit %d has similarity %f; want %f", i, h.Similarity, sims[i])
+			}
+			if id, _ := strconv.Atoi(h.Id); Tanimoto(q, fps[id]) != h.Similarity {
+				t.Errorf("hit %d : similarity %f does not belong to %s", i, h.Similarity, h.Id)
+			}
+		}
+	}
+
+	if hits := db.NearestNeighbours(nil, 0); hits != nil {
+		t.Errorf("%d hits for k = 0; want none", len(hits))
+	}
+}
+
+func BenchmarkNearestNeighbours(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	db := newSyntheticDB(syntheticFingerprints(5000, r))
+	qs := syntheticFingerprints(100, r)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		db.NearestNeighbours(qs[i%len(qs)], 10)
+	}
+}
+
+// buildLadder builds a saturated ladder of the given number of fused
+// six-membered rings, as in the perhydro derivative of an acene.
+func buildLadder(b *testing.B, rings int) *Molecule {
+	mb := NewMoleculeBuilder()
+	add := func() uint16 {
+		aid, err := mb.AddAtom("C", 0, 0, 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		return aid
+	}
+	bond := func(a1, a2 uint16) {
+		if err := mb.AddBond(a1, a2, cmn.BondTypeSingle); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	// Each ring shares its left rung with the previous ring.
+	top, bottom := add(), add()
+	bond(top, bottom)
+	for i := 0; i < rings; i++ {
+		t1, t2, b1, b2 := add(), add(), add(), add()
+		bond(top, t1)
+		bond(t1, t2)
+		bond(bottom, b1)
+		bond(b1, b2)
+		bond(t2, b2)
+		top, bottom = t2, b2
+	}
+
+	m, err := mb.Finish()
+	if err != nil {
+		b.Fatal(err)
+	}
+	return m
+}
+
+// BenchmarkIdLookups walks every bond of a large fused-ring structure,
+// looking up the bond and its atoms by their IDs.
+func BenchmarkIdLookups(b *testing.B) {
+	m := buildLadder(b, 100)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, a := range m.atoms {
+			for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+				bd := m.bondWithId(uint16(bid))
+				if m.atomWithIid(bd.otherAtomIid(a.iId)) == nil {
+					b.Fatalf("bond %d : atom not found", bid)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package molecule
+
+import (
+	"math"
+	"testing"
+
+	bits "github.com/willf/bitset"
+)
+
+// TestSimilarityOrdering checks the similarities of a fixed pair of
+// fingerprints, with four and three bits set, two in common.  For any
+// pair, Tanimoto similarity is at most the Dice similarity, which is
+// at most the cosine similarity.
+func TestSimilarityOrdering(t *testing.T) {
+	const eps = 1e-9
+
+	a := bits.New(64).Set(1).Set(2).Set(3).Set(4)
+	b := bits.New(64).Set(3).Set(4).Set(5)
+
+	tan, dice, cos := Tanimoto(a, b), Dice(a, b), Cosine(a, b)
+	if math.Abs(tan-2.0/5) > eps {
+		t.Errorf("Tanimoto %f; want %f", tan, 2.0/5)
+	}
+	if math.Abs(dice-4.0/7) > eps {
+		t.Errorf("Dice %f; want %f", dice, 4.0/7)
+	}
+	if want := 2 / math.Sqrt(12); math.Abs(cos-want) > eps {
+		t.Errorf("cosine %f; want %f", cos, want)
+	}
+	if !(tan < dice && dice < cos) {
+		t.Errorf("Tanimoto %f, Dice %f, cosine %f; want them in ascending order", tan, dice, cos)
+	}
+	// Dice is a monotonic function of Tanimoto.
+	if want := 2 * tan / (1 + tan); math.Abs(dice-want) > eps {
+		t.Errorf("Dice %f; want 2T / (1 + T) = %f", dice, want)
+	}
+
+	// Identical fingerprints are fully similar by every measure,
+	// unless they are empty.
+	for name, sim := range map[string]func(a, b *bits.BitSet) float64{
+		"Tanimoto": Tanimoto, "Dice": Dice, "cosine": Cosine,
+	} {
+		if s := sim(a, a); s != 1 {
+			t.Errorf("%s of a fingerprint with itself %f; want 1", name, s)
+		}
+		if s := sim(bits.New(64), bits.New(64)); s != 0 {
+			t.Errorf("%s of empty fingerprints %f; want 0", name, s)
+		}
+	}
+}
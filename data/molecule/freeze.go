@@ -0,0 +1,48 @@
+package molecule
+
+import "fmt"
+
+// Freeze makes this molecule read-only.  A frozen molecule rejects
+// the methods that edit its structure - adding or removing atoms and
+// bonds, changing bond orders, charges or hydrogen counts, and
+// renumbering its atoms - with an error.  Its derived information,
+// such as rings, aromaticity, normalised IDs and the canonical key,
+// therefore, stays valid for as long as it remains frozen; it can be
+// shared freely.
+//
+// Normalisation freezes a molecule on completion.  Molecules answered
+// normalised - such as those of `MoleculeBuilder.Finish`,
+// `UnmarshalMolecule` and `SplitComponents` - are, hence, frozen.
+// The readers of molfile, SD, XYZ and RXN input do not normalise; the
+// molecules they answer are editable.  Whole-molecule transformations,
+// such as `Standardize`, `Neutralize` and `RenumberCanonically`,
+// unfreeze a molecule for their duration themselves.
+//
+// Coordinates, attributes and stereo perception are not affected by
+// freezing.
+func (m *Molecule) Freeze() {
+	m.frozen = true
+}
+
+// Unfreeze makes this molecule editable again.  Thereafter, each edit
+// marks the derived information it affects as stale, as described in
+// `markStale`, until the molecule is normalised - and frozen - again.
+func (m *Molecule) Unfreeze() {
+	m.frozen = false
+}
+
+// IsFrozen answers if this molecule is read-only.  See `Freeze`.
+func (m *Molecule) IsFrozen() bool {
+	return m.frozen
+}
+
+// checkMutable answers an error if this molecule is frozen.  Methods
+// that edit the structure of a molecule invoke it before anything
+// else.
+func (m *Molecule) checkMutable() error {
+	if m.frozen {
+		return fmt.Errorf("Molecule %d is frozen; it can not be edited.", m.id)
+	}
+
+	return nil
+}
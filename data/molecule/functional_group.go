@@ -0,0 +1,271 @@
+package molecule
+
+import (
+	"fmt"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// DetectFunctionalGroups identifies the functional groups substituted
+// on the carbon atoms of this molecule, and records them as the
+// features of those atoms.  Any previously-recorded features are
+// discarded.
+//
+// The features of each atom are listed in the order of the registry
+// `cmn.FunctionalGroups`, by `sortFeaturesByPriority`; hence, the first
+// is its primary feature.  A
+// group that is substituted more than once on an atom - as in a
+// trichloromethyl group - is recorded as many times.
+//
+// Groups are identified as follows.
+//
+//   - A carbonyl carbon bearing a singly-bonded oxygen is a carboxyl,
+//     an ester or an anhydride, as determined by `carboxylKind`; one
+//     bearing a nitrogen is an amide; any other is a carbonyl.
+//   - A carbon triply-bonded to a nitrogen is a nitrile.
+//   - A nitrogen bearing two oxygen atoms is a nitro group.  Any other
+//     non-aromatic nitrogen singly-bonded to a carbon is an amine,
+//     unless it is an amide nitrogen.
+//   - Hydroxyl, thiol, ether and halide groups are recognised from
+//     the singly-bonded neighbour concerned.
+//
+// Atoms in aromatic rings are not considered to be substituents of
+// one another.  The oxygen and nitrogen atoms of carboxyl and amide
+// groups are not additionally reported as hydroxyl, ether or amine
+// groups on the carbonyl carbon.
+//
+// Unsaturation and aromaticity must have been determined - usually by
+// normalising this molecule - before this method is invoked.
+func (m *Molecule) DetectFunctionalGroups() error {
+	for _, a := range m.atoms {
+		a.features = a.features[:0]
+		if a.atNum != 6 {
+			continue
+		}
+
+		kinds := a.substitutedGroups()
+		if len(kinds) > cmn.MaxFeatures {
+			return fmt.Errorf("Atom %d has %d features; at most %d are allowed.", a.iId, len(kinds), cmn.MaxFeatures)
+		}
+
+		for _, fk := range kinds {
+			a.addFeature(fk)
+		}
+		a.sortFeaturesByPriority()
+	}
+
+	return nil
+}
+
+// substitutedGroups answers the kinds of the functional groups
+// substituted on this carbon atom, in no particular order.  See
+// `DetectFunctionalGroups`.
+func (a *_Atom) substitutedGroups() []uint16 {
+	kinds := make([]uint16, 0, cmn.ListSizeTiny)
+
+	isCarbonyl := a.isCarbonylC()
+	var carbonylKind cmn.FeatureKind
+	if isCarbonyl {
+		carbonylKind = cmn.FeatureCarbonyl
+		if fk := a.carboxylKind(); fk != cmn.FeatureNone {
+			carbonylKind = fk
+		}
+	}
+
+	mol := a.mol
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		b := mol.bondWithId(uint16(bid))
+		if b.isAro {
+			continue
+		}
+		oa := mol.atomWithIid(b.otherAtomIid(a.iId))
+
+		switch {
+		case b.bType == cmn.BondTypeTriple && oa.atNum == 7:
+			kinds = append(kinds, uint16(cmn.FeatureNitrile))
+
+		case b.bType != cmn.BondTypeSingle:
+			// Carbonyl oxygen, or a multiple bond to carbon.
+
+		case oa.isHalogen():
+			kinds = append(kinds, uint16(cmn.FeatureHalide))
+
+		case oa.atNum == 8:
+			switch {
+			case isCarbonyl:
+				// Accounted for by `carboxylKind`.
+			case oa.isHydroxyl():
+				kinds = append(kinds, uint16(cmn.FeatureHydroxyl))
+			case oa.isEtherO():
+				kinds = append(kinds, uint16(cmn.FeatureEther))
+			}
+
+		case oa.atNum == 7:
+			switch {
+			case isCarbonyl:
+				if carbonylKind == cmn.FeatureCarbonyl {
+					carbonylKind = cmn.FeatureAmide
+				}
+			case oa.isNitroN():
+				kinds = append(kinds, uint16(cmn.FeatureNitro))
+			case oa.isAmideN():
+				// Substituent on the nitrogen of an amide.
+			case oa.doubleBondCount == 0 && oa.tripleBondCount == 0 && !oa.isInAroRing:
+				kinds = append(kinds, uint16(cmn.FeatureAmine))
+			}
+
+		case oa.atNum == 16 && oa.hCount == 1:
+			kinds = append(kinds, uint16(cmn.FeatureThiol))
+		}
+	}
+
+	if isCarbonyl {
+		kinds = append(kinds, uint16(carbonylKind))
+	}
+	return kinds
+}
+
+// carboxylKind answers the kind of the carboxyl group of which this
+// atom is the carbonyl carbon, judging by its singly-bonded oxygen
+// neighbour.
+//
+// An oxygen bearing a hydrogen - or a negative charge, as in a
+// carboxylate - makes it a carboxyl.  One bonded to another carbonyl
+// carbon makes it an anhydride, and one bonded to any other carbon,
+// an ester.  Answers `FeatureNone` if this atom is not a carbonyl
+// carbon, or has no such oxygen neighbour.
+func (a *_Atom) carboxylKind() cmn.FeatureKind {
+	if !a.isCarbonylC() {
+		return cmn.FeatureNone
+	}
+
+	mol := a.mol
+	_, db := a.firstDoublyBondedNeighbourId()
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		b := mol.bondWithId(uint16(bid))
+		if b == db || b.bType != cmn.BondTypeSingle {
+			continue
+		}
+		oa := mol.atomWithIid(b.otherAtomIid(a.iId))
+		if oa.atNum != 8 {
+			continue
+		}
+
+		if oa.hCount > 0 || oa.charge < 0 {
+			return cmn.FeatureCarboxyl
+		}
+		for _, nid := range oa.distinctNeighbours() {
+			if nid == a.iId {
+				continue
+			}
+			na := mol.atomWithIid(nid)
+			switch {
+			case na.isCarbonylC():
+				return cmn.FeatureAnhydride
+			case na.atNum == 6:
+				return cmn.FeatureEster
+			}
+		}
+	}
+
+	return cmn.FeatureNone
+}
+
+// isEtherO answers if this atom is an oxygen singly-bonded to two
+// carbon atoms, neither of which is a carbonyl carbon.
+func (a *_Atom) isEtherO() bool {
+	if a.atNum != 8 || a.hCount != 0 || a.charge != 0 ||
+		a.bonds.Count() != 2 || a.singleBondCount != 2 {
+		return false
+	}
+
+	mol := a.mol
+	for _, nid := range a.distinctNeighbours() {
+		na := mol.atomWithIid(nid)
+		if na.atNum != 6 || na.isCarbonylC() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isNitroN answers if this atom is a nitrogen bonded to two oxygen
+// atoms, irrespective of the charges used to depict them.
+func (a *_Atom) isNitroN() bool {
+	if a.atNum != 7 {
+		return false
+	}
+
+	mol := a.mol
+	no := 0
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		oa := mol.atomWithIid(mol.bondWithId(uint16(bid)).otherAtomIid(a.iId))
+		if oa.atNum == 8 {
+			no++
+		}
+	}
+
+	return no == 2
+}
+
+// isAmideN answers if this atom is an uncharged, trivalent nitrogen
+// singly-bonded to at least one carbonyl carbon.
+func (a *_Atom) isAmideN() bool {
+	if !a.isTrivalentN() || a.charge != 0 {
+		return false
+	}
+
+	mol := a.mol
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		b := mol.bondWithId(uint16(bid))
+		if b.bType != cmn.BondTypeSingle || b.isAro {
+			continue
+		}
+		if mol.atomWithIid(b.otherAtomIid(a.iId)).isCarbonylC() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// amideClass answers the class of this atom, if it is an amide
+// nitrogen.  Answers `AmideClassNone` otherwise.
+func (a *_Atom) amideClass() cmn.AmideClass {
+	if !a.isAmideN() {
+		return cmn.AmideClassNone
+	}
+
+	switch a.hCount {
+	case 2:
+		return cmn.AmideClassPrimary
+	case 1:
+		return cmn.AmideClassSecondary
+	case 0:
+		return cmn.AmideClassTertiary
+	}
+
+	return cmn.AmideClassNone
+}
+
+// AmideBonds answers the amide linkages in this molecule: the single
+// bonds between carbonyl carbons and amide nitrogens.  In a peptide,
+// these are its peptide bonds, together with any amide bonds in the
+// side chains.
+//
+// These are the same bonds that are excluded from the rotatable bond
+// count.  See `RotatableBondCount`.
+//
+// This molecule should have been normalised, for carbonyl detection to
+// be available.
+func (m *Molecule) AmideBonds() []Bond {
+	bonds := make([]Bond, 0, cmn.ListSizeTiny)
+	for _, b := range m.bonds {
+		if b.isAmideCN() {
+			bonds = append(bonds, Bond{b})
+		}
+	}
+
+	return bonds
+}
@@ -0,0 +1,110 @@
+package molecule
+
+import (
+	"testing"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// TestFunctionalGroupPriority checks methyl chloroformate, whose
+// carbonyl carbon atom carries both an ester and a halide: the ester is
+// its primary feature.
+func TestFunctionalGroupPriority(t *testing.T) {
+	mb := NewMoleculeBuilder()
+	var ids [5]uint16
+	for i, sym := range []string{"Cl", "C", "O", "O", "C"} {
+		aid, err := mb.AddAtom(sym, float32(i), 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = aid
+	}
+	bonds := []struct {
+		a1, a2 int
+		bType  cmn.BondType
+	}{
+		{0, 1, cmn.BondTypeSingle},
+		{1, 2, cmn.BondTypeDouble},
+		{1, 3, cmn.BondTypeSingle},
+		{3, 4, cmn.BondTypeSingle},
+	}
+	for _, b := range bonds {
+		if err := mb.AddBond(ids[b.a1], ids[b.a2], b.bType); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m, err := mb.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.discard()
+
+	a, ok := m.AtomWithIid(ids[1])
+	if !ok {
+		t.Fatal("carbonyl carbon atom not found")
+	}
+	fgs := a.FunctionalGroups()
+	want := []uint16{uint16(cmn.FeatureEster), uint16(cmn.FeatureHalide)}
+	if len(fgs) != len(want) || fgs[0] != want[0] || fgs[1] != want[1] {
+		t.Fatalf("functional groups %v; want %v", fgs, want)
+	}
+	if pfg := a.PrimaryFunctionalGroup(); pfg != uint16(cmn.FeatureEster) {
+		t.Errorf("primary functional group %s; want ester", cmn.FeatureName(pfg))
+	}
+
+	// The answered slice is a copy.
+	fgs[0] = uint16(cmn.FeatureNone)
+	if a.FunctionalGroups()[0] != uint16(cmn.FeatureEster) {
+		t.Error("functional groups of the atom modified through the answered slice")
+	}
+}
+
+func TestSortFeaturesByPriority(t *testing.T) {
+	m := New()
+	defer m.discard()
+
+	a := newAtom(m, 6, 1)
+	a.features = []uint16{uint16(cmn.FeatureHalide), uint16(cmn.FeatureEster), uint16(cmn.FeatureCarboxyl)}
+	a.sortFeaturesByPriority()
+
+	want := []uint16{uint16(cmn.FeatureCarboxyl), uint16(cmn.FeatureEster), uint16(cmn.FeatureHalide)}
+	for i := range want {
+		if a.features[i] != want[i] {
+			t.Fatalf("features %v; want %v", a.features, want)
+		}
+	}
+}
+
+// TestCarboxylKind checks the carbonyl carbon atoms of an acid, an
+// ester and an anhydride of acetic acid, and of acetone.
+func TestCarboxylKind(t *testing.T) {
+	cases := []struct {
+		name  string
+		syms  []string
+		bonds []_TestBond
+		cs    []uint16 // Carbonyl carbon atoms.
+		kind  cmn.FeatureKind
+	}{
+		{"acetic acid", []string{"C", "C", "O", "O"},
+			testBonds([][3]int{{1, 2, 1}, {2, 3, 2}, {2, 4, 1}}),
+			[]uint16{2}, cmn.FeatureCarboxyl},
+		{"methyl acetate", []string{"C", "C", "O", "O", "C"},
+			testBonds([][3]int{{1, 2, 1}, {2, 3, 2}, {2, 4, 1}, {4, 5, 1}}),
+			[]uint16{2}, cmn.FeatureEster},
+		{"acetic anhydride", []string{"C", "C", "O", "O", "C", "O", "C"},
+			testBonds([][3]int{{1, 2, 1}, {2, 3, 2}, {2, 4, 1}, {4, 5, 1}, {5, 6, 2}, {5, 7, 1}}),
+			[]uint16{2, 5}, cmn.FeatureAnhydride},
+		{"acetone", []string{"C", "C", "O", "C"},
+			testBonds([][3]int{{1, 2, 1}, {2, 3, 2}, {2, 4, 1}}),
+			[]uint16{2}, cmn.FeatureNone},
+	}
+	for _, c := range cases {
+		m := buildMolecule(t, c.syms, c.bonds)
+		for _, id := range c.cs {
+			if k := m.atomWithIid(id).carboxylKind(); k != c.kind {
+				t.Errorf("%s : atom %d is %s; want %s", c.name, id, cmn.FeatureName(uint16(k)), cmn.FeatureName(uint16(c.kind)))
+			}
+		}
+		m.discard()
+	}
+}
@@ -0,0 +1,178 @@
+package molecule
+
+import (
+	"fmt"
+	"math"
+)
+
+// Translate moves all the atoms of this molecule by the given offsets
+// along the three axes.
+func (m *Molecule) Translate(dx, dy, dz float32) {
+	for _, a := range m.atoms {
+		a.X += dx
+		a.Y += dy
+		a.Z += dz
+	}
+}
+
+// Scale multiplies the coordinates of all the atoms of this molecule
+// by the given factor, about the origin.
+//
+// A negative factor also inverts the molecule through the origin.
+// Stereo configurations already determined from the coordinates are
+// not updated.  See `PerceiveTetrahedralStereo`.
+func (m *Molecule) Scale(factor float32) {
+	for _, a := range m.atoms {
+		a.X *= factor
+		a.Y *= factor
+		a.Z *= factor
+	}
+}
+
+// CenterAtOrigin translates this molecule so that the centroid of its
+// atoms - the unweighted mean of their coordinates - lies at the
+// origin.  A molecule with no atoms is left as it is.
+func (m *Molecule) CenterAtOrigin() {
+	n := len(m.atoms)
+	if n == 0 {
+		return
+	}
+
+	var sx, sy, sz float64
+	for _, a := range m.atoms {
+		sx += float64(a.X)
+		sy += float64(a.Y)
+		sz += float64(a.Z)
+	}
+
+	fn := float64(n)
+	m.Translate(float32(-sx/fn), float32(-sy/fn), float32(-sz/fn))
+}
+
+// BoundingBox answers the opposite corners of the smallest box,
+// aligned with the axes, that contains all the atoms of this molecule.
+// The coordinates are in the order X, Y and Z.  Both corners are at
+// the origin for a molecule with no atoms.
+func (m *Molecule) BoundingBox() (min, max [3]float32) {
+	for i, a := range m.atoms {
+		c := [3]float32{a.X, a.Y, a.Z}
+		if i == 0 {
+			min, max = c, c
+			continue
+		}
+
+		for k := range c {
+			if c[k] < min[k] {
+				min[k] = c[k]
+			}
+			if c[k] > max[k] {
+				max[k] = c[k]
+			}
+		}
+	}
+
+	return min, max
+}
+
+// BondLength answers the length of the bond with the given ID: the
+// distance between its atoms, in the units of their coordinates.
+func (m *Molecule) BondLength(bondId uint16) (float64, error) {
+	b := m.bondWithId(bondId)
+	if b == nil {
+		return 0, fmt.Errorf("Unknown bond ID given : %d", bondId)
+	}
+
+	return norm(vectorBetween(m.atomWithIid(b.a1), m.atomWithIid(b.a2))), nil
+}
+
+// Angle answers the angle, in degrees, between the bonds `b`-`a` and
+// `b`-`c`, given the input IDs of the three atoms.  It lies between 0
+// and 180.
+//
+// Answers an error if `b` is not bonded to both the others, or if
+// either bond has zero length.
+func (m *Molecule) Angle(a, b, c uint16) (float64, error) {
+	ats, err := m.bondedChain(a, b, c)
+	if err != nil {
+		return 0, err
+	}
+
+	v1 := vectorBetween(ats[1], ats[0])
+	v2 := vectorBetween(ats[1], ats[2])
+	n1, n2 := norm(v1), norm(v2)
+	if n1 == 0 || n2 == 0 {
+		return 0, fmt.Errorf("Atoms %d, %d and %d do not define an angle.", a, b, c)
+	}
+
+	cos := math.Max(-1, math.Min(1, dot(v1, v2)/(n1*n2)))
+	return math.Acos(cos) * 180 / math.Pi, nil
+}
+
+// Dihedral answers the dihedral angle, in degrees, about the bond
+// `b`-`c`, between the planes `a`-`b`-`c` and `b`-`c`-`d`, given the
+// input IDs of the four atoms.  It lies in the range (-180, 180], and
+// is positive when, looking along `b`-`c`, the bond to `a` must be
+// turned clockwise to eclipse that to `d`.
+//
+// Answers an error unless the atoms form a chain `a`-`b`-`c`-`d` of
+// bonds, or if three consecutive atoms are collinear.
+func (m *Molecule) Dihedral(a, b, c, d uint16) (float64, error) {
+	ats, err := m.bondedChain(a, b, c, d)
+	if err != nil {
+		return 0, err
+	}
+
+	b1 := vectorBetween(ats[0], ats[1])
+	b2 := vectorBetween(ats[1], ats[2])
+	b3 := vectorBetween(ats[2], ats[3])
+	n1, n2 := cross(b1, b2), cross(b2, b3)
+	if norm(n1) == 0 || norm(n2) == 0 {
+		return 0, fmt.Errorf("Atoms %d, %d, %d and %d do not define a dihedral angle.", a, b, c, d)
+	}
+
+	x := dot(n1, n2)
+	y := dot(cross(n1, n2), b2) / norm(b2)
+	return math.Atan2(y, x) * 180 / math.Pi, nil
+}
+
+// bondedChain answers the atoms with the given input IDs, in order,
+// when each is bonded to the next.  Answers an error otherwise.
+func (m *Molecule) bondedChain(iIds ...uint16) ([]*_Atom, error) {
+	ats := make([]*_Atom, len(iIds))
+	for i, id := range iIds {
+		ats[i] = m.atomWithIid(id)
+		if ats[i] == nil {
+			return nil, fmt.Errorf("Unknown atom input ID given : %d", id)
+		}
+		if i > 0 && m.bondBetween(iIds[i-1], id) == nil {
+			return nil, fmt.Errorf("Atoms %d and %d are not bonded.", iIds[i-1], id)
+		}
+	}
+
+	return ats, nil
+}
+
+// vectorBetween answers the vector from the first given atom to the
+// second.
+func vectorBetween(a1, a2 *_Atom) [3]float64 {
+	return [3]float64{float64(a2.X - a1.X), float64(a2.Y - a1.Y), float64(a2.Z - a1.Z)}
+}
+
+// dot answers the dot product of the given vectors.
+func dot(v1, v2 [3]float64) float64 {
+	return v1[0]*v2[0] + v1[1]*v2[1] + v1[2]*v2[2]
+}
+
+// cross answers the cross product of the given vectors.
+func cross(v1, v2 [3]float64) [3]float64 {
+	return [3]float64{
+		v1[1]*v2[2] - v1[2]*v2[1],
+		v1[2]*v2[0] - v1[0]*v2[2],
+		v1[0]*v2[1] - v1[1]*v2[0],
+	}
+}
+
+// norm answers the length of the given vector.
+func norm(v [3]float64) float64 {
+	return math.Sqrt(dot(v, v))
+}
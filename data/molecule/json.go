@@ -0,0 +1,152 @@
+package molecule
+
+import (
+	"encoding/json"
+	"fmt"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// _JSONMolecule is the JSON representation of a molecule.  See
+// `doc/design/molecule-json.md` for the format.
+type _JSONMolecule struct {
+	Vendor           string        `json:"vendor,omitempty"`
+	VendorMoleculeId string        `json:"vendorMoleculeId,omitempty"`
+	Atoms            []_JSONAtom   `json:"atoms"`
+	Bonds            []_JSONBond   `json:"bonds"`
+	Attributes       []_JSONAttrib `json:"attributes,omitempty"`
+}
+
+// _JSONAtom is the JSON representation of an atom.
+type _JSONAtom struct {
+	Id      uint16      `json:"id"`
+	Symbol  string      `json:"symbol"`
+	X       float32     `json:"x"`
+	Y       float32     `json:"y"`
+	Z       float32     `json:"z"`
+	Charge  int8        `json:"charge"`
+	HCount  uint8       `json:"hCount"`
+	Isotope uint16      `json:"isotope,omitempty"`
+	Radical cmn.Radical `json:"radical,omitempty"`
+	MapNum  uint16      `json:"mapNum,omitempty"`
+}
+
+// _JSONBond is the JSON representation of a bond.
+type _JSONBond struct {
+	Id     uint16    `json:"id"`
+	Atoms  [2]uint16 `json:"atoms"`
+	Order  uint8     `json:"order"`
+	Stereo uint8     `json:"stereo"`
+}
+
+// _JSONAttrib is the JSON representation of an attribute.
+type _JSONAttrib struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// MarshalJSON answers the JSON representation of this molecule.
+//
+// Only the input structure - atoms, bonds and attributes - is
+// written.  Derived information such as rings and aromaticity is
+// recomputed when the molecule is read back.  See
+// `doc/design/molecule-json.md` for the format.
+func (m *Molecule) MarshalJSON() ([]byte, error) {
+	jm := _JSONMolecule{
+		Vendor:           m.vendor,
+		VendorMoleculeId: m.vendorMoleculeId,
+		Atoms:            make([]_JSONAtom, 0, len(m.atoms)),
+		Bonds:            make([]_JSONBond, 0, len(m.bonds)),
+	}
+
+	for _, a := range m.atoms {
+		jm.Atoms = append(jm.Atoms, _JSONAtom{a.iId, a.symbol, a.X, a.Y, a.Z, a.charge, a.hCount, a.isotope, a.radical, a.mapNum})
+	}
+	for _, b := range m.bonds {
+		jm.Bonds = append(jm.Bonds, _JSONBond{b.id, [2]uint16{b.a1, b.a2}, uint8(b.bType), uint8(b.bStereo)})
+	}
+	for _, attr := range m.attributes {
+		jm.Attributes = append(jm.Attributes, _JSONAttrib{attr.Name, attr.Value})
+	}
+
+	return json.Marshal(jm)
+}
+
+// UnmarshalMolecule constructs a new molecule from the given JSON
+// representation, and normalises it.  See
+// `doc/design/molecule-json.md` for the format.
+func UnmarshalMolecule(data []byte) (*Molecule, error) {
+	var jm _JSONMolecule
+	if err := json.Unmarshal(data, &jm); err != nil {
+		return nil, err
+	}
+
+	m := New()
+	if err := m.loadJSON(&jm); err != nil {
+		m.discard()
+		return nil, err
+	}
+	if err := m.Normalise(); err != nil {
+		m.discard()
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// loadJSON populates this molecule from the given JSON
+// representation.
+func (m *Molecule) loadJSON(jm *_JSONMolecule) error {
+	m.vendor = jm.Vendor
+	m.vendorMoleculeId = jm.VendorMoleculeId
+
+	for _, ja := range jm.Atoms {
+		if ja.Id == 0 {
+			return fmt.Errorf("Atom input IDs should be positive.")
+		}
+		el, ok := cmn.PeriodicTable[ja.Symbol]
+		if !ok {
+			return fmt.Errorf("Unknown element symbol : %s", ja.Symbol)
+		}
+
+		if ja.Radical > cmn.RadicalTriplet {
+			return fmt.Errorf("Atom %d : unknown radical configuration : %d", ja.Id, ja.Radical)
+		}
+
+		a := newAtom(m, el.Number, int(ja.Id))
+		a.X, a.Y, a.Z = ja.X, ja.Y, ja.Z
+		a.charge = ja.Charge
+		a.hCount = ja.HCount
+		a.isotope = ja.Isotope
+		a.radical = ja.Radical
+		a.mapNum = ja.MapNum
+		if err := m.addAtom(a); err != nil {
+			return err
+		}
+	}
+
+	for _, jb := range jm.Bonds {
+		if jb.Id == 0 {
+			return fmt.Errorf("Bond IDs should be positive.")
+		}
+		bType := cmn.BondType(jb.Order)
+		if bType < cmn.BondTypeSingle || bType > cmn.BondTypeDative {
+			return fmt.Errorf("Unhandled bond type : %v", bType)
+		}
+
+		b := newBond(m, int(jb.Id))
+		b.a1, b.a2 = jb.Atoms[0], jb.Atoms[1]
+		b.bType = bType
+		b.isAro = bType == cmn.BondTypeAltern
+		b.bStereo = cmn.BondStereo(jb.Stereo)
+		if err := m.addBond(b); err != nil {
+			return err
+		}
+	}
+
+	for _, ja := range jm.Attributes {
+		m.SetAttribute(ja.Name, ja.Value)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package molecule
+
+import (
+	"testing"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+func TestJSONRoundTripLabels(t *testing.T) {
+	doc := `{
+  "atoms": [
+    {"id": 1, "symbol": "C", "x": 0, "y": 0, "z": 0, "charge": 0, "hCount": 3, "isotope": 13, "mapNum": 1},
+    {"id": 2, "symbol": "C", "x": 1.3, "y": 0.75, "z": 0, "charge": 0, "hCount": 2, "radical": 2, "mapNum": 2}
+  ],
+  "bonds": [
+    {"id": 1, "atoms": [1, 2], "order": 1, "stereo": 0}
+  ]
+}`
+	m, err := UnmarshalMolecule([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.discard()
+
+	data, err := m.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m2, err := UnmarshalMolecule(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m2.discard()
+
+	a1, a2 := m2.atomWithIid(1), m2.atomWithIid(2)
+	if a1.isotope != 13 || a1.mapNum != 1 || a1.radical != cmn.RadicalNone {
+		t.Errorf("atom 1: isotope %d, map %d, radical %v", a1.isotope, a1.mapNum, a1.radical)
+	}
+	if a2.isotope != 0 || a2.mapNum != 2 || a2.radical != cmn.RadicalDoublet {
+		t.Errorf("atom 2: isotope %d, map %d, radical %v", a2.isotope, a2.mapNum, a2.radical)
+	}
+	if !m.Equals(m2) {
+		t.Errorf("canonical key %s changed to %s", m.CanonicalKey(), m2.CanonicalKey())
+	}
+}
+
+func TestJSONRejectsUnknownRadical(t *testing.T) {
+	doc := `{"atoms": [{"id": 1, "symbol": "C", "hCount": 4, "radical": 9}], "bonds": []}`
+	if m, err := UnmarshalMolecule([]byte(doc)); err == nil {
+		m.discard()
+		t.Error("radical 9 accepted")
+	}
+}
+
+func TestJSONAromaticBonds(t *testing.T) {
+	doc := `{
+  "atoms": [
+    {"id": 1, "symbol": "N", "hCount": 0},
+    {"id": 2, "symbol": "C", "hCount": 1},
+    {"id": 3, "symbol": "C", "hCount": 1},
+    {"id": 4, "symbol": "C", "hCount": 1},
+    {"id": 5, "symbol": "C", "hCount": 1},
+    {"id": 6, "symbol": "C", "hCount": 1}
+  ],
+  "bonds": [
+    {"id": 1, "atoms": [1, 2], "order": 4},
+    {"id": 2, "atoms": [2, 3], "order": 4},
+    {"id": 3, "atoms": [3, 4], "order": 4},
+    {"id": 4, "atoms": [4, 5], "order": 4},
+    {"id": 5, "atoms": [5, 6], "order": 4},
+    {"id": 6, "atoms": [6, 1], "order": 4}
+  ]
+}`
+	m, err := UnmarshalMolecule([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.discard()
+
+	if f := m.Formula(); f != "C5H5N" {
+		t.Errorf("formula %s; want C5H5N", f)
+	}
+	if n := m.aromaticRingCount(); n != 1 {
+		t.Errorf("%d aromatic rings; want 1", n)
+	}
+	for _, b := range m.bonds {
+		if b.bType == cmn.BondTypeAltern {
+			t.Errorf("bond %d has no order assigned", b.id)
+		}
+	}
+}
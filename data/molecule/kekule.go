@@ -0,0 +1,164 @@
+package molecule
+
+import (
+	"fmt"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// Kekulize assigns alternating single and double bonds to the aromatic
+// bonds of this molecule, so that each aromatic atom that needs a
+// double bond has exactly one.  The aromatic flags of the bonds are
+// left intact.
+//
+// Bonds given as aromatic in the input have no order, until this
+// method assigns them one.  Normalisation invokes it for that purpose,
+// before anything else.
+//
+// An aromatic atom needs a double bond when, counting its aromatic
+// bonds as single, it falls short of the valence permitted by its
+// charge.  Thus, the carbon atoms and the pyridine-type nitrogen
+// atoms of an aromatic ring need one, while pyrrole-type nitrogen,
+// furan-type oxygen and charged carbon atoms do not.  The double bonds
+// are then placed using a perfect matching of the needy atoms over
+// the aromatic bonds.
+//
+// Answers an error, leaving all bonds unchanged, if no such matching
+// exists.  Aromaticity must have been determined - or the aromatic
+// bonds given in the input - before this method is invoked.
+func (m *Molecule) Kekulize() error {
+	needy := make(map[uint16]bool)
+	for _, a := range m.atoms {
+		if a.hasAromaticBond() && a.needsAromaticDoubleBond() {
+			needy[a.iId] = true
+		}
+	}
+
+	mates := make(map[uint16]*_Bond, len(needy))
+	if !m.matchAromaticAtoms(needy, mates) {
+		return fmt.Errorf("Molecule %d has no valid Kekulé structure.", m.id)
+	}
+
+	for _, b := range m.bonds {
+		if !b.isAro {
+			continue
+		}
+		if mates[b.a1] == b {
+			m.setBondType(b, cmn.BondTypeDouble)
+		} else {
+			m.setBondType(b, cmn.BondTypeSingle)
+		}
+	}
+
+	return nil
+}
+
+// hasAromaticBond answers if at least one of the bonds of this atom is
+// aromatic.
+func (a *_Atom) hasAromaticBond() bool {
+	mol := a.mol
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		if mol.bondWithId(uint16(bid)).isAro {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasUnassignedBondOrders answers if any of the bonds of this molecule
+// was given as aromatic in the input, and is yet to be assigned an
+// order.
+func (m *Molecule) hasUnassignedBondOrders() bool {
+	for _, b := range m.bonds {
+		if b.bType == cmn.BondTypeAltern {
+			return true
+		}
+	}
+
+	return false
+}
+
+// needsAromaticDoubleBond answers if this aromatic atom needs a double
+// bond within its aromatic system.  See `Kekulize`.
+func (a *_Atom) needsAromaticDoubleBond() bool {
+	mol := a.mol
+
+	sum := int(a.hCount)
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		b := mol.bondWithId(uint16(bid))
+		if b.isAro {
+			sum++
+		} else {
+			sum += b.order()
+		}
+	}
+
+	return a.chargedValence()-sum >= 1
+}
+
+// matchAromaticAtoms pairs each of the given needy atoms with another
+// over an aromatic bond, recording the bond chosen for each atom in
+// the given map.  Answers `false` if a perfect matching does not
+// exist.
+//
+// The search is a backtracking one that always proceeds with the
+// unmatched atom having the fewest candidate partners.  It is
+// exponential in the worst case, but fast for the ring systems of
+// common molecules.
+func (m *Molecule) matchAromaticAtoms(needy map[uint16]bool, mates map[uint16]*_Bond) bool {
+	var next *_Atom
+	var nextCands []*_Bond
+	for aid := range needy {
+		if mates[aid] != nil {
+			continue
+		}
+
+		a := m.atomWithIid(aid)
+		cands := make([]*_Bond, 0, 3)
+		for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+			b := m.bondWithId(uint16(bid))
+			oaid := b.otherAtomIid(aid)
+			if b.isAro && needy[oaid] && mates[oaid] == nil {
+				cands = append(cands, b)
+			}
+		}
+		if next == nil || len(cands) < len(nextCands) ||
+			(len(cands) == len(nextCands) && aid < next.iId) {
+			next, nextCands = a, cands
+		}
+	}
+	if next == nil {
+		return true // All matched.
+	}
+
+	for _, b := range nextCands {
+		oaid := b.otherAtomIid(next.iId)
+		mates[next.iId] = b
+		mates[oaid] = b
+		if m.matchAromaticAtoms(needy, mates) {
+			return true
+		}
+		delete(mates, next.iId)
+		delete(mates, oaid)
+	}
+
+	return false
+}
+
+// setBondType changes the order of the given bond to the given type,
+// keeping its atoms consistent.
+func (m *Molecule) setBondType(b *_Bond, bType cmn.BondType) {
+	if b.bType == bType {
+		return
+	}
+
+	a1 := m.atomWithIid(b.a1)
+	a2 := m.atomWithIid(b.a2)
+
+	a1.removeBond(b)
+	a2.removeBond(b)
+	b.bType = bType
+	a1.addBond(b)
+	a2.addBond(b)
+}
@@ -0,0 +1,341 @@
+package molecule
+
+import (
+	"sort"
+	"time"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// MCSOptions configures the search for a maximum common substructure.
+// See `MCS`.
+type MCSOptions struct {
+	// Should mapped atoms be of the same element?
+	MatchElements bool
+	// Should mapped bonds be of the same order?  Aromatic bonds match
+	// only aromatic bonds.
+	MatchBondOrders bool
+	// Should ring bonds map only to ring bonds, and chain bonds only to
+	// chain bonds?
+	RingMatchesRingOnly bool
+	// Time after which the search stops, answering the best mapping
+	// found so far.  A non-positive value imposes no limit.
+	Timeout time.Duration
+}
+
+// AtomMapping relates an atom of one molecule to an atom of another.
+type AtomMapping struct {
+	A uint16 // Input ID of the atom in the first molecule.
+	B uint16 // Input ID of the atom in the second molecule.
+}
+
+// MCS answers a maximum common substructure of the two given
+// molecules, as a mapping between their atoms, ordered by the input
+// IDs of the atoms of the first molecule.
+//
+// The common substructure is connected, and has as many atoms as
+// possible.  Each pair of mapped atoms, and each bond between mapped
+// atoms present in both molecules, should be compatible as required by
+// the given options.  Mapped atoms bonded in only one of the molecules
+// are permitted, as long as the substructure remains connected through
+// common bonds.
+//
+// The search is exhaustive, but pruned by the size of the best mapping
+// found so far.  When a timeout is given, the best mapping found
+// before it expires is answered; it may not be maximal.
+//
+// Stale rings and aromaticity of the molecules are determined afresh.
+func MCS(a, b *Molecule, opts MCSOptions) ([]AtomMapping, error) {
+	if err := a.refresh(stageAromaticity); err != nil {
+		return nil, err
+	}
+	if err := b.refresh(stageAromaticity); err != nil {
+		return nil, err
+	}
+
+	s := newMCSSearch(a, b, opts)
+	s.run()
+
+	maps := make([]AtomMapping, 0, len(s.best))
+	for i, j := range s.best {
+		if j >= 0 {
+			maps = append(maps, AtomMapping{a.atoms[i].iId, b.atoms[j].iId})
+		}
+	}
+	sort.Sort(_AtomMappings(maps))
+
+	return maps, nil
+}
+
+// _AtomMappings orders atom mappings by the input IDs of the atoms of
+// the first molecule.
+type _AtomMappings []AtomMapping
+
+func (ams _AtomMappings) Len() int {
+	return len(ams)
+}
+
+func (ams _AtomMappings) Swap(i, j int) {
+	ams[i], ams[j] = ams[j], ams[i]
+}
+
+func (ams _AtomMappings) Less(i, j int) bool {
+	return ams[i].A < ams[j].A
+}
+
+// _MCSSearch holds the state of a search for a maximum common
+// substructure.  Atoms are referred to by their indices in the lists
+// of atoms of their molecules.
+type _MCSSearch struct {
+	a, b *Molecule
+	opts MCSOptions
+
+	adjA, adjB [][]int           // Neighbour indices of each atom.
+	bondsA     map[[2]int]*_Bond // Bonds of `a`, keyed by atom indices.
+	bondsB     map[[2]int]*_Bond // Bonds of `b`, keyed by atom indices.
+
+	mapping  []int  // Index in `b` mapped to each atom of `a`; `-1` if none.
+	usedB    []bool // Is each atom of `b` mapped?
+	excluded []bool // Is each atom of `a` excluded from the mapping?
+	size     int    // Number of atoms currently mapped.
+
+	best     []int // Best mapping found so far.
+	bestSize int   // Number of atoms in the best mapping.
+
+	deadline time.Time // Time at which the search stops; zero if none.
+	steps    int       // Number of steps taken, to pace the deadline checks.
+	expired  bool      // Has the deadline passed?
+}
+
+// newMCSSearch prepares a search for a maximum common substructure of
+// the two given molecules.
+func newMCSSearch(a, b *Molecule, opts MCSOptions) *_MCSSearch {
+	s := &_MCSSearch{a: a, b: b, opts: opts}
+	s.adjA, s.bondsA = mcsAdjacency(a)
+	s.adjB, s.bondsB = mcsAdjacency(b)
+
+	s.mapping = make([]int, len(a.atoms))
+	for i := range s.mapping {
+		s.mapping[i] = -1
+	}
+	s.usedB = make([]bool, len(b.atoms))
+	s.excluded = make([]bool, len(a.atoms))
+	s.best = append([]int(nil), s.mapping...)
+
+	if opts.Timeout > 0 {
+		s.deadline = time.Now().Add(opts.Timeout)
+	}
+	return s
+}
+
+// mcsAdjacency answers the neighbour indices of each atom of the given
+// molecule, and its bonds keyed by the indices of their atoms, the
+// smaller first.
+func mcsAdjacency(m *Molecule) ([][]int, map[[2]int]*_Bond) {
+	idxs := make(map[uint16]int, len(m.atoms))
+	for i, a := range m.atoms {
+		idxs[a.iId] = i
+	}
+
+	adj := make([][]int, len(m.atoms))
+	bonds := make(map[[2]int]*_Bond, len(m.bonds))
+	for _, b := range m.bonds {
+		i, j := idxs[b.a1], idxs[b.a2]
+		adj[i] = append(adj[i], j)
+		adj[j] = append(adj[j], i)
+		if i > j {
+			i, j = j, i
+		}
+		bonds[[2]int{i, j}] = b
+	}
+
+	return adj, bonds
+}
+
+// bondBetween answers the bond between the atoms at the given indices,
+// from the given index of bonds.  Answers `nil` if they are not
+// bonded.
+func bondBetween(bonds map[[2]int]*_Bond, i, j int) *_Bond {
+	if i > j {
+		i, j = j, i
+	}
+	return bonds[[2]int{i, j}]
+}
+
+// run performs the search, seeding it with each compatible pair of
+// atoms in turn.  Once all seeds involving an atom of `a` are
+// exhausted, that atom is excluded from further seeds, since every
+// mapping including it has already been considered.
+func (s *_MCSSearch) run() {
+	for i := range s.a.atoms {
+		for j := range s.b.atoms {
+			if s.expired || s.bestSize == len(s.a.atoms)-s.excludedCount() {
+				return
+			}
+			if !s.atomsMatch(i, j) {
+				continue
+			}
+
+			s.assign(i, j)
+			s.grow()
+			s.unassign(i, j)
+		}
+		s.excluded[i] = true
+	}
+}
+
+// excludedCount answers the number of atoms of `a` excluded from the
+// mapping.
+func (s *_MCSSearch) excludedCount() int {
+	n := 0
+	for _, ex := range s.excluded {
+		if ex {
+			n++
+		}
+	}
+	return n
+}
+
+// grow extends the current mapping in all possible ways, recording the
+// largest mapping found.
+func (s *_MCSSearch) grow() {
+	if s.size > s.bestSize {
+		copy(s.best, s.mapping)
+		s.bestSize = s.size
+	}
+	if s.checkDeadline() {
+		return
+	}
+
+	// Prune when even mapping every remaining atom cannot improve on
+	// the best mapping.
+	free := 0
+	for i, j := range s.mapping {
+		if j < 0 && !s.excluded[i] {
+			free++
+		}
+	}
+	if s.size+free <= s.bestSize || s.size >= len(s.b.atoms) {
+		return
+	}
+
+	// The next atom to map is the first free atom bonded to a mapped
+	// one.  It can be mapped to any free atom bonded to the image of one
+	// of its mapped neighbours.
+	next := -1
+	cands := make([]int, 0, cmn.ListSizeTiny)
+	seen := make(map[int]bool)
+	for i, j := range s.mapping {
+		if j >= 0 || s.excluded[i] {
+			continue
+		}
+		for _, k := range s.adjA[i] {
+			if s.mapping[k] < 0 {
+				continue
+			}
+			next = i
+			for _, l := range s.adjB[s.mapping[k]] {
+				if !s.usedB[l] && !seen[l] {
+					cands = append(cands, l)
+					seen[l] = true
+				}
+			}
+		}
+		if next >= 0 {
+			break
+		}
+	}
+	if next < 0 {
+		return
+	}
+
+	for _, j := range cands {
+		if !s.atomsMatch(next, j) || !s.consistent(next, j) {
+			continue
+		}
+
+		s.assign(next, j)
+		s.grow()
+		s.unassign(next, j)
+		if s.expired {
+			return
+		}
+	}
+
+	// Alternatively, leave the atom out of the mapping.
+	s.excluded[next] = true
+	s.grow()
+	s.excluded[next] = false
+}
+
+// assign maps the atom of `a` at index `i` to that of `b` at `j`.
+func (s *_MCSSearch) assign(i, j int) {
+	s.mapping[i] = j
+	s.usedB[j] = true
+	s.size++
+}
+
+// unassign undoes `assign`.
+func (s *_MCSSearch) unassign(i, j int) {
+	s.mapping[i] = -1
+	s.usedB[j] = false
+	s.size--
+}
+
+// consistent answers if mapping the atom of `a` at index `i` to that of
+// `b` at `j` keeps every bond present in both molecules, between
+// mapped atoms, compatible.
+func (s *_MCSSearch) consistent(i, j int) bool {
+	for _, k := range s.adjA[i] {
+		l := s.mapping[k]
+		if l < 0 {
+			continue
+		}
+		if bb := bondBetween(s.bondsB, j, l); bb != nil && !s.bondsMatch(bondBetween(s.bondsA, i, k), bb) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// atomsMatch answers if the atom of `a` at index `i` can be mapped to
+// that of `b` at `j`, under the options of this search.
+func (s *_MCSSearch) atomsMatch(i, j int) bool {
+	if s.opts.MatchElements && s.a.atoms[i].atNum != s.b.atoms[j].atNum {
+		return false
+	}
+	return true
+}
+
+// bondsMatch answers if the two given bonds can be mapped to each
+// other, under the options of this search.
+func (s *_MCSSearch) bondsMatch(b1, b2 *_Bond) bool {
+	if s.opts.RingMatchesRingOnly && b1.isCyclic() != b2.isCyclic() {
+		return false
+	}
+	if s.opts.MatchBondOrders {
+		if b1.isAro || b2.isAro {
+			return b1.isAro == b2.isAro
+		}
+		return b1.bType == b2.bType
+	}
+	return true
+}
+
+// checkDeadline answers if the deadline of this search has passed.
+// The clock is consulted only periodically.
+func (s *_MCSSearch) checkDeadline() bool {
+	if s.expired {
+		return true
+	}
+	if s.deadline.IsZero() {
+		return false
+	}
+
+	s.steps++
+	if s.steps%256 == 0 && time.Now().After(s.deadline) {
+		s.expired = true
+	}
+	return s.expired
+}
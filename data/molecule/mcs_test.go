@@ -0,0 +1,60 @@
+package molecule
+
+import "testing"
+
+// buildMonosubstitutedBenzene builds benzene with the given atom on
+// its first carbon atom.  Atoms are numbered as in `C1=CC=CC=C1X`.
+func buildMonosubstitutedBenzene(t *testing.T, sym string) *Molecule {
+	return buildMolecule(t, []string{"C", "C", "C", "C", "C", "C", sym}, testBonds([][3]int{
+		{1, 2, 2}, {2, 3, 1}, {3, 4, 2}, {4, 5, 1}, {5, 6, 2}, {6, 1, 1}, {1, 7, 1},
+	}))
+}
+
+// TestMCSSingleAtomDifference checks toluene against phenol, which
+// differ only in the atom on the ring.
+func TestMCSSingleAtomDifference(t *testing.T) {
+	toluene := buildMonosubstitutedBenzene(t, "C")
+	defer toluene.discard()
+	phenol := buildMonosubstitutedBenzene(t, "O")
+	defer phenol.discard()
+
+	cases := []struct {
+		name string
+		opts MCSOptions
+		size int
+	}{
+		// The ring alone is common.
+		{"matching elements", MCSOptions{MatchElements: true, MatchBondOrders: true}, 6},
+		// The methyl carbon maps to the hydroxyl oxygen.
+		{"ignoring elements", MCSOptions{MatchBondOrders: true}, 7},
+	}
+	for _, c := range cases {
+		ms, err := MCS(toluene, phenol, c.opts)
+		if err != nil {
+			t.Fatalf("%s : %v", c.name, err)
+		}
+		if len(ms) != c.size {
+			t.Errorf("%s : %d atoms mapped; want %d", c.name, len(ms), c.size)
+			continue
+		}
+
+		// Every ring atom maps to a ring atom, bonds are preserved,
+		// and the mapping is ordered by the atoms of toluene.
+		for i, am := range ms {
+			if i > 0 && am.A <= ms[i-1].A {
+				t.Errorf("%s : mapping %v not ordered by the first molecule", c.name, ms)
+			}
+			if am.A <= 6 && am.B > 6 || am.A > 6 && am.B <= 6 {
+				t.Errorf("%s : atom %d of toluene maps to atom %d of phenol", c.name, am.A, am.B)
+			}
+		}
+		for _, x := range ms {
+			for _, y := range ms {
+				bt, bp := toluene.atomWithIid(x.A).bondTo(y.A), phenol.atomWithIid(x.B).bondTo(y.B)
+				if (bt == nil) != (bp == nil) {
+					t.Errorf("%s : atoms %d and %d are bonded in only one of toluene and phenol", c.name, x.A, y.A)
+				}
+			}
+		}
+	}
+}
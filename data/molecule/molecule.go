@@ -1,6 +1,8 @@
 package molecule
 
 import (
+	"fmt"
+	"math"
 	"sync"
 
 	cmn "github.com/RxnWeaver/RxnWeaver/common"
@@ -10,28 +12,55 @@ import (
 // globally-unique ID to each molecule.
 type nextMolIdHolder struct {
 	mu     sync.Mutex
-	nextId uint32
+	nextId uint64
 }
 
 // The only instance of `nextMolIdHolder`.
 var nextMolId nextMolIdHolder
 
-func nextMoleculeId() uint32 {
+// nextMoleculeId answers the next available molecule ID.  IDs start
+// at `1`.
+//
+// The IDs do not wrap around: should they ever be exhausted, this
+// function panics rather than answer an ID already in use.
+func nextMoleculeId() uint64 {
 	nextMolId.mu.Lock()
 	defer nextMolId.mu.Unlock()
 
+	if nextMolId.nextId == math.MaxUint64 {
+		panic("Molecule IDs exhausted!")
+	}
 	nextMolId.nextId++
 	return nextMolId.nextId
 }
 
+// ResetMoleculeIds restarts the assignment of molecule IDs from `1`.
+// It is meant for tests, that need deterministic IDs.
+//
+// Since IDs are expected to be unique, this function should only be
+// called when no molecules are alive.  See `AllMolecules.Clear`.
+func ResetMoleculeIds() {
+	nextMolId.mu.Lock()
+	defer nextMolId.mu.Unlock()
+
+	nextMolId.nextId = 0
+}
+
 // molecules holds all the molecules that are currently alive.
+//
+// Molecules register and unregister themselves from their own event
+// loops; hence, access is synchronised.
 type molecules struct {
-	allMolecules map[uint32]*Molecule
+	mu           sync.RWMutex
+	allMolecules map[uint64]*Molecule
 }
 
 // MoleculeWithId answers the molecule instance with the given ID, if
 // one such exists.
-func (ms *molecules) MoleculeWithId(id uint32) *Molecule {
+func (ms *molecules) MoleculeWithId(id uint64) *Molecule {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
 	if mol, ok := ms.allMolecules[id]; ok {
 		return mol
 	}
@@ -42,11 +71,35 @@ func (ms *molecules) MoleculeWithId(id uint32) *Molecule {
 // Clear sends a termination request to all the alive molecules, and
 // stops tracking them.
 func (ms *molecules) Clear() {
+	ms.mu.Lock()
+	mols := make([]*Molecule, 0, len(ms.allMolecules))
 	for id, mol := range ms.allMolecules {
-		msg := InMessage{ReqExit, 0, nil, nil}
-		mol.InChannel() <- msg
+		mols = append(mols, mol)
 		delete(ms.allMolecules, id)
 	}
+	ms.mu.Unlock()
+
+	// The lock is released first, since exiting molecules unregister
+	// themselves.
+	for _, mol := range mols {
+		mol.InChannel() <- InMessage{ReqExit, 0, nil, nil}
+	}
+}
+
+// register starts tracking the given molecule.
+func (ms *molecules) register(mol *Molecule) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.allMolecules[mol.id] = mol
+}
+
+// unregister stops tracking the given molecule.
+func (ms *molecules) unregister(mol *Molecule) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	delete(ms.allMolecules, mol.id)
 }
 
 // The only instance of `molecules`.
@@ -54,7 +107,7 @@ var AllMolecules molecules
 
 // Initialise the global molecule cache.
 func init() {
-	AllMolecules.allMolecules = make(map[uint32]*Molecule)
+	AllMolecules.allMolecules = make(map[uint64]*Molecule)
 }
 
 // Molecule represents a chemical molecule.
@@ -62,17 +115,22 @@ func init() {
 // It holds information concerning its atom, bonds, rings, etc.  Note
 // that a molecule is expected to be a single connected component.
 type Molecule struct {
-	id uint32 // The globally-unique ID of this molecule.
+	id uint64 // The globally-unique ID of this molecule.
 
 	// Channel on which this molecule receives requests and
 	// notifications.
 	inChannel chan InMessage
+	// Closed when the event loop of this molecule terminates.
+	done chan struct{}
 
 	atoms       []*_Atom       // List of atoms in this molecule.
 	bonds       []*_Bond       // List of bonds in this molecule.
 	rings       []*_Ring       // List of rings in this molecule.
 	ringSystems []*_RingSystem // List of ring systems in this molecule.
 
+	atomsByIid map[uint16]*_Atom // Index of atoms on their input IDs.
+	bondsById  map[uint16]*_Bond // Index of bonds on their IDs.
+
 	nextAtomIid      uint16 // Running number for atom input IDs.
 	nextBondId       uint16 // Running number for bond IDs.
 	nextRingId       uint8  // Running number for ring IDs.
@@ -85,6 +143,13 @@ type Molecule struct {
 
 	dists [][]int // Matrix of pair-wise distances between atoms.
 	paths [][]int // Lists of pair-wise paths between atoms.
+
+	aroModel cmn.AromaticityModel // Rules for determining aromaticity.
+
+	isNormalised bool   // Has this molecule been normalised?
+	frozen       bool   // Does this molecule reject edits?  See `Freeze`.
+	canonicalKey string // Input-order-independent key of the structure.
+	dirty        _Stage // Stages of derived information that are stale.
 }
 
 // New creates and initialises a molecule.
@@ -93,14 +158,21 @@ func New() *Molecule {
 	mol.id = nextMoleculeId()
 
 	mol.inChannel = make(chan InMessage, ReqChanSize)
+	mol.done = make(chan struct{})
 
 	mol.atoms = make([]*_Atom, 0, cmn.ListSizeLarge)
 	mol.bonds = make([]*_Bond, 0, cmn.ListSizeLarge)
+	mol.atomsByIid = make(map[uint16]*_Atom, cmn.ListSizeLarge)
+	mol.bondsById = make(map[uint16]*_Bond, cmn.ListSizeLarge)
 	mol.rings = make([]*_Ring, 0, cmn.ListSizeSmall)
 	mol.ringSystems = make([]*_RingSystem, 0, cmn.ListSizeSmall)
 
 	mol.attributes = make([]Attribute, 0, cmn.ListSizeTiny)
 
+	mol.nextAtomIid = 1
+	mol.nextBondId = 1
+	mol.dirty = stageAll
+
 	// Start the molecule's event loop.
 	go mol.run()
 
@@ -109,19 +181,33 @@ func New() *Molecule {
 
 // NewAtomBuilder answers a new atom builder.
 func (m *Molecule) NewAtomBuilder() *AtomBuilder {
-	return &AtomBuilder{m, nil}
+	return &AtomBuilder{mol: m}
 }
 
 // NewBondBuilder answers a new bond builder.
 func (m *Molecule) NewBondBuilder() *BondBuilder {
-	return &BondBuilder{m, nil}
+	return &BondBuilder{mol: m}
 }
 
 // Id answers the globally-unique ID of this molecule.
-func (m *Molecule) Id() uint32 {
+func (m *Molecule) Id() uint64 {
 	return m.id
 }
 
+// discard requests this molecule to terminate its event loop, so
+// that it is no longer tracked.  It is used to abandon a molecule
+// whose construction failed.
+func (m *Molecule) discard() {
+	m.inChannel <- InMessage{ReqExit, 0, nil, nil}
+}
+
+// discardAll discards each of the given molecules.  See `discard`.
+func discardAll(mols []*Molecule) {
+	for _, mol := range mols {
+		mol.discard()
+	}
+}
+
 // InChannel answers the input channel of this molecule.
 func (m *Molecule) InChannel() chan InMessage {
 	return m.inChannel
@@ -133,52 +219,587 @@ func (m *Molecule) InChannel() chan InMessage {
 // external agents.  For each request, an appropriate processing is
 // then performed, and the result returned on the channel that is part
 // of that request.
+//
+// This is the only goroutine that processes requests for this
+// molecule; requests received on other channels are forwarded to its
+// input channel.  See `Serve`.
 func (m *Molecule) run() {
 	// Register this molecule in the cache.
-	AllMolecules.allMolecules[m.id] = m
+	AllMolecules.register(m)
 
 	// Unregister this molecule from the cache when done.
-	defer delete(AllMolecules.allMolecules, m.id)
+	defer AllMolecules.unregister(m)
+	defer close(m.done)
 
-	alive := true
+	for msg := range m.inChannel {
+		if msg.Request == ReqExit {
+			return
+		}
+		m.processInMessage(msg)
+	}
+}
 
-liveloop:
-	for {
-		if !alive {
-			break liveloop
+// Serve starts a goroutine that forwards the requests received on the
+// given channel to this molecule's own input channel.  The requests
+// of all channels are, thus, processed one at a time, by the event
+// loop of this molecule.
+//
+// The goroutine terminates when it receives a `ReqExit` request, or
+// when the channel is closed.  Such a request ends only the
+// forwarding; the molecule continues to serve its other channels.
+//
+// Should the molecule itself terminate, each further request is
+// answered with `StNotFound`, until the channel is closed.
+func (m *Molecule) Serve(in <-chan InMessage) {
+	go m.forward(in)
+}
+
+// forward sends the requests received on the given channel to the
+// input channel of this molecule, until it receives a `ReqExit`
+// request, or the channel is closed.
+func (m *Molecule) forward(in <-chan InMessage) {
+	for msg := range in {
+		if msg.Request == ReqExit {
+			return
 		}
 
+		// A terminated molecule may still have room in its input
+		// channel; it is checked for first.
 		select {
-		case msg := <-m.inChannel:
-
-			switch msg.Request {
-			case ReqExit:
-				alive = false
+		case <-m.done:
+			m.answerGone(msg)
+			continue
+		default:
+		}
 
-			default:
-				m.processInMessage(msg)
-			}
+		select {
+		case m.inChannel <- msg:
+		case <-m.done:
+			m.answerGone(msg)
 		}
 	}
 }
 
+// answerGone answers the given request with `StNotFound`, since this
+// molecule has terminated.
+func (m *Molecule) answerGone(msg InMessage) {
+	if msg.OutChannel != nil {
+		msg.OutChannel <- OutMessage{Status: StNotFound, Cookie: msg.Cookie}
+	}
+}
+
 // processInMessage is the workhorse function of this molecule.
+//
+// It dispatches the given request to the appropriate handler, and
+// sends the outcome on the out-channel of the request, if one is
+// given.  The cookie of the request is returned unchanged.
 func (m *Molecule) processInMessage(msg InMessage) {
-	// TODO(js): Implement.
+	var out OutMessage
+
+	switch msg.Request {
+	case ReqAddAtom:
+		out = m.processAddAtom(msg.Payload)
+
+	case ReqAddBond:
+		out = m.processAddBond(msg.Payload)
+
+	case ReqSetAtomAttribute, ReqAddTag:
+		out = m.processSetAttribute(msg.Payload)
+
+	default:
+		out = OutMessage{Status: StIncorrectParameter}
+	}
+
+	if msg.OutChannel != nil {
+		out.Cookie = msg.Cookie
+		msg.OutChannel <- out
+	}
 }
 
-// atomWithIid answers the atom for the given input ID, if found.
-// Answers `nil` otherwise.
-func (m *Molecule) atomWithIid(id uint16) *_Atom {
+// processSetAttribute annotates this molecule with the given
+// attribute.  A tag may also be given as a plain string, in which
+// case it is recorded with an empty value.
+func (m *Molecule) processSetAttribute(payload interface{}) OutMessage {
+	switch attr := payload.(type) {
+	case Attribute:
+		m.SetAttribute(attr.Name, attr.Value)
+	case *Attribute:
+		if attr == nil {
+			return OutMessage{Status: StIncorrectParameter}
+		}
+		m.SetAttribute(attr.Name, attr.Value)
+	case string:
+		m.SetAttribute(attr, "")
+	default:
+		return OutMessage{Status: StIncorrectParameter}
+	}
+
+	return OutMessage{Status: StSuccess}
+}
+
+// processAddAtom adds the atom built by the given atom builder to
+// this molecule.  On success, the payload of the answered message is
+// the input ID of the atom.
+//
+// The status is `StAlreadyExists` if an atom with the same input ID
+// exists, and `StIncorrectParameter` if this molecule is frozen or the
+// payload is not a builder of its atoms.  On failure, the payload is
+// the error, if any.
+func (m *Molecule) processAddAtom(payload interface{}) OutMessage {
+	ab, ok := payload.(*AtomBuilder)
+	if !ok || ab == nil || ab.a == nil || ab.mol != m {
+		return OutMessage{Status: StIncorrectParameter}
+	}
+
+	if err := m.checkMutable(); err != nil {
+		return OutMessage{Status: StIncorrectParameter, Payload: err}
+	}
+	if m.atomWithIid(ab.a.iId) != nil {
+		return OutMessage{Status: StAlreadyExists}
+	}
+	if err := m.addAtom(ab.a); err != nil {
+		return OutMessage{Status: StIncorrectParameter, Payload: err}
+	}
+	return OutMessage{Status: StSuccess, Payload: ab.a.iId}
+}
+
+// processAddBond adds the bond built by the given bond builder to
+// this molecule.  On success, the payload of the answered message is
+// the ID of the bond.
+//
+// The status is `StAlreadyExists` if a bond with the same ID exists,
+// `StNotFound` if either of its atoms does not, and
+// `StIncorrectParameter` if this molecule is frozen or the payload is
+// not a builder of its bonds.  On failure, the payload is the error,
+// if any.
+func (m *Molecule) processAddBond(payload interface{}) OutMessage {
+	bb, ok := payload.(*BondBuilder)
+	if !ok || bb == nil || bb.b == nil || bb.mol != m {
+		return OutMessage{Status: StIncorrectParameter}
+	}
+
+	b := bb.b
+	if err := m.checkMutable(); err != nil {
+		return OutMessage{Status: StIncorrectParameter, Payload: err}
+	}
+	if m.bondWithId(b.id) != nil {
+		return OutMessage{Status: StAlreadyExists}
+	}
+	if m.atomWithIid(b.a1) == nil || m.atomWithIid(b.a2) == nil {
+		return OutMessage{Status: StNotFound}
+	}
+	if err := m.addBond(b); err != nil {
+		return OutMessage{Status: StIncorrectParameter, Payload: err}
+	}
+	return OutMessage{Status: StSuccess, Payload: b.id}
+}
+
+// addAtom adds the given atom to this molecule, and indexes it on
+// its input ID.  The running number for atom input IDs is moved past
+// that of the atom.
+//
+// Answers an error if an atom with the same input ID already exists,
+// or if this molecule is frozen.
+func (m *Molecule) addAtom(a *_Atom) error {
+	if err := m.checkMutable(); err != nil {
+		return err
+	}
+	if _, ok := m.atomsByIid[a.iId]; ok {
+		return fmt.Errorf("Atom with input ID %d already exists.", a.iId)
+	}
+
+	m.atoms = append(m.atoms, a)
+	m.atomsByIid[a.iId] = a
+	if a.iId >= m.nextAtomIid {
+		m.nextAtomIid = a.iId + 1
+	}
+
+	m.markStale(stageAll)
+	return nil
+}
+
+// removeAtom removes the atom with the given input ID from this
+// molecule, if it has no bonds.  Answers `true` if the atom was
+// removed; `false` otherwise.
+func (m *Molecule) removeAtom(iId uint16) bool {
+	a := m.atomWithIid(iId)
+	if a == nil || a.bonds.Count() > 0 {
+		return false
+	}
+
+	for i, ma := range m.atoms {
+		if ma == a {
+			m.atoms = append(m.atoms[:i], m.atoms[i+1:]...)
+			break
+		}
+	}
+	delete(m.atomsByIid, iId)
+	return true
+}
+
+// addBond adds the given bond to this molecule, and indexes it on its
+// ID.  It also adds the bond to both of its atoms.  The running
+// number for bond IDs is moved past that of the bond.
+//
+// Answers an error if a bond with the same ID already exists, or if
+// either of the atoms of the bond is unknown, or if this molecule is
+// frozen.
+func (m *Molecule) addBond(b *_Bond) error {
+	if err := m.checkMutable(); err != nil {
+		return err
+	}
+	if _, ok := m.bondsById[b.id]; ok {
+		return fmt.Errorf("Bond with ID %d already exists.", b.id)
+	}
+
+	a1 := m.atomWithIid(b.a1)
+	a2 := m.atomWithIid(b.a2)
+	if a1 == nil || a2 == nil {
+		return fmt.Errorf("Bond %d refers to an unknown atom : %d, %d", b.id, b.a1, b.a2)
+	}
+
+	m.bonds = append(m.bonds, b)
+	m.bondsById[b.id] = b
+	if b.id >= m.nextBondId {
+		m.nextBondId = b.id + 1
+	}
+	a1.addBond(b)
+	a2.addBond(b)
+
+	m.markStale(stageAll)
+	return nil
+}
+
+// RemoveBond removes the bond with the given ID from this molecule,
+// and from both of its atoms.  The hydrogen counts of the atoms are
+// not adjusted.
+//
+// The rings in which the bond participates are dissolved, and the
+// ring systems are determined afresh from the remaining rings.  Any
+// cycle that survives - such as the periphery of naphthalene, when its
+// fusion bond is removed - is detected when the rings are next
+// perceived.  Other derived information - such as aromaticity,
+// functional groups and distances - becomes stale; this molecule is no
+// longer normalised.  See `markStale`.
+//
+// Answers an error if this molecule is frozen.  See `Freeze`.
+func (m *Molecule) RemoveBond(bondId uint16) error {
+	if err := m.checkMutable(); err != nil {
+		return err
+	}
+	b := m.bondWithId(bondId)
+	if b == nil {
+		return fmt.Errorf("Unknown bond ID given : %d", bondId)
+	}
+
+	rids := make([]uint8, len(b.rings))
+	copy(rids, b.rings)
+	for _, rid := range rids {
+		m.dissolveRing(m.ringWithId(rid))
+	}
+
+	m.atomWithIid(b.a1).removeBond(b)
+	m.atomWithIid(b.a2).removeBond(b)
+	for i, mb := range m.bonds {
+		if mb == b {
+			m.bonds = append(m.bonds[:i], m.bonds[i+1:]...)
+			break
+		}
+	}
+	delete(m.bondsById, bondId)
+
+	m.markStale(stageAll)
+	if len(rids) > 0 {
+		return m.PerceiveRingSystems()
+	}
+	return nil
+}
+
+// RemoveAtom removes the atom with the given input ID from this
+// molecule, after removing all of its bonds.  See `RemoveBond`.
+func (m *Molecule) RemoveAtom(iId uint16) error {
+	if err := m.checkMutable(); err != nil {
+		return err
+	}
+	a := m.atomWithIid(iId)
+	if a == nil {
+		return fmt.Errorf("Unknown atom input ID given : %d", iId)
+	}
+
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		if err := m.RemoveBond(uint16(bid)); err != nil {
+			return err
+		}
+	}
+	m.removeAtom(iId)
+
+	m.markStale(stageAll)
+	return nil
+}
+
+// SetBondOrder changes the type of the bond with the given ID to the
+// given single, double, triple or dative type.  The hydrogen counts of
+// its atoms are not adjusted.
+//
+// The bond counts and the unsaturation of both the atoms of the bond
+// are updated, and their valence is validated.  Should the new valence
+// of either atom be invalid, the change is rolled back, leaving this
+// molecule as it was, and an error is answered.
+//
+// Derived information - such as aromaticity, functional groups and the
+// canonical key - becomes stale; this molecule is no longer
+// normalised.  Answers an error if this molecule is frozen.
+func (m *Molecule) SetBondOrder(bondId uint16, order cmn.BondType) error {
+	if err := m.checkMutable(); err != nil {
+		return err
+	}
+	b := m.bondWithId(bondId)
+	if b == nil {
+		return fmt.Errorf("Unknown bond ID given : %d", bondId)
+	}
+	if order < cmn.BondTypeSingle || order > cmn.BondTypeDative || order == cmn.BondTypeAltern {
+		return fmt.Errorf("Invalid bond type given : %v", order)
+	}
+	if order == b.bType {
+		return nil
+	}
+
+	a1 := m.atomWithIid(b.a1)
+	a2 := m.atomWithIid(b.a2)
+	old := b.bType
+	u1, u2 := a1.unsaturation, a2.unsaturation
+
+	retype := func(bt cmn.BondType) {
+		a1.removeBond(b)
+		a2.removeBond(b)
+		b.bType = bt
+		a1.addBond(b)
+		a2.addBond(b)
+	}
+
+	retype(order)
+	for _, a := range []*_Atom{a1, a2} {
+		err := a.checkValence()
+		if err == nil {
+			err = a.determineUnsaturation()
+		}
+		if err != nil {
+			retype(old)
+			a1.unsaturation, a2.unsaturation = u1, u2
+			return fmt.Errorf("Bond %d can not be of type %v : %v", bondId, order, err)
+		}
+	}
+
+	m.markStale(stageAromaticity | stageHashes)
+	return nil
+}
+
+// AssignFormalCharges assigns charges to the uncharged atoms of this
+// molecule whose valences are not those of their elements, as is
+// common in structures exported by tools that drop charges.  A
+// four-bonded nitrogen, for instance, becomes a cation, and a
+// singly-bonded oxygen with no hydrogen an anion.  See
+// `_Atom.inferFormalCharge`.
+//
+// Atoms that already carry a charge are left alone.  Should the
+// valence of any atom not be reconcilable, no charge is assigned, and
+// an error is answered.
+//
+// When any charge is assigned, derived information becomes stale; this
+// molecule is no longer normalised.  Answers an error if this molecule
+// is frozen.
+func (m *Molecule) AssignFormalCharges() error {
+	if err := m.checkMutable(); err != nil {
+		return err
+	}
+	charges := make(map[uint16]int, cmn.ListSizeSmall)
 	for _, a := range m.atoms {
-		if a.iId == id {
-			return a
+		ch, err := a.inferFormalCharge()
+		if err != nil {
+			return err
+		}
+		if ch != 0 {
+			charges[a.iId] = ch
+		}
+	}
+
+	if len(charges) == 0 {
+		return nil
+	}
+	for aid, ch := range charges {
+		m.atomWithIid(aid).charge = int8(ch)
+	}
+
+	m.markStale(stageAromaticity | stageHashes)
+	return nil
+}
+
+// InferHydrogenCounts sets the hydrogen counts of the atoms of this
+// molecule according to the given policy, as is needed for structures
+// read from formats that omit hydrogen atoms.  See
+// `_Atom.inferHydrogenCount`.
+//
+// Bonds given as aromatic in the input are first assigned orders, as
+// in normalisation, since the counts of aromatic atoms depend on
+// them.  See `Kekulize`.  Should no Kekulé structure exist, an error
+// is answered.
+//
+// Atoms of elements with no default valence are left alone.  Should
+// the count of any other atom not be inferable, no count is changed,
+// and an error is answered.
+//
+// When any count changes, derived information becomes stale; this
+// molecule is no longer normalised.  Answers an error if this molecule
+// is frozen.
+func (m *Molecule) InferHydrogenCounts(policy cmn.HydrogenPolicy) error {
+	if policy == cmn.HydrogenPolicyExplicit {
+		return nil
+	}
+	if err := m.checkMutable(); err != nil {
+		return err
+	}
+	if m.hasUnassignedBondOrders() {
+		if err := m.Kekulize(); err != nil {
+			return err
+		}
+		m.markStale(stageAromaticity | stageHashes)
+	}
+
+	old := make(map[uint16]uint8, len(m.atoms))
+	for _, a := range m.atoms {
+		old[a.iId] = a.hCount
+	}
+	changed := false
+	for _, a := range m.atoms {
+		if cmn.PeriodicTable[a.symbol].Valence < 0 {
+			continue
+		}
+		if err := a.inferHydrogenCount(policy); err != nil {
+			for _, oa := range m.atoms {
+				oa.hCount = old[oa.iId]
+			}
+			return err
+		}
+		changed = changed || a.hCount != old[a.iId]
+	}
+
+	if changed {
+		m.markStale(stageAromaticity | stageHashes)
+	}
+	return nil
+}
+
+// dissolveRing removes the given ring from this molecule, and from its
+// atoms and bonds.  Ring systems are not updated.
+func (m *Molecule) dissolveRing(r *_Ring) {
+	for _, aid := range r.atoms {
+		m.atomWithIid(aid).removeRing(r)
+	}
+	for _, bid := range r.bonds {
+		m.bondWithId(bid).removeRing(r.id)
+	}
+
+	for i, mr := range m.rings {
+		if mr == r {
+			m.rings = append(m.rings[:i], m.rings[i+1:]...)
+			break
+		}
+	}
+}
+
+// _Stage identifies a kind of information derived from the structure
+// of a molecule.  Stages are combined as a bit mask, to record which of
+// them are stale.
+type _Stage uint8
+
+const (
+	stageRings       _Stage = 1 << iota // Rings and ring systems.
+	stageAromaticity                    // Unsaturation and aromaticity.
+	stageDistances                      // Topological distances and paths.
+	stageHashes                         // Functional groups, pseudo-hashes, normalised IDs and the canonical key.
+
+	stageAll = stageRings | stageAromaticity | stageDistances | stageHashes
+)
+
+// markStale records that the structure of this molecule has changed in
+// a manner that invalidates the given stages of derived information.
+// Aromaticity depends on rings; it is invalidated with them.
+//
+// Distances and the canonical key of the old structure are discarded,
+// when invalidated.  In any case, this molecule is no longer
+// normalised.
+func (m *Molecule) markStale(stages _Stage) {
+	if stages&stageRings != 0 {
+		stages |= stageAromaticity
+	}
+	m.dirty |= stages
+	m.isNormalised = false
+
+	if stages&stageDistances != 0 {
+		m.dists = nil
+		m.paths = nil
+	}
+	if stages&stageHashes != 0 {
+		m.canonicalKey = ""
+	}
+}
+
+// refresh recomputes those of the given stages of derived information
+// that are stale.  Rings and ring systems are perceived afresh, and
+// aromaticity is determined afresh, as needed.  Since aromaticity
+// depends on rings, refreshing the former refreshes the latter, too.
+//
+// The remaining stages can only be recomputed by normalising this
+// molecule.  An error is answered if any of them is requested, and is
+// stale.
+//
+// Query methods invoke this method; they may, hence, modify a stale
+// molecule.  See `markStale`.
+func (m *Molecule) refresh(stages _Stage) error {
+	if stages&stageAromaticity != 0 {
+		stages |= stageRings
+	}
+	if m.dirty&stages&(stageDistances|stageHashes) != 0 {
+		return fmt.Errorf("Molecule %d has not been normalised.", m.id)
+	}
+
+	if m.dirty&stages&stageRings != 0 {
+		if err := m.PerceiveRings(); err != nil {
+			return err
+		}
+		if err := m.PerceiveRingSystems(); err != nil {
+			return err
+		}
+		// Perceiving rings resets aromaticity.
+		m.dirty = m.dirty&^stageRings | stageAromaticity
+	}
+
+	if m.dirty&stages&stageAromaticity != 0 {
+		if m.hasUnassignedBondOrders() {
+			if err := m.Kekulize(); err != nil {
+				return err
+			}
+		}
+		for _, a := range m.atoms {
+			if err := a.determineUnsaturation(); err != nil {
+				return err
+			}
+		}
+		if err := m.DetermineAromaticity(); err != nil {
+			return err
 		}
+		m.dirty &^= stageAromaticity
 	}
 
 	return nil
 }
 
+// atomWithIid answers the atom for the given input ID, if found.
+// Answers `nil` otherwise.
+func (m *Molecule) atomWithIid(id uint16) *_Atom {
+	return m.atomsByIid[id]
+}
+
 // atomWithNid answers the atom for the given normalised ID, if found.
 // Answers `nil` otherwise.
 func (m *Molecule) atomWithNid(id uint16) *_Atom {
@@ -193,14 +814,12 @@ func (m *Molecule) atomWithNid(id uint16) *_Atom {
 
 // bondWithId answers the bond for the given ID, if found.  Answers
 // `nil` otherwise.
+//
+// The bond set of an atom is indexed by bond ID.  Hence, a bit index
+// answered by `NextSet` on that set is itself the ID to give here,
+// with no adjustment.
 func (m *Molecule) bondWithId(id uint16) *_Bond {
-	for _, b := range m.bonds {
-		if b.id == id {
-			return b
-		}
-	}
-
-	return nil
+	return m.bondsById[id]
 }
 
 // ringWithId answers the ring for the given ID, if found.  Answers
@@ -221,13 +840,12 @@ func (m *Molecule) ringWithId(id uint8) *_Ring {
 // Note that the two given atoms are represented by their input IDs,
 // NOT normalised IDs.
 func (m *Molecule) bondBetween(a1id, a2id uint16) *_Bond {
-	for _, b := range m.bonds {
-		if (b.a1 == a1id && b.a2 == a2id) || (b.a2 == a1id && b.a1 == a2id) {
-			return b
-		}
+	a1 := m.atomWithIid(a1id)
+	if a1 == nil {
+		return nil
 	}
 
-	return nil
+	return a1.bondTo(a2id)
 }
 
 // bondCount answers the total number of bonds of the given type in
@@ -286,3 +904,71 @@ func (m *Molecule) aromaticRingSystemCount() int {
 
 	return c
 }
+
+// AromaticAtomCount answers the number of aromatic atoms in this
+// molecule.  Stale rings and aromaticity are determined afresh; `0` is
+// answered if that fails.  See `refresh`.
+func (m *Molecule) AromaticAtomCount() int {
+	if m.refresh(stageAromaticity) != nil {
+		return 0
+	}
+
+	c := 0
+	for _, a := range m.atoms {
+		if a.isInAroRing {
+			c++
+		}
+	}
+
+	return c
+}
+
+// AromaticBondCount answers the number of aromatic bonds in this
+// molecule.  Stale rings and aromaticity are determined afresh; `0` is
+// answered if that fails.  See `refresh`.
+func (m *Molecule) AromaticBondCount() int {
+	if m.refresh(stageAromaticity) != nil {
+		return 0
+	}
+
+	c := 0
+	for _, b := range m.bonds {
+		if b.isAro {
+			c++
+		}
+	}
+
+	return c
+}
+
+// NetCharge answers the sum of the residual charges on the atoms of
+// this molecule.
+func (m *Molecule) NetCharge() int {
+	c := 0
+	for _, a := range m.atoms {
+		c += int(a.charge)
+	}
+
+	return c
+}
+
+// IsChargeBalanced answers if the residual charges on the atoms of
+// this molecule cancel out.
+func (m *Molecule) IsChargeBalanced() bool {
+	return m.NetCharge() == 0
+}
+
+// ChargeInconsistentAtoms answers the input IDs of those atoms whose
+// bonds and hydrogen atoms exceed the valence permitted by their
+// charges, e.g. a neutral nitrogen with four bonds.  See
+// `isChargeConsistent`.
+func (m *Molecule) ChargeInconsistentAtoms() []uint16 {
+	ids := make([]uint16, 0, cmn.ListSizeTiny)
+	for _, a := range m.atoms {
+		if !a.isChargeConsistent() {
+			ids = append(ids, a.iId)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,205 @@
+package molecule
+
+import (
+	"fmt"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// MoleculeBuilder builds a molecule, one atom or bond at a time.
+//
+// This type is public.  It is a convenience over `AtomBuilder` and
+// `BondBuilder`: it assigns the input IDs of atoms and the IDs of
+// bonds itself, so that callers need not keep them in sequence.
+//
+// Bonds to plain hydrogen atoms are treated as elsewhere: they only
+// increment the hydrogen counts of their other atoms.  Hydrogen atoms
+// so accounted for are removed from the molecule when it is finished.
+//
+// A builder builds exactly one molecule - or, when splitting
+// disconnected input, one per component.  It can not be used after
+// `Finish` or `FinishAll` is called.
+type MoleculeBuilder struct {
+	mol *Molecule    // Molecule being built by this builder.
+	ab  *AtomBuilder // Builder of the atoms of the molecule.
+	bb  *BondBuilder // Builder of the bonds of the molecule.
+
+	hs      []uint16            // Hydrogen atoms folded into their neighbours.
+	hPolicy cmn.HydrogenPolicy  // How further hydrogen atoms are inferred.
+	cPolicy cmn.ComponentPolicy // How disconnected input is treated.
+}
+
+// NewMoleculeBuilder answers a new molecule builder, with an empty
+// molecule.
+func NewMoleculeBuilder() *MoleculeBuilder {
+	mol := New()
+	return &MoleculeBuilder{
+		mol: mol,
+		ab:  mol.NewAtomBuilder(),
+		bb:  mol.NewBondBuilder(),
+		hs:  make([]uint16, 0, cmn.ListSizeSmall),
+	}
+}
+
+// HydrogenPolicy sets how the hydrogen counts of atoms are inferred,
+// when the molecule is finished.  By default, atoms are filled to
+// their valences; `cmn.HydrogenPolicyExplicit` counts only the
+// hydrogen atoms added explicitly.  See `Molecule.InferHydrogenCounts`.
+func (mb *MoleculeBuilder) HydrogenPolicy(policy cmn.HydrogenPolicy) *MoleculeBuilder {
+	mb.hPolicy = policy
+	return mb
+}
+
+// ComponentPolicy sets how disconnected input is treated, when the
+// molecule is finished.  By default, all the components are kept in a
+// single molecule.  See `FinishAll`.
+func (mb *MoleculeBuilder) ComponentPolicy(policy cmn.ComponentPolicy) *MoleculeBuilder {
+	mb.cPolicy = policy
+	return mb
+}
+
+// AddAtom adds an atom of the given element, at the given coordinates,
+// to the molecule.  Answers the input ID assigned to the atom.
+func (mb *MoleculeBuilder) AddAtom(symbol string, x, y, z float32) (atomId uint16, err error) {
+	mol := mb.mol
+	if mol == nil {
+		return 0, fmt.Errorf("Molecule has already been finished.")
+	}
+
+	if _, err := mb.ab.New(symbol, int(mol.nextAtomIid)); err != nil {
+		return 0, err
+	}
+	mb.ab.Coordinates(x, y, z)
+
+	return mb.ab.Build()
+}
+
+// AddBond binds the two atoms with the given input IDs, using a bond
+// of the given order.
+func (mb *MoleculeBuilder) AddBond(a1, a2 uint16, order cmn.BondType) error {
+	mol := mb.mol
+	if mol == nil {
+		return fmt.Errorf("Molecule has already been finished.")
+	}
+
+	at1 := mol.atomWithIid(a1)
+	at2 := mol.atomWithIid(a2)
+	if at1 == nil {
+		return fmt.Errorf("Unknown atom input ID given : %d", a1)
+	}
+	if at2 == nil {
+		return fmt.Errorf("Unknown atom input ID given : %d", a2)
+	}
+
+	// Bonds to plain hydrogen atoms are folded into the hydrogen counts
+	// of their other atoms.
+	if at1.isPlainH() || at2.isPlainH() {
+		if a1 == a2 {
+			return fmt.Errorf("Bond binds atom %d to itself.", a1)
+		}
+		if order != cmn.BondTypeSingle {
+			return fmt.Errorf("Hydrogen atom bound with bond type : %v", order)
+		}
+
+		h, oa := at1, at2
+		if !h.isPlainH() {
+			h, oa = at2, at1
+		}
+		for _, hid := range mb.hs {
+			if hid == h.iId {
+				return fmt.Errorf("Hydrogen atom %d is already bonded.", hid)
+			}
+		}
+		oa.hCount++
+		mb.hs = append(mb.hs, h.iId)
+		return nil
+	}
+
+	if _, err := mb.bb.New(int(mol.nextBondId)); err != nil {
+		return err
+	}
+	if _, err := mb.bb.Atoms(int(a1), int(a2)); err != nil {
+		return err
+	}
+	if _, err := mb.bb.BondType(order); err != nil {
+		return err
+	}
+
+	_, err := mb.bb.Build()
+	return err
+}
+
+// Finish completes the molecule being built, and answers it.  See
+// `FinishAll`, which this method invokes.
+//
+// Since only one molecule can be answered, an error is answered - and
+// all the components discarded - should the component policy of this
+// builder split the molecule into several.
+func (mb *MoleculeBuilder) Finish() (*Molecule, error) {
+	mols, err := mb.FinishAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(mols) != 1 {
+		discardAll(mols)
+		return nil, fmt.Errorf("Molecule has %d components; use FinishAll.", len(mols))
+	}
+
+	return mols[0], nil
+}
+
+// FinishAll completes the molecule being built, infers its hydrogen
+// counts as per the policy of this builder, and normalises it.
+//
+// Should the molecule not be connected, the component policy of this
+// builder applies.  By default, the single molecule is answered as it
+// is.  With `cmn.ComponentPolicyReject`, an error is answered.  With
+// `cmn.ComponentPolicySplit`, a new molecule is answered for each
+// component, in the manner of `Molecule.SplitComponents`, and the
+// molecule built is discarded.
+//
+// An error is answered if any atom's bonds and hydrogen atoms exceed
+// the valence permitted by its charge - a neutral nitrogen atom with
+// four bonds, for instance.  See `Molecule.ChargeInconsistentAtoms`.
+//
+// Should any step fail, all the molecules are discarded.  In either
+// case, this builder can not be used any further.
+func (mb *MoleculeBuilder) FinishAll() ([]*Molecule, error) {
+	mol := mb.mol
+	if mol == nil {
+		return nil, fmt.Errorf("Molecule has already been finished.")
+	}
+	mb.mol = nil
+
+	for _, hid := range mb.hs {
+		mol.removeAtom(hid)
+	}
+
+	if err := mol.InferHydrogenCounts(mb.hPolicy); err != nil {
+		mol.discard()
+		return nil, err
+	}
+	if ids := mol.ChargeInconsistentAtoms(); len(ids) > 0 {
+		mol.discard()
+		return nil, fmt.Errorf("Atoms %v exceed the valences permitted by their charges.", ids)
+	}
+
+	if n := mol.ComponentCount(); n > 1 {
+		switch mb.cPolicy {
+		case cmn.ComponentPolicyReject:
+			mol.discard()
+			return nil, fmt.Errorf("Molecule has %d disconnected components.", n)
+		case cmn.ComponentPolicySplit:
+			frags, err := mol.SplitComponents()
+			mol.discard()
+			return frags, err
+		}
+	}
+
+	if err := mol.Normalise(); err != nil {
+		mol.discard()
+		return nil, err
+	}
+
+	return []*Molecule{mol}, nil
+}
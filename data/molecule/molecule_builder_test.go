@@ -0,0 +1,88 @@
+package molecule
+
+import (
+	"testing"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// TestFinishRejectsChargeInconsistent builds ammonium with no charge:
+// a neutral nitrogen atom with four bonds.
+func TestFinishRejectsChargeInconsistent(t *testing.T) {
+	mb := NewMoleculeBuilder().HydrogenPolicy(cmn.HydrogenPolicyExplicit)
+	n, err := mb.AddAtom("N", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		h, err := mb.AddAtom("H", float32(i), 1, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := mb.AddBond(n, h, cmn.BondTypeSingle); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if m, err := mb.Finish(); err == nil {
+		m.discard()
+		t.Error("neutral nitrogen atom with four bonds accepted")
+	}
+}
+
+// buildEthanolWater answers a builder holding ethanol and water, as a
+// single disconnected input, with the given component policy.
+func buildEthanolWater(t *testing.T, policy cmn.ComponentPolicy) *MoleculeBuilder {
+	mb := NewMoleculeBuilder().ComponentPolicy(policy)
+	ids := make([]uint16, 0, 4)
+	for _, sym := range []string{"C", "C", "O", "O"} {
+		aid, err := mb.AddAtom(sym, 0, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, aid)
+	}
+	for _, p := range [][2]int{{0, 1}, {1, 2}} {
+		if err := mb.AddBond(ids[p[0]], ids[p[1]], cmn.BondTypeSingle); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return mb
+}
+
+func TestFinishAllComponentPolicies(t *testing.T) {
+	mols, err := buildEthanolWater(t, cmn.ComponentPolicyKeep).FinishAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mols) != 1 || mols[0].ComponentCount() != 2 {
+		t.Errorf("kept %d molecules; want 1, of 2 components", len(mols))
+	}
+	discardAll(mols)
+
+	if mols, err := buildEthanolWater(t, cmn.ComponentPolicyReject).FinishAll(); err == nil {
+		discardAll(mols)
+		t.Error("disconnected input accepted")
+	}
+
+	mols, err = buildEthanolWater(t, cmn.ComponentPolicySplit).FinishAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer discardAll(mols)
+	if len(mols) != 2 {
+		t.Fatalf("split into %d molecules; want 2", len(mols))
+	}
+	for i, want := range []string{"C2H6O", "H2O"} {
+		if f := mols[i].Formula(); f != want || !mols[i].IsNormalised() {
+			t.Errorf("component %d : formula %s, normalised : %v; want %s, true", i, f, mols[i].IsNormalised(), want)
+		}
+	}
+}
+
+func TestFinishSplitNeedsFinishAll(t *testing.T) {
+	if m, err := buildEthanolWater(t, cmn.ComponentPolicySplit).Finish(); err == nil {
+		m.discard()
+		t.Error("several components answered as one molecule")
+	}
+}
@@ -0,0 +1,250 @@
+package molecule
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// TestServeSingleDispatch sends requests to one molecule from several
+// agents at once - two through `Serve`, and one on the molecule's own
+// input channel - and checks that every request is processed exactly
+// once.  Run it with `-race` to check that the requests are not
+// processed concurrently.
+func TestServeSingleDispatch(t *testing.T) {
+	const agents, perAgent = 3, 50
+
+	m := New()
+	defer m.discard()
+
+	chans := make([]chan<- InMessage, agents)
+	for i := 0; i < agents-1; i++ {
+		in := make(chan InMessage)
+		m.Serve(in)
+		chans[i] = in
+		defer close(in)
+	}
+	chans[agents-1] = m.InChannel()
+
+	// Every agent tries to add the same atoms; only one addition of
+	// each may succeed.
+	var wg sync.WaitGroup
+	results := make([][]OutMessage, agents)
+	for i := 0; i < agents; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			out := make(chan OutMessage)
+			for j := 1; j <= perAgent; j++ {
+				ab := &AtomBuilder{mol: m, a: newAtom(m, 6, j)}
+				chans[i] <- InMessage{ReqAddAtom, uint64(j), out, ab}
+				results[i] = append(results[i], <-out)
+
+				tag := fmt.Sprintf("agent-%d-%d", i, j)
+				chans[i] <- InMessage{ReqAddTag, uint64(j), out, tag}
+				results[i] = append(results[i], <-out)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	added := 0
+	for _, rs := range results {
+		for _, r := range rs {
+			switch r.Status {
+			case StSuccess:
+				if _, ok := r.Payload.(uint16); ok {
+					added++
+				}
+			case StAlreadyExists:
+			default:
+				t.Fatalf("unexpected status %d for request %d", r.Status, r.Cookie)
+			}
+		}
+	}
+	if added != perAgent {
+		t.Errorf("%d atoms added; want %d", added, perAgent)
+	}
+	if len(m.atoms) != perAgent || len(m.atomsByIid) != perAgent {
+		t.Errorf("molecule has %d atoms, %d indexed; want %d", len(m.atoms), len(m.atomsByIid), perAgent)
+	}
+	for i := 0; i < agents; i++ {
+		for j := 1; j <= perAgent; j++ {
+			if _, ok := m.Attribute(fmt.Sprintf("agent-%d-%d", i, j)); !ok {
+				t.Errorf("tag of agent %d, request %d is missing", i, j)
+			}
+		}
+	}
+}
+
+// TestServeAfterExit checks that requests forwarded to a terminated
+// molecule are answered, rather than left waiting.
+func TestServeAfterExit(t *testing.T) {
+	m := New()
+	m.discard()
+	<-m.done
+
+	in := make(chan InMessage)
+	defer close(in)
+	m.Serve(in)
+
+	out := make(chan OutMessage)
+	in <- InMessage{ReqAddTag, 7, out, "late"}
+	if r := <-out; r.Status != StNotFound || r.Cookie != 7 {
+		t.Errorf("got status %d, cookie %d; want %d, 7", r.Status, r.Cookie, StNotFound)
+	}
+}
+
+// TestAddStatuses checks the statuses answered to requests that add
+// atoms and bonds, for each way in which they can fail.
+func TestAddStatuses(t *testing.T) {
+	m := New()
+	defer m.discard()
+
+	out := make(chan OutMessage)
+	send := func(req RequestType, payload interface{}) StatusType {
+		m.InChannel() <- InMessage{req, 1, out, payload}
+		return (<-out).Status
+	}
+	addAtom := func(iId int) StatusType {
+		return send(ReqAddAtom, &AtomBuilder{mol: m, a: newAtom(m, 6, iId)})
+	}
+	addBond := func(id int, a1, a2 uint16) StatusType {
+		b := newBond(m, id)
+		b.a1, b.a2, b.bType = a1, a2, cmn.BondTypeSingle
+		return send(ReqAddBond, &BondBuilder{mol: m, b: b})
+	}
+
+	type check struct {
+		what string
+		got  StatusType
+		want StatusType
+	}
+	checks := []check{
+		{"atom 1", addAtom(1), StSuccess},
+		{"atom 2", addAtom(2), StSuccess},
+		{"duplicate atom 1", addAtom(1), StAlreadyExists},
+		{"bond 1", addBond(1, 1, 2), StSuccess},
+		{"duplicate bond 1", addBond(1, 1, 2), StAlreadyExists},
+		{"bond to missing atom", addBond(2, 1, 9), StNotFound},
+	}
+	m.Freeze()
+	checks = append(checks,
+		check{"atom 3, frozen", addAtom(3), StIncorrectParameter},
+		check{"bond 2, frozen", addBond(2, 1, 2), StIncorrectParameter},
+	)
+
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s : status %d; want %d", c.what, c.got, c.want)
+		}
+	}
+}
+
+// TestRemoveRingBond breaks ring bonds of decalin.  Removing the fusion
+// bond leaves a single ten-membered ring; removing a bond of that ring
+// leaves none.  Rings are perceived afresh when next queried.
+func TestRemoveRingBond(t *testing.T) {
+	// C1 to C6 form one ring, and C5, C6 and C7 to C10 the other; C5-C6
+	// is the fusion bond.
+	m := buildMolecule(t, []string{"C", "C", "C", "C", "C", "C", "C", "C", "C", "C"}, testBonds([][3]int{
+		{1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {4, 5, 1}, {5, 6, 1}, {6, 1, 1},
+		{6, 7, 1}, {7, 8, 1}, {8, 9, 1}, {9, 10, 1}, {10, 5, 1},
+	}))
+	defer m.discard()
+	if n := m.RingCount(); n != 2 {
+		t.Fatalf("decalin : %d rings; want 2", n)
+	}
+	m.Unfreeze()
+
+	cases := []struct {
+		name     string
+		a1, a2   uint16
+		sizes    []int // Sizes of the rings that remain.
+		systems  int
+		acyclics []uint16 // Atoms no longer in any ring.
+	}{
+		{"fusion bond", 5, 6, []int{10}, 1, nil},
+		{"ring bond", 1, 2, nil, 0, []uint16{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+	}
+	for _, c := range cases {
+		b := m.atomWithIid(c.a1).bondTo(c.a2)
+		if err := m.RemoveBond(b.id); err != nil {
+			t.Fatalf("%s : %v", c.name, err)
+		}
+		if m.IsNormalised() {
+			t.Errorf("%s : molecule still normalised", c.name)
+		}
+
+		// Stale rings are perceived afresh on this query.
+		if n := m.RingCount(); n != len(c.sizes) {
+			t.Errorf("%s : %d rings; want %d", c.name, n, len(c.sizes))
+		} else {
+			for i, r := range m.rings {
+				if r.size() != c.sizes[i] {
+					t.Errorf("%s : ring %d has size %d; want %d", c.name, r.id, r.size(), c.sizes[i])
+				}
+			}
+		}
+		if n := m.RingSystemCount(); n != c.systems {
+			t.Errorf("%s : %d ring systems; want %d", c.name, n, c.systems)
+		}
+		for _, id := range c.acyclics {
+			if m.atomWithIid(id).isCyclic() {
+				t.Errorf("%s : atom %d is still cyclic", c.name, id)
+			}
+		}
+		if m.atomWithIid(c.a1).bondTo(c.a2) != nil {
+			t.Errorf("%s : atoms %d and %d are still bonded", c.name, c.a1, c.a2)
+		}
+	}
+}
+
+// TestAssignFormalCharges checks the charges assigned to ammonium and
+// to nitromethane, drawn as `CH3-N(=O)-O` without charges.
+func TestAssignFormalCharges(t *testing.T) {
+	cases := []struct {
+		name    string
+		atNums  []uint8
+		hCounts []uint8
+		bonds   []_TestBond
+		charges []int8
+	}{
+		{"ammonium", []uint8{7}, []uint8{4}, nil, []int8{1}},
+		{"nitromethane", []uint8{6, 7, 8, 8}, []uint8{3, 0, 0, 0},
+			testBonds([][3]int{{1, 2, 1}, {2, 3, 2}, {2, 4, 1}}),
+			[]int8{0, 1, 0, -1}},
+	}
+	for _, c := range cases {
+		m := New()
+		for i, atNum := range c.atNums {
+			a := newAtom(m, atNum, i+1)
+			a.hCount = c.hCounts[i]
+			if err := m.addAtom(a); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for i, tb := range c.bonds {
+			b := newBond(m, i+1)
+			b.a1, b.a2, b.bType = uint16(tb.a1), uint16(tb.a2), tb.bType
+			if err := m.addBond(b); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := m.AssignFormalCharges(); err != nil {
+			t.Errorf("%s : %v", c.name, err)
+		}
+		for i, want := range c.charges {
+			if ch := m.atomWithIid(uint16(i + 1)).charge; ch != want {
+				t.Errorf("%s : atom %d has charge %d; want %d", c.name, i+1, ch, want)
+			}
+		}
+		if ids := m.ChargeInconsistentAtoms(); len(ids) != 0 {
+			t.Errorf("%s : atoms %v are still inconsistent with their charges", c.name, ids)
+		}
+		m.discard()
+	}
+}
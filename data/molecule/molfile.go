@@ -0,0 +1,605 @@
+package molecule
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// mdlField answers the trimmed text in the given columns of the given
+// line.  Columns beyond the end of the line are treated as blank.
+func mdlField(line string, from, to int) string {
+	if from >= len(line) {
+		return ""
+	}
+	if to > len(line) {
+		to = len(line)
+	}
+
+	return strings.TrimSpace(line[from:to])
+}
+
+// mdlInt answers the integer in the given columns of the given line.
+// A blank field is read as `0`.
+func mdlInt(line string, from, to int) (int, error) {
+	f := mdlField(line, from, to)
+	if f == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(f)
+}
+
+// mdlFloat answers the real number in the given columns of the given
+// line.
+func mdlFloat(line string, from, to int) (float32, error) {
+	f, err := strconv.ParseFloat(mdlField(line, from, to), 32)
+	if err != nil {
+		return 0, err
+	}
+
+	return float32(f), nil
+}
+
+// _ParseError records a problem in a structure file, together with
+// the number of the offending line.
+type _ParseError struct {
+	line int
+	err  error
+}
+
+func (e *_ParseError) Error() string {
+	return fmt.Sprintf("Line %d : %v", e.line, e.err)
+}
+
+// MolfileParser parses MDL V2000 molfiles embedded in the input of
+// other readers, such as those of RXN files.
+type MolfileParser struct {
+	// HydrogenPolicy determines how the hydrogen counts of atoms are
+	// inferred.  By default, atoms are filled to their valences.
+	HydrogenPolicy cmn.HydrogenPolicy
+}
+
+// ParseMolfile constructs a new molecule from the given lines of an
+// MDL V2000 molfile, starting with its header block, using a default
+// `MolfileParser`.
+func ParseMolfile(lines []string, firstLine int) (*Molecule, int, error) {
+	return MolfileParser{}.Parse(lines, firstLine)
+}
+
+// Parse constructs a new molecule from the given lines of an MDL V2000
+// molfile, starting with its header block.  The number of the first
+// given line is used to report the location of errors.
+//
+// Answers the molecule and the number of lines consumed.  See
+// `parseMolfile`.
+func (mp MolfileParser) Parse(lines []string, firstLine int) (*Molecule, int, error) {
+	return parseMolfile(lines, firstLine, mp.HydrogenPolicy)
+}
+
+// parseMolfile constructs a new molecule from the given lines of an
+// MDL V2000 molfile, starting with its header block.  The number of
+// the first given line is used to report the location of errors.
+//
+// Parsing stops at the `M  END` line.  Answers the molecule and the
+// number of lines consumed, including that of `M  END`.
+//
+// Bonds to hydrogen atoms are not created, as elsewhere; they only
+// increment the hydrogen counts of their other atoms.  Hydrogen atoms
+// so accounted for are then removed from the molecule.  Further
+// hydrogen atoms are inferred as per the given policy.  See
+// `InferHydrogenCounts`.
+//
+// Charges and isotopes given as `M  CHG` and `M  ISO` properties
+// supersede those given in the atom block.
+func parseMolfile(lines []string, firstLine int, policy cmn.HydrogenPolicy) (*Molecule, int, error) {
+	lerr := func(idx int, err error) error {
+		return &_ParseError{firstLine + idx, err}
+	}
+
+	if len(lines) < 4 {
+		return nil, len(lines), lerr(len(lines), fmt.Errorf("Incomplete header block."))
+	}
+
+	counts := lines[3]
+	if v := mdlField(counts, 33, 39); v != "" && v != "V2000" {
+		return nil, 4, lerr(3, fmt.Errorf("Unsupported molfile version : %s", v))
+	}
+	nAtoms, err := mdlInt(counts, 0, 3)
+	if err != nil {
+		return nil, 4, lerr(3, fmt.Errorf("Invalid atom count : %v", err))
+	}
+	nBonds, err := mdlInt(counts, 3, 6)
+	if err != nil {
+		return nil, 4, lerr(3, fmt.Errorf("Invalid bond count : %v", err))
+	}
+	if len(lines) < 4+nAtoms+nBonds {
+		return nil, len(lines), lerr(len(lines), fmt.Errorf("Expected %d atoms and %d bonds.", nAtoms, nBonds))
+	}
+
+	m := New()
+	idx := 4
+
+	ab := m.NewAtomBuilder()
+	massDiffs := make([]uint16, 0, cmn.ListSizeTiny)
+	for i := 1; i <= nAtoms; i, idx = i+1, idx+1 {
+		hasDiff, err := parseMolfileAtom(ab, lines[idx], i)
+		if err != nil {
+			m.discard()
+			return nil, idx + 1, lerr(idx, err)
+		}
+		if hasDiff {
+			massDiffs = append(massDiffs, uint16(i))
+		}
+	}
+
+	// Isotopes are applied ahead of the bond block, since they decide
+	// which hydrogen atoms are folded into the counts of their
+	// neighbours.
+	if i, err := parseMolfileIsotopes(m, lines, idx+nBonds, massDiffs); err != nil {
+		m.discard()
+		return nil, i + 1, lerr(i, err)
+	}
+
+	hs := make([]uint16, 0, nAtoms)
+	bb := m.NewBondBuilder().AcceptAromatic(true)
+	for i := 1; i <= nBonds; i, idx = i+1, idx+1 {
+		hid, err := parseMolfileBond(bb, lines[idx])
+		if err != nil {
+			m.discard()
+			return nil, idx + 1, lerr(idx, err)
+		}
+		if hid > 0 {
+			hs = append(hs, hid)
+		}
+	}
+
+	chgSeen := false
+	for ; idx < len(lines); idx++ {
+		line := lines[idx]
+		switch {
+		case strings.HasPrefix(line, "M  END"):
+			for _, hid := range hs {
+				m.removeAtom(hid)
+			}
+			if err := m.InferHydrogenCounts(policy); err != nil {
+				m.discard()
+				return nil, idx + 1, lerr(idx, err)
+			}
+			return m, idx + 1, nil
+
+		case strings.HasPrefix(line, "M  CHG"):
+			if !chgSeen {
+				// The charge property supersedes all the charges,
+				// and radical states, given in the atom block.
+				for _, a := range m.atoms {
+					a.charge = 0
+					a.radical = cmn.RadicalNone
+				}
+				chgSeen = true
+			}
+			if err := parseMolfileCharges(m, line); err != nil {
+				m.discard()
+				return nil, idx + 1, lerr(idx, err)
+			}
+		}
+	}
+
+	m.discard()
+	return nil, idx, lerr(idx, fmt.Errorf("Missing 'M  END' line."))
+}
+
+// parseMolfileAtom builds the atom described by the given line of the
+// atom block of a molfile, and adds it to the builder's molecule.
+// Answers if the line gives a mass difference for the atom.
+func parseMolfileAtom(ab *AtomBuilder, line string, iId int) (bool, error) {
+	x, err := mdlFloat(line, 0, 10)
+	if err != nil {
+		return false, fmt.Errorf("Invalid X-coordinate : %v", err)
+	}
+	y, err := mdlFloat(line, 10, 20)
+	if err != nil {
+		return false, fmt.Errorf("Invalid Y-coordinate : %v", err)
+	}
+	z, err := mdlFloat(line, 20, 30)
+	if err != nil {
+		return false, fmt.Errorf("Invalid Z-coordinate : %v", err)
+	}
+	dd, err := mdlInt(line, 34, 36)
+	if err != nil {
+		return false, fmt.Errorf("Invalid mass difference : %v", err)
+	}
+	ch, err := mdlInt(line, 36, 39)
+	if err != nil {
+		return false, fmt.Errorf("Invalid charge : %v", err)
+	}
+	val, err := mdlInt(line, 48, 51)
+	if err != nil {
+		return false, fmt.Errorf("Invalid valence : %v", err)
+	}
+	mn, err := mdlInt(line, 60, 63)
+	if err != nil {
+		return false, fmt.Errorf("Invalid atom-atom mapping number : %v", err)
+	}
+
+	if _, err := ab.New(mdlField(line, 31, 34), iId); err != nil {
+		return false, err
+	}
+	netCh, r := mdlChargeFromCode(ch)
+	ab.Coordinates(x, y, z).Charge(netCh).Radical(r).Valence(val).MapNumber(mn)
+	if dd != 0 {
+		ab.Isotope(naturalMassNumber(ab.a.atNum) + dd)
+	}
+
+	_, err = ab.Build()
+	return dd != 0, err
+}
+
+// parseMolfileBond builds the bond described by the given line of the
+// bond block of a molfile, and adds it to the builder's molecule.
+//
+// When the bond involves a hydrogen atom, it only increments the
+// hydrogen count of the other atom.  In that case, the input ID of the
+// hydrogen atom is answered; `0` otherwise.
+func parseMolfileBond(bb *BondBuilder, line string) (uint16, error) {
+	a1, err := mdlInt(line, 0, 3)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid first atom : %v", err)
+	}
+	a2, err := mdlInt(line, 3, 6)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid second atom : %v", err)
+	}
+	bt, err := mdlInt(line, 6, 9)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid bond type : %v", err)
+	}
+	bs, err := mdlInt(line, 9, 12)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid bond stereo : %v", err)
+	}
+
+	mol := bb.mol
+	if _, err := bb.New(int(mol.nextBondId)); err != nil {
+		return 0, err
+	}
+	if _, err := bb.Atoms(a1, a2); err != nil {
+		if bb.b != nil {
+			return 0, err
+		}
+		if mol.atomWithIid(uint16(a1)).isPlainH() {
+			return uint16(a1), nil
+		}
+		return uint16(a2), nil
+	}
+	bType, err := mdlBondType(bt)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := bb.BondType(bType); err != nil {
+		return 0, err
+	}
+	bb.BondStereo(mdlBondStereo(bType, bs))
+
+	_, err = bb.Build()
+	return 0, err
+}
+
+// parseMolfileCharges applies the charges given in the given `M  CHG`
+// property line to the atoms of the given molecule.  Answers an error
+// for a charge outside the range -3 to +3.
+func parseMolfileCharges(m *Molecule, line string) error {
+	return parseMolfileProperty(m, line, "charge", func(a *_Atom, ch int) error {
+		if err := checkCharge(ch); err != nil {
+			return err
+		}
+		a.charge = int8(ch)
+		return nil
+	})
+}
+
+// parseMolfileIsotopes applies the mass numbers given in the `M  ISO`
+// property lines of the given molfile lines, from the given index up
+// to the `M  END` line, to the atoms of the given molecule.  Should any
+// such line be present, the mass differences given in the atom block -
+// to the atoms with the given input IDs - are discarded, since the
+// property supersedes them.  Isotopes denoted by symbols, such as `D`,
+// are retained.
+//
+// Answers the index of the offending line, in case of an error.
+func parseMolfileIsotopes(m *Molecule, lines []string, from int, massDiffs []uint16) (int, error) {
+	seen := false
+	for idx := from; idx < len(lines) && !strings.HasPrefix(lines[idx], "M  END"); idx++ {
+		if !strings.HasPrefix(lines[idx], "M  ISO") {
+			continue
+		}
+		if !seen {
+			for _, aid := range massDiffs {
+				m.atomWithIid(aid).isotope = 0
+			}
+			seen = true
+		}
+
+		err := parseMolfileProperty(m, lines[idx], "isotope", func(a *_Atom, mass int) error {
+			if mass <= 0 || mass > math.MaxUint16 {
+				return fmt.Errorf("Invalid mass number : %d", mass)
+			}
+			a.isotope = uint16(mass)
+			return nil
+		})
+		if err != nil {
+			return idx, err
+		}
+	}
+
+	return from, nil
+}
+
+// parseMolfileProperty applies the values of the given atom property
+// line - such as `M  CHG` - to the atoms of the given molecule, using
+// the given function.  Such a line gives the number of its entries,
+// followed by as many pairs of an atom number and a value.
+func parseMolfileProperty(m *Molecule, line, what string, apply func(*_Atom, int) error) error {
+	fs := strings.Fields(line[6:])
+	if len(fs) == 0 {
+		return fmt.Errorf("Missing %s entry count.", what)
+	}
+	n, err := strconv.Atoi(fs[0])
+	if err != nil || len(fs) < 1+2*n {
+		return fmt.Errorf("Malformed %s property : %s", what, line)
+	}
+
+	for i := 0; i < n; i++ {
+		aid, err1 := strconv.Atoi(fs[1+2*i])
+		v, err2 := strconv.Atoi(fs[2+2*i])
+		if err1 != nil || err2 != nil {
+			return fmt.Errorf("Malformed %s property : %s", what, line)
+		}
+
+		a := m.atomWithIid(uint16(aid))
+		if a == nil {
+			return fmt.Errorf("Unknown atom input ID given : %d", aid)
+		}
+		if err := apply(a, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mdlChargeFromCode answers the charge and the radical state denoted
+// by the given charge code of the atom block of a molfile.  Unknown
+// codes denote an uncharged atom.  See `mdlChargeCode`.
+func mdlChargeFromCode(code int) (int, cmn.Radical) {
+	switch code {
+	case 1, 2, 3, 5, 6, 7:
+		return 4 - code, cmn.RadicalNone
+	case 4:
+		return 0, cmn.RadicalDoublet
+	}
+
+	return 0, cmn.RadicalNone
+}
+
+// mdlBondTypeCoordination is the bond type code used in molfiles for
+// coordinate (dative) bonds.
+const mdlBondTypeCoordination = 9
+
+// mdlBondType answers the bond type denoted by the given bond type
+// code of a molfile.  Aromatic bonds are answered as such; query bond
+// types are not handled.
+func mdlBondType(code int) (cmn.BondType, error) {
+	switch code {
+	case 1, 2, 3:
+		return cmn.BondType(code), nil
+	case 4:
+		return cmn.BondTypeAltern, nil
+	case mdlBondTypeCoordination:
+		return cmn.BondTypeDative, nil
+	}
+
+	return cmn.BondTypeNone, fmt.Errorf("Unhandled bond type code : %d", code)
+}
+
+// mdlBondStereo answers the bond stereo denoted by the given bond
+// stereo code of a molfile, for a bond of the given type.  Single
+// bonds may be wedged up or down, or wavy - `either'; double bonds may
+// be crossed - `either' cis or trans.  Codes that do not apply to the
+// type of the bond are ignored.
+func mdlBondStereo(bType cmn.BondType, code int) cmn.BondStereo {
+	bs := cmn.BondStereo(code)
+	switch {
+	case bType == cmn.BondTypeSingle && (bs == cmn.BondStereoUp || bs == cmn.BondStereoDown || bs == cmn.BondStereoEither):
+		return bs
+	case bType == cmn.BondTypeDouble && bs == cmn.BondStereoDoubleEither:
+		return bs
+	}
+
+	return cmn.BondStereoNone
+}
+
+// mdlBondTypeCode answers the bond type code used in molfiles for the
+// given bond type.
+func mdlBondTypeCode(bType cmn.BondType) int {
+	if bType == cmn.BondTypeDative {
+		return mdlBondTypeCoordination
+	}
+
+	return int(bType)
+}
+
+// mdlChargeCode answers the code used in the atom block of a molfile
+// for the charge and radical state of the given atom.
+func mdlChargeCode(a *_Atom) int {
+	switch a.charge {
+	case 3:
+		return 1
+	case 2:
+		return 2
+	case 1:
+		return 3
+	case -1:
+		return 5
+	case -2:
+		return 6
+	case -3:
+		return 7
+	case 0:
+		if a.radical == cmn.RadicalDoublet {
+			return 4
+		}
+	}
+
+	return 0
+}
+
+// writeMolfile writes this molecule to the given buffer as an MDL
+// V2000 molfile, up to and including its `M  END` line.
+//
+// Atoms are numbered in the order in which they are held.  The
+// hydrogen atoms attached to each atom are written explicitly,
+// following all the other atoms, at the positions answered by
+// `hydrogenPositions`.  Reading the molfile back thus restores the
+// hydrogen counts.  Charges are
+// written both in the atom block and as `M  CHG` properties.  Isotopes
+// are written as `M  ISO` properties, and - when they fit - as mass
+// differences in the atom block as well.  Atom-atom mapping numbers
+// are written in their column of the atom block.
+func (m *Molecule) writeMolfile(buf *bytes.Buffer) {
+	idxs := make(map[uint16]int, len(m.atoms))
+	nH := 0
+	dim := "2D"
+	for i, a := range m.atoms {
+		idxs[a.iId] = i + 1
+		nH += int(a.hCount)
+		if a.Z != 0 {
+			dim = "3D"
+		}
+	}
+
+	buf.WriteByte('\n')
+	fmt.Fprintf(buf, "  %-8s%10s%s\n", "RxnWeavr", "", dim)
+	buf.WriteByte('\n')
+	fmt.Fprintf(buf, "%3d%3d  0  0  0  0  0  0  0  0999 V2000\n", len(m.atoms)+nH, len(m.bonds)+nH)
+
+	const atomFmt = "%10.4f%10.4f%10.4f %-3s%2d%3d  0  0  0  0  0  0  0%3d  0  0\n"
+	for _, a := range m.atoms {
+		dd := 0
+		if a.isotope != 0 {
+			dd = int(a.isotope) - naturalMassNumber(a.atNum)
+			if dd < -3 || dd > 4 {
+				dd = 0 // Given in the `M  ISO` property alone.
+			}
+		}
+		fmt.Fprintf(buf, atomFmt, a.X, a.Y, a.Z, a.symbol, dd, mdlChargeCode(a), a.mapNum)
+	}
+	for _, a := range m.atoms {
+		for _, p := range m.hydrogenPositions(a) {
+			fmt.Fprintf(buf, atomFmt, p[0], p[1], p[2], "H", 0, 0, 0)
+		}
+	}
+
+	for _, b := range m.bonds {
+		fmt.Fprintf(buf, "%3d%3d%3d%3d\n", idxs[b.a1], idxs[b.a2], mdlBondTypeCode(b.bType), b.bStereo)
+	}
+	hIdx := len(m.atoms)
+	for i, a := range m.atoms {
+		for j := 0; j < int(a.hCount); j++ {
+			hIdx++
+			fmt.Fprintf(buf, "%3d%3d%3d%3d\n", i+1, hIdx, cmn.BondTypeSingle, cmn.BondStereoNone)
+		}
+	}
+
+	chgs := make([]int, 0, 2*len(m.atoms))
+	isos := make([]int, 0, 2*len(m.atoms))
+	for i, a := range m.atoms {
+		if a.charge != 0 {
+			chgs = append(chgs, i+1, int(a.charge))
+		}
+		if a.isotope != 0 {
+			isos = append(isos, i+1, int(a.isotope))
+		}
+	}
+	writeMolfileProperty(buf, "CHG", chgs)
+	writeMolfileProperty(buf, "ISO", isos)
+
+	buf.WriteString("M  END\n")
+}
+
+// hydrogenPositions answers coordinates for the hydrogen atoms counted
+// in the given atom, so that they can be written explicitly.
+//
+// The hydrogen atoms are placed in the plane of the atom parallel to
+// the XY plane, at the covalent bond length from it, and spread evenly
+// over the side of the atom away from its neighbours.  The positions
+// are meant only to give bonds of sensible lengths; they are not an
+// optimised geometry.
+func (m *Molecule) hydrogenPositions(a *_Atom) [][3]float32 {
+	n := int(a.hCount)
+	if n == 0 {
+		return nil
+	}
+
+	l := cmn.CovalentBondLength(a.atNum, 1)
+	if l == 0 {
+		l = 1
+	}
+
+	// Point away from the mean direction of the neighbours.
+	nbrs := a.distinctNeighbours()
+	var sx, sy float64
+	for _, nid := range nbrs {
+		nbr := m.atomWithIid(nid)
+		dx, dy := float64(nbr.X-a.X), float64(nbr.Y-a.Y)
+		if d := math.Hypot(dx, dy); d > 0 {
+			sx += dx / d
+			sy += dy / d
+		}
+	}
+	base := 0.0
+	if sx != 0 || sy != 0 {
+		base = math.Atan2(-sy, -sx)
+	}
+
+	step := 2 * math.Pi / float64(len(nbrs)+n)
+	ps := make([][3]float32, n)
+	for i := range ps {
+		t := base + (float64(i)-float64(n-1)/2)*step
+		ps[i] = [3]float32{
+			a.X + float32(l*math.Cos(t)),
+			a.Y + float32(l*math.Sin(t)),
+			a.Z,
+		}
+	}
+	return ps
+}
+
+// writeMolfileProperty writes the given pairs of atom numbers and
+// values as lines of the atom property with the given name, such as
+// `CHG`, with at most eight entries per line.
+func writeMolfileProperty(buf *bytes.Buffer, name string, pairs []int) {
+	for len(pairs) > 0 {
+		n := len(pairs) / 2
+		if n > 8 {
+			n = 8
+		}
+		fmt.Fprintf(buf, "M  %s%3d", name, n)
+		for i := 0; i < n; i++ {
+			fmt.Fprintf(buf, " %3d %3d", pairs[2*i], pairs[2*i+1])
+		}
+		buf.WriteByte('\n')
+		pairs = pairs[2*n:]
+	}
+}
+
+// naturalMassNumber answers the mass number relative to which molfiles
+// express the isotope of an atom of the given element: its average
+// atomic weight, rounded.
+func naturalMassNumber(atNum uint8) int {
+	return int(math.Floor(cmn.PeriodicTable[cmn.ElementSymbols[atNum]].Weight + 0.5))
+}
@@ -0,0 +1,277 @@
+package molecule
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// ethanolMolfile is ethanol, with its hydrogen atoms left implicit, as
+// is usual in V2000 molfiles.
+const ethanolMolfile = `ethanol
+  test
+
+  3  2  0  0  0  0  0  0  0  0999 V2000
+    0.0000    0.0000    0.0000 C   0  0  0  0  0  0  0  0  0  0  0  0
+    1.2990    0.7500    0.0000 C   0  0  0  0  0  0  0  0  0  0  0  0
+    2.5981    0.0000    0.0000 O   0  0  0  0  0  0  0  0  0  0  0  0
+  1  2  1  0
+  2  3  1  0
+M  END`
+
+func molfileLines(s string) []string {
+	return strings.Split(s, "\n")
+}
+
+// hCounts answers the hydrogen counts of the atoms of the given
+// molecule, in input order.
+func hCounts(m *Molecule) []int {
+	hs := make([]int, len(m.atoms))
+	for i, a := range m.atoms {
+		hs[i] = int(a.hCount)
+	}
+	return hs
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParseMolfileFillsHydrogens(t *testing.T) {
+	m, n, err := ParseMolfile(molfileLines(ethanolMolfile), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.discard()
+
+	if n != 10 {
+		t.Errorf("%d lines consumed; want 10", n)
+	}
+	if got, want := hCounts(m), []int{3, 2, 1}; !equalInts(got, want) {
+		t.Errorf("hydrogen counts %v; want %v", got, want)
+	}
+	if err := m.Normalise(); err != nil {
+		t.Fatal(err)
+	}
+	if f := m.Formula(); f != "C2H6O" {
+		t.Errorf("formula %s; want C2H6O", f)
+	}
+}
+
+func TestParseMolfileExplicitHydrogens(t *testing.T) {
+	mp := MolfileParser{HydrogenPolicy: cmn.HydrogenPolicyExplicit}
+	m, _, err := mp.Parse(molfileLines(ethanolMolfile), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.discard()
+
+	if got, want := hCounts(m), []int{0, 0, 0}; !equalInts(got, want) {
+		t.Errorf("hydrogen counts %v; want %v", got, want)
+	}
+}
+
+// aromaticMolfile answers a molfile of a single ring of the given
+// atoms, with all its bonds of MDL type `4`, and no hydrogen atoms.
+func aromaticMolfile(syms []string) string {
+	var sb strings.Builder
+	sb.WriteString("ring\n  test\n\n")
+	fmt.Fprintf(&sb, "%3d%3d  0  0  0  0  0  0  0  0999 V2000\n", len(syms), len(syms))
+	for _, s := range syms {
+		fmt.Fprintf(&sb, "    0.0000    0.0000    0.0000 %-3s 0  0  0  0  0  0  0  0  0  0  0  0\n", s)
+	}
+	for i := range syms {
+		fmt.Fprintf(&sb, "%3d%3d  4  0\n", i+1, (i+1)%len(syms)+1)
+	}
+	sb.WriteString("M  END")
+	return sb.String()
+}
+
+func TestParseMolfileAromaticHydrogens(t *testing.T) {
+	cases := []struct {
+		syms    []string
+		formula string
+	}{
+		{[]string{"C", "C", "C", "C", "C", "C"}, "C6H6"},
+		{[]string{"N", "C", "C", "C", "C", "C"}, "C5H5N"},
+	}
+	for _, c := range cases {
+		m, _, err := ParseMolfile(molfileLines(aromaticMolfile(c.syms)), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Normalise(); err != nil {
+			t.Fatal(err)
+		}
+		if f := m.Formula(); f != c.formula {
+			t.Errorf("formula %s; want %s", f, c.formula)
+		}
+		if n := m.aromaticRingCount(); n != 1 {
+			t.Errorf("%s has %d aromatic rings; want 1", c.formula, n)
+		}
+		m.discard()
+	}
+}
+
+// heavyWaterMolfile is D2O, with one deuterium atom given by its
+// symbol, and the other by an `M  ISO` property.
+const heavyWaterMolfile = `D2O
+  test
+
+  3  2  0  0  0  0  0  0  0  0999 V2000
+    0.0000    0.0000    0.0000 O   0  0  0  0  0  0  0  0  0  0  0  0
+    0.9000    0.0000    0.0000 H   0  0  0  0  0  0  0  0  0  0  0  0
+   -0.3000    0.9000    0.0000 D   0  0  0  0  0  0  0  0  0  0  0  0
+  1  2  1  0
+  1  3  1  0
+M  ISO  1   2   2
+M  END`
+
+func TestParseMolfileIsotopes(t *testing.T) {
+	m, _, err := ParseMolfile(molfileLines(heavyWaterMolfile), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.discard()
+
+	// Deuterium atoms are not folded into hydrogen counts.
+	if len(m.atoms) != 3 || m.atomWithIid(1).hCount != 0 {
+		t.Fatalf("%d atoms, oxygen with %d hydrogen atoms; want 3, 0", len(m.atoms), m.atomWithIid(1).hCount)
+	}
+	for _, iId := range []uint16{2, 3} {
+		if iso := m.atomWithIid(iId).isotope; iso != 2 {
+			t.Errorf("atom %d has mass number %d; want 2", iId, iso)
+		}
+	}
+
+	if err := m.Normalise(); err != nil {
+		t.Fatal(err)
+	}
+	if w := m.MonoisotopicWeight(); math.Abs(w-20.02312) > 1e-4 {
+		t.Errorf("monoisotopic weight %.5f; want 20.02312", w)
+	}
+	if w := m.AverageWeight(); math.Abs(w-20.027) > 1e-3 {
+		t.Errorf("average weight %.3f; want 20.027", w)
+	}
+
+	// The isotopes survive a round trip.
+	var buf bytes.Buffer
+	m.writeMolfile(&buf)
+	if !strings.Contains(buf.String(), "M  ISO  2") {
+		t.Errorf("no isotope property written:\n%s", buf.String())
+	}
+	m2, _, err := ParseMolfile(molfileLines(buf.String()), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m2.discard()
+	if err := m2.Normalise(); err != nil {
+		t.Fatal(err)
+	}
+	if !m.Equals(m2) {
+		t.Errorf("canonical key %s changed to %s", m.CanonicalKey(), m2.CanonicalKey())
+	}
+}
+
+func TestParseMolfileIsotopeSupersedes(t *testing.T) {
+	src := strings.Replace(ethanolMolfile, "M  END", "M  ISO  1   1  13\nM  END", 1)
+	src = strings.Replace(src, "O   0", "O   1", 1)
+	m, _, err := ParseMolfile(molfileLines(src), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.discard()
+
+	if iso := m.atomWithIid(1).isotope; iso != 13 {
+		t.Errorf("carbon has mass number %d; want 13", iso)
+	}
+	if iso := m.atomWithIid(3).isotope; iso != 0 {
+		t.Errorf("oxygen has mass number %d; want none", iso)
+	}
+}
+
+func TestWriteMolfileHydrogenPositions(t *testing.T) {
+	m, _, err := ParseMolfile(molfileLines(ethanolMolfile), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.discard()
+
+	var buf bytes.Buffer
+	m.writeMolfile(&buf)
+	lines := molfileLines(buf.String())
+
+	var nAtoms, nBonds int
+	fmt.Sscanf(lines[3], "%3d%3d", &nAtoms, &nBonds)
+	if nAtoms != 9 || nBonds != 8 {
+		t.Fatalf("%d atoms, %d bonds written; want 9, 8", nAtoms, nBonds)
+	}
+	coords := make([][3]float64, nAtoms)
+	for i := range coords {
+		f := strings.Fields(lines[4+i])
+		for j := range coords[i] {
+			fmt.Sscanf(f[j], "%f", &coords[i][j])
+		}
+	}
+
+	// Every bond, including those to the hydrogen atoms, has a
+	// sensible length.
+	for _, l := range lines[4+nAtoms : 4+nAtoms+nBonds] {
+		var i, j int
+		fmt.Sscanf(l, "%3d%3d", &i, &j)
+		c1, c2 := coords[i-1], coords[j-1]
+		d := math.Sqrt((c1[0]-c2[0])*(c1[0]-c2[0]) + (c1[1]-c2[1])*(c1[1]-c2[1]) + (c1[2]-c2[2])*(c1[2]-c2[2]))
+		if d < 0.9 || d > 1.6 {
+			t.Errorf("bond %d-%d has length %.4f", i, j, d)
+		}
+	}
+
+	m2, _, err := ParseMolfile(lines, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m2.discard()
+	if got, want := hCounts(m2), []int{3, 2, 1}; !equalInts(got, want) {
+		t.Errorf("hydrogen counts %v; want %v", got, want)
+	}
+}
+
+func TestParseMolfileRejectsCharge(t *testing.T) {
+	src := strings.Replace(ethanolMolfile, "M  END", "M  CHG  1   3   4\nM  END", 1)
+	if m, _, err := ParseMolfile(molfileLines(src), 1); err == nil {
+		m.discard()
+		t.Error("charge +4 accepted")
+	}
+}
+
+func TestParseMolfileChargeSupersedesRadical(t *testing.T) {
+	// The atom block marks the oxygen atom a radical, and the charge
+	// property makes it an anion instead.
+	src := strings.Replace(ethanolMolfile, "O   0  0", "O   0  4", 1)
+	src = strings.Replace(src, "M  END", "M  CHG  1   3  -1\nM  END", 1)
+	m, _, err := ParseMolfile(molfileLines(src), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.discard()
+
+	o := m.atomWithIid(3)
+	if o.radical != cmn.RadicalNone || o.charge != -1 {
+		t.Errorf("oxygen has radical %v, charge %d; want none, -1", o.radical, o.charge)
+	}
+	if got, want := hCounts(m), []int{3, 2, 0}; !equalInts(got, want) {
+		t.Errorf("hydrogen counts %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+package molecule
+
+// Neutralize removes the charges of this molecule that can be
+// neutralised by adding or removing a proton, as in its free acid or
+// free base form.
+//
+// A negatively-charged nitrogen, oxygen or sulfur atom gains hydrogen
+// atoms - as a carboxylate does - and a positively-charged nitrogen,
+// oxygen, phosphorus or sulfur atom bearing hydrogen atoms loses them
+// - as an ammonium does - until it is uncharged, or no further proton
+// can be moved.  Permanent charges are left untouched: those of
+// positively-charged atoms without hydrogen atoms, such as quaternary
+// nitrogen, and those of atoms bonded to an oppositely-charged atom,
+// such as in nitro groups and N-oxides.
+//
+// The unsaturation of each affected atom is determined afresh.  If
+// this molecule was normalised, it is normalised again, so that its
+// keys reflect the neutral form.
+//
+// Being a whole-molecule transformation, this method works on frozen
+// molecules as well: it unfreezes this molecule for the duration, and
+// freezes it again on success.  See `Freeze`.
+func (m *Molecule) Neutralize() error {
+	wasNormalised, wasFrozen := m.isNormalised, m.frozen
+	m.Unfreeze()
+
+	changed, err := m.neutralize()
+	if err != nil {
+		return err
+	}
+	if changed && wasNormalised {
+		return m.Normalise()
+	}
+
+	if wasFrozen {
+		m.Freeze()
+	}
+	return nil
+}
+
+// neutralize removes the charges of this molecule as described in
+// `Neutralize`, without normalising it.  Answers if any charge was
+// removed.
+func (m *Molecule) neutralize() (bool, error) {
+	changed := false
+	for _, a := range m.atoms {
+		if a.charge == 0 || a.hasOppositelyChargedNeighbour() {
+			continue
+		}
+
+		switch {
+		case a.charge < 0 && a.isOneOfNOS():
+			a.hCount += uint8(-a.charge)
+			a.charge = 0
+
+		case a.charge > 0 && a.isOneOfNOPS() && a.hCount > 0:
+			for a.charge > 0 && a.hCount > 0 {
+				a.hCount--
+				a.charge--
+			}
+
+		default:
+			continue
+		}
+
+		if err := a.determineUnsaturation(); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+
+	if changed {
+		m.markStale(stageAromaticity | stageHashes)
+	}
+	return changed, nil
+}
+
+// hasOppositelyChargedNeighbour answers if at least one of this atom's
+// neighbours has a charge opposite in sign to that of this atom.
+func (a *_Atom) hasOppositelyChargedNeighbour() bool {
+	mol := a.mol
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		oa := mol.atomWithIid(mol.bondWithId(uint16(bid)).otherAtomIid(a.iId))
+		if int(oa.charge)*int(a.charge) < 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,44 @@
+package molecule
+
+import "testing"
+
+// acetateMolfile is the acetate anion, with its hydrogen atoms left
+// implicit.
+const acetateMolfile = `acetate
+  test
+
+  4  3  0  0  0  0  0  0  0  0999 V2000
+    0.0000    0.0000    0.0000 C   0  0  0  0  0  0  0  0  0  0  0  0
+    1.2990    0.7500    0.0000 C   0  0  0  0  0  0  0  0  0  0  0  0
+    1.2990    2.2500    0.0000 O   0  0  0  0  0  0  0  0  0  0  0  0
+    2.5981    0.0000    0.0000 O   0  0  0  0  0  0  0  0  0  0  0  0
+  1  2  1  0
+  2  3  2  0
+  2  4  1  0
+M  CHG  1   4  -1
+M  END`
+
+func TestNeutralizeNormalised(t *testing.T) {
+	m, _, err := ParseMolfile(molfileLines(acetateMolfile), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.discard()
+	if err := m.Normalise(); err != nil {
+		t.Fatal(err)
+	}
+	charged := m.CanonicalKey()
+
+	if err := m.Neutralize(); err != nil {
+		t.Fatal(err)
+	}
+	if !m.IsNormalised() || !m.IsFrozen() {
+		t.Error("neutralised molecule is not normalised and frozen")
+	}
+	if f := m.Formula(); f != "C2H4O2" {
+		t.Errorf("formula %s; want C2H4O2", f)
+	}
+	if m.CanonicalKey() == charged {
+		t.Error("canonical key does not reflect the neutral form")
+	}
+}
@@ -0,0 +1,376 @@
+package molecule
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"runtime"
+	"sort"
+	"sync"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// Normalise transforms this molecule into its normal form, as
+// described in `doc/design/molecule-normal-form.md`.
+//
+// The following steps are performed, in order.
+//
+//   - Orders are assigned to the bonds given as aromatic in the input.
+//   - Unsaturation of each atom is determined.
+//   - Rings and ring systems are detected.
+//   - Aromaticity of the rings and ring systems is determined.
+//   - Electron-donating and -withdrawing neighbours are counted.
+//   - Functional groups substituted on the atoms are detected.
+//   - Pseudo-hashes of the atoms are computed.
+//   - Normalised IDs are assigned to the atoms.
+//   - Topological distances between the atoms are computed.
+//   - A canonical key of the molecule is computed.
+//
+// The order matters, since later steps depend on the results of the
+// earlier ones.  In particular, aromatic bonds are ranked alike
+// irrespective of the Kekulé structure given in the input.
+//
+// This method is idempotent.  On success, this molecule is frozen, so
+// that the information derived stays valid.  See `Freeze`.
+func (m *Molecule) Normalise() error {
+	m.isNormalised = false
+
+	if m.hasUnassignedBondOrders() {
+		if err := m.Kekulize(); err != nil {
+			return err
+		}
+	}
+
+	for _, a := range m.atoms {
+		if err := a.determineUnsaturation(); err != nil {
+			return err
+		}
+	}
+
+	if err := m.PerceiveRings(); err != nil {
+		return err
+	}
+	if err := m.PerceiveRingSystems(); err != nil {
+		return err
+	}
+	if err := m.DetermineAromaticity(); err != nil {
+		return err
+	}
+	if err := m.computeNeighbourElectronics(); err != nil {
+		return err
+	}
+	if err := m.DetectFunctionalGroups(); err != nil {
+		return err
+	}
+
+	for _, a := range m.atoms {
+		a.computePHash()
+	}
+	m.assignNormalisedIds()
+	m.computeDistances()
+
+	for _, r := range m.rings {
+		if err := r.normalise(); err != nil {
+			return err
+		}
+	}
+
+	m.canonicalKey = m.computeCanonicalKey()
+	m.isNormalised = true
+	m.dirty = 0
+	m.frozen = true
+	return nil
+}
+
+// NormaliseAll normalises the given molecules, using the given number
+// of concurrent workers.  A non-positive number of workers uses one per
+// available processor.  See `Normalise`.
+//
+// Answers the error of normalising each molecule, aligned by index
+// with the molecules; the error of a molecule normalised successfully
+// is `nil`.
+//
+// Normalisation only modifies the molecule being normalised.  Hence,
+// the given molecules should be distinct, and should not be accessed
+// otherwise until this function returns.
+func NormaliseAll(mols []*Molecule, workers int) []error {
+	errs := make([]error, len(mols))
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(mols) {
+		workers = len(mols)
+	}
+
+	idxs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range idxs {
+				if mols[i] == nil {
+					errs[i] = fmt.Errorf("Molecule at index %d is nil.", i)
+					continue
+				}
+				errs[i] = mols[i].Normalise()
+			}
+		}()
+	}
+
+	for i := range mols {
+		idxs <- i
+	}
+	close(idxs)
+	wg.Wait()
+
+	return errs
+}
+
+// IsNormalised answers if this molecule has been normalised since it
+// was last modified.
+func (m *Molecule) IsNormalised() bool {
+	return m.isNormalised
+}
+
+// _RankNbr is a neighbour of an atom, as seen by the ranking
+// procedure.  It holds the index of the neighbour in the molecule's
+// list of atoms, and the order of the bond to it.
+type _RankNbr struct {
+	idx   int
+	order int
+}
+
+// _RankKeys sorts a set of atom indices on their corresponding keys.
+// Keys are compared element by element, in order, from left to
+// right.
+type _RankKeys struct {
+	idxs []int
+	keys [][]int
+	desc bool
+}
+
+func (rk _RankKeys) Len() int {
+	return len(rk.idxs)
+}
+
+func (rk _RankKeys) Swap(i, j int) {
+	rk.idxs[i], rk.idxs[j] = rk.idxs[j], rk.idxs[i]
+}
+
+func (rk _RankKeys) Less(i, j int) bool {
+	c := compareKeys(rk.keys[rk.idxs[i]], rk.keys[rk.idxs[j]])
+	if rk.desc {
+		return c > 0
+	}
+	return c < 0
+}
+
+// compareKeys compares the two given keys element by element.  A
+// shorter key that is a prefix of a longer one is deemed smaller.
+func compareKeys(k1, k2 []int) int {
+	for i := 0; i < len(k1) && i < len(k2); i++ {
+		switch {
+		case k1[i] < k2[i]:
+			return -1
+		case k1[i] > k2[i]:
+			return 1
+		}
+	}
+
+	return len(k1) - len(k2)
+}
+
+// ranksFromKeys answers dense ranks, starting at `1`, of the atoms
+// having the given keys.  Atoms with equal keys receive equal ranks.
+func ranksFromKeys(keys [][]int, desc bool) ([]int, int) {
+	n := len(keys)
+	idxs := make([]int, n)
+	for i := range idxs {
+		idxs[i] = i
+	}
+	sort.Stable(_RankKeys{idxs, keys, desc})
+
+	ranks := make([]int, n)
+	rank := 0
+	for i, idx := range idxs {
+		if i == 0 || compareKeys(keys[idxs[i-1]], keys[idx]) != 0 {
+			rank++
+		}
+		ranks[idx] = rank
+	}
+
+	return ranks, rank
+}
+
+// priorityTuple answers the initial priority tuple of this atom, as
+// described in `doc/design/molecule-normal-form.md`.
+//
+// The tuple comprises this atom's atomic number, followed by those of
+// its expanded neighbours in descending order.  A neighbour across an
+// aromatic bond is listed only once, so that the tuple does not depend
+// on the Kekulé structure.  Hydrogen count, residual charge, the
+// number of aromatic bonds and the mass number follow, to distinguish
+// otherwise similar atoms - including isotopes of an element.
+func (a *_Atom) priorityTuple() []int {
+	const tupleLen = 21
+
+	mol := a.mol
+	t := make([]int, tupleLen, tupleLen+4)
+	t[0] = int(a.atNum)
+
+	nbrs := make([]int, 0, len(a.nbrs))
+	aroCount := 0
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		b := mol.bondWithId(uint16(bid))
+		oa := mol.atomWithIid(b.otherAtomIid(a.iId))
+		n := b.order()
+		if b.isAro {
+			n = 1
+			aroCount++
+		}
+		for i := 0; i < n; i++ {
+			nbrs = append(nbrs, int(oa.atNum))
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(nbrs)))
+	for i := 0; i < len(nbrs) && i < tupleLen-1; i++ {
+		t[i+1] = nbrs[i]
+	}
+
+	return append(t, int(a.hCount), int(a.charge), aroCount, int(a.isotope))
+}
+
+// computePHash computes the pseudo-hash of this atom from its
+// invariant attributes: those of its priority tuple, and whether it
+// is in a ring.  Atoms alike in these attributes have equal
+// pseudo-hashes, irrespective of the input order of the atoms.
+//
+// Rings and aromaticity must have been determined, before this method
+// is invoked.
+func (a *_Atom) computePHash() {
+	t := a.priorityTuple()
+	if a.isCyclic() {
+		t = append(t, 1)
+	} else {
+		t = append(t, 0)
+	}
+
+	a.pHash = hashInts(t)
+}
+
+// hashInts answers the 64-bit FNV-1a hash of the given integers, in
+// order.
+func hashInts(vals []int) uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	for _, v := range vals {
+		binary.LittleEndian.PutUint64(buf[:], uint64(int64(v)))
+		h.Write(buf[:])
+	}
+	return h.Sum64()
+}
+
+// assignNormalisedIds computes a canonical ranking of the atoms in
+// this molecule, and records it as their normalised IDs.
+//
+// The initial ranking uses each atom's priority tuple, with higher
+// tuples receiving lower IDs.  The ranking is then refined
+// iteratively using the ranks of the neighbours, until it stabilises.
+// Ties that remain need not be between equivalent atoms; they are
+// resolved by a search over the ways of breaking them, which keeps the
+// least labelled adjacency.  See `_RankSearch`.  Thus, the IDs do not
+// depend on the order in which the atoms are held.
+func (m *Molecule) assignNormalisedIds() {
+	n := len(m.atoms)
+	if n == 0 {
+		return
+	}
+
+	adj := m.rankAdjacency(true)
+	keys := make([][]int, n)
+	for i, a := range m.atoms {
+		keys[i] = a.priorityTuple()
+	}
+
+	for i, r := range canonicalRanks(keys, adj) {
+		m.atoms[i].nId = uint16(r)
+	}
+}
+
+// rankAdjacency answers the neighbours of each atom in this molecule,
+// aligned by index with its list of atoms, as seen by the ranking
+// procedure.  Bond orders are recorded only if `withOrders` is `true`;
+// they are `0` otherwise.
+func (m *Molecule) rankAdjacency(withOrders bool) [][]_RankNbr {
+	idxs := make(map[uint16]int, len(m.atoms))
+	for i, a := range m.atoms {
+		idxs[a.iId] = i
+	}
+
+	adj := make([][]_RankNbr, len(m.atoms))
+	for i, a := range m.atoms {
+		for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+			b := m.bondWithId(uint16(bid))
+			nbr := _RankNbr{idxs[b.otherAtomIid(a.iId)], 0}
+			if withOrders {
+				nbr.order = b.rankOrder()
+			}
+			adj[i] = append(adj[i], nbr)
+		}
+	}
+
+	return adj
+}
+
+// canonicalRanks answers distinct ranks, starting at `1`, of the atoms
+// with the given initial keys and adjacency.  Higher keys receive
+// lower ranks.  See `assignNormalisedIds` for the procedure.
+func canonicalRanks(keys [][]int, adj [][]_RankNbr) []int {
+	return newRankSearch(keys, adj).best
+}
+
+// rankOrder answers the order of this bond, as used in ranking atoms.
+// Aromatic bonds have an order of their own.
+func (b *_Bond) rankOrder() int {
+	if b.isAro {
+		return int(cmn.BondTypeAltern)
+	}
+	return int(b.bType)
+}
+
+// rankKeys answers single-element keys made of the given ranks.
+func rankKeys(ranks []int) [][]int {
+	keys := make([][]int, len(ranks))
+	for i, r := range ranks {
+		keys[i] = []int{r}
+	}
+	return keys
+}
+
+// refineRanks iteratively refines the given ranks using those of the
+// neighbours of each atom, until the number of distinct ranks does
+// not increase any further.
+func refineRanks(ranks []int, c int, adj [][]_RankNbr) ([]int, int) {
+	n := len(ranks)
+	for c < n {
+		keys := make([][]int, n)
+		for i, nbrs := range adj {
+			k := make([]int, 0, len(nbrs)+1)
+			for _, nbr := range nbrs {
+				k = append(k, 8*ranks[nbr.idx]+nbr.order)
+			}
+			sort.Ints(k)
+			keys[i] = append([]int{ranks[i]}, k...)
+		}
+
+		nranks, nc := ranksFromKeys(keys, false)
+		if nc == c {
+			break
+		}
+		ranks, c = nranks, nc
+	}
+
+	return ranks, c
+}
@@ -0,0 +1,56 @@
+package molecule
+
+import "testing"
+
+// TestNormaliseAll normalises a batch of molecules concurrently, and
+// checks that each gets the same result as when normalised alone.  Run
+// it with `-race` to check that the workers share no mutable state.
+func TestNormaliseAll(t *testing.T) {
+	srcs := []string{ethanolMolfile, acetateMolfile, aromaticMolfile([]string{"N", "C", "C", "C", "C", "C"})}
+	want := make([]string, len(srcs))
+	for i, src := range srcs {
+		m, _, err := ParseMolfile(molfileLines(src), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Normalise(); err != nil {
+			t.Fatal(err)
+		}
+		want[i] = m.CanonicalKey()
+		m.discard()
+	}
+
+	const n = 60
+	mols := make([]*Molecule, n)
+	for i := range mols {
+		if i == n/2 {
+			continue // A `nil` molecule gets an error of its own.
+		}
+		m, _, err := ParseMolfile(molfileLines(srcs[i%len(srcs)]), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer m.discard()
+		mols[i] = m
+	}
+
+	errs := NormaliseAll(mols, 4)
+	if len(errs) != n {
+		t.Fatalf("%d errors answered; want %d", len(errs), n)
+	}
+	for i, m := range mols {
+		if m == nil {
+			if errs[i] == nil {
+				t.Errorf("no error for nil molecule at index %d", i)
+			}
+			continue
+		}
+		if errs[i] != nil {
+			t.Errorf("molecule %d : %v", i, errs[i])
+			continue
+		}
+		if !m.IsNormalised() || m.CanonicalKey() != want[i%len(srcs)] {
+			t.Errorf("molecule %d has canonical key %s; want %s", i, m.CanonicalKey(), want[i%len(srcs)])
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package molecule
+
+import (
+	"fmt"
+)
+
+// _ProtonSite is a site of this molecule that can gain or lose a
+// proton, as reported by `AcidicSites` or `BasicSites`.
+type _ProtonSite struct {
+	iId           uint16 // Input ID of the site atom.
+	isAcid        bool   // Is this an acidic site?
+	isIonised     bool   // Is the site ionised in the molecule?
+	preferIonised bool   // Is the site ionised near neutral pH?
+}
+
+// EnumerateProtonationStates answers the protonation states of this
+// molecule, obtained by adding or removing a proton at each of its
+// acidic and basic sites.  See `AcidicSites` and `BasicSites`.
+//
+// The state expected near neutral pH comes first: carboxylic and
+// sulfonic acids deprotonated, phenols protonated, and amines,
+// amidines and guanidines protonated.  The other states follow in the
+// increasing order of the number of sites at which they differ from
+// it.  At most `maxStates` states are answered.
+//
+// Each state is a separate clone of this molecule, which can be
+// modified independently.  If this molecule was normalised, so is each
+// state.
+func (m *Molecule) EnumerateProtonationStates(maxStates int) ([]*Molecule, error) {
+	if maxStates < 1 {
+		return nil, fmt.Errorf("Invalid maximum number of states given : %d", maxStates)
+	}
+	if err := m.refresh(stageAromaticity); err != nil {
+		return nil, err
+	}
+
+	sites := m.protonSites()
+	n := len(sites)
+
+	states := make([]*Molecule, 0, maxStates)
+	flips := make([]int, 0, n)
+	for k := 0; k <= n && len(states) < maxStates; k++ {
+		// Every combination of `k` sites at which to deviate from the
+		// preferred state, in lexicographic order.
+		flips = flips[:k]
+		for i := range flips {
+			flips[i] = i
+		}
+
+		for len(states) < maxStates {
+			st, err := m.protonationState(sites, flips)
+			if err != nil {
+				discardAll(states)
+				return nil, err
+			}
+			states = append(states, st)
+
+			i := k - 1
+			for i >= 0 && flips[i] == n-k+i {
+				i--
+			}
+			if i < 0 {
+				break
+			}
+			flips[i]++
+			for j := i + 1; j < k; j++ {
+				flips[j] = flips[j-1] + 1
+			}
+		}
+	}
+
+	return states, nil
+}
+
+// protonSites answers the acidic and basic sites of this molecule,
+// with their current and preferred ionisation.  Sites that cannot be
+// toggled - such as an ionised base bearing no hydrogen - are omitted.
+//
+// This method assumes that the aromaticity of this molecule is
+// current.
+func (m *Molecule) protonSites() []_ProtonSite {
+	sites := make([]_ProtonSite, 0, len(m.atoms))
+	for _, a := range m.atoms {
+		switch {
+		case a.isAcidicO():
+			oa := m.atomWithIid(a.distinctNeighbours()[0])
+			sites = append(sites, _ProtonSite{
+				iId:           a.iId,
+				isAcid:        true,
+				isIonised:     a.charge < 0,
+				preferIonised: !oa.isInAroRing,
+			})
+
+		case a.isAliphaticAmineN() || a.isAmidineN():
+			if a.charge > 0 && a.hCount == 0 {
+				continue
+			}
+			sites = append(sites, _ProtonSite{
+				iId:           a.iId,
+				isIonised:     a.charge > 0,
+				preferIonised: true,
+			})
+		}
+	}
+
+	return sites
+}
+
+// protonationState answers a clone of this molecule, in which each of
+// the given sites is in its preferred ionisation, except those whose
+// indices are listed in `flips`.  Should that fail, the clone is
+// discarded.
+func (m *Molecule) protonationState(sites []_ProtonSite, flips []int) (*Molecule, error) {
+	mol := m.Clone()
+	if err := mol.protonate(sites, flips); err != nil {
+		mol.discard()
+		return nil, err
+	}
+
+	return mol, nil
+}
+
+// protonate sets each of the given sites of this molecule in its
+// preferred ionisation, except those whose indices are listed in
+// `flips`.  See `protonationState`.
+func (m *Molecule) protonate(sites []_ProtonSite, flips []int) error {
+	m.Unfreeze()
+
+	fi := 0
+	for i, s := range sites {
+		ionised := s.preferIonised
+		if fi < len(flips) && flips[fi] == i {
+			ionised = !ionised
+			fi++
+		}
+		if ionised == s.isIonised {
+			continue
+		}
+
+		a := m.atomWithIid(s.iId)
+		switch {
+		case s.isAcid && ionised, !s.isAcid && !ionised:
+			a.hCount--
+			a.charge--
+		default:
+			a.hCount++
+			a.charge++
+		}
+		if err := a.determineUnsaturation(); err != nil {
+			return err
+		}
+	}
+
+	wasNormalised := m.isNormalised
+	m.markStale(stageAromaticity | stageHashes)
+	if wasNormalised {
+		return m.Normalise()
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+// Package query provides predicates on the atoms of a molecule, which
+// can be combined to select atoms without writing new package code.
+// See `Molecule.SelectAtoms`.
+package query
+
+import (
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+	"github.com/RxnWeaver/RxnWeaver/data/molecule"
+)
+
+// Predicate answers if the given atom satisfies a condition.
+type Predicate func(molecule.Atom) bool
+
+// IsElement answers a predicate that is satisfied by atoms of the
+// element with the given atomic number.
+func IsElement(atNum uint8) Predicate {
+	return func(atom molecule.Atom) bool {
+		return atom.AtomicNumber() == atNum
+	}
+}
+
+// HasCharge answers a predicate that is satisfied by atoms having the
+// given residual charge.
+func HasCharge(charge int8) Predicate {
+	return func(atom molecule.Atom) bool {
+		return atom.Charge() == charge
+	}
+}
+
+// HasDegree answers a predicate that is satisfied by atoms bonded to
+// the given number of atoms, not counting attached hydrogen atoms.
+func HasDegree(degree int) Predicate {
+	return func(atom molecule.Atom) bool {
+		return atom.Degree() == degree
+	}
+}
+
+// HasHydrogenCount answers a predicate that is satisfied by atoms
+// bearing the given number of hydrogen atoms.
+func HasHydrogenCount(n uint8) Predicate {
+	return func(atom molecule.Atom) bool {
+		return atom.HydrogenCount() == n
+	}
+}
+
+// HasFunctionalGroup answers a predicate that is satisfied by atoms
+// whose primary functional group is of the given kind.  See
+// `Atom.PrimaryFunctionalGroup`.
+func HasFunctionalGroup(kind cmn.FeatureKind) Predicate {
+	return func(atom molecule.Atom) bool {
+		return cmn.FeatureKind(atom.PrimaryFunctionalGroup()) == kind
+	}
+}
+
+// InRing answers a predicate that is satisfied by atoms participating
+// in at least one ring.
+func InRing() Predicate {
+	return func(atom molecule.Atom) bool {
+		return atom.IsCyclic()
+	}
+}
+
+// IsAromatic answers a predicate that is satisfied by atoms that are
+// part of an aromatic ring.
+func IsAromatic() Predicate {
+	return func(atom molecule.Atom) bool {
+		return atom.IsAromatic()
+	}
+}
+
+// And answers a predicate that is satisfied by atoms satisfying all
+// the given predicates.  With no predicates, it is satisfied by all
+// atoms.
+func And(preds ...Predicate) Predicate {
+	return func(atom molecule.Atom) bool {
+		for _, p := range preds {
+			if !p(atom) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or answers a predicate that is satisfied by atoms satisfying at
+// least one of the given predicates.  With no predicates, it is
+// satisfied by no atom.
+func Or(preds ...Predicate) Predicate {
+	return func(atom molecule.Atom) bool {
+		for _, p := range preds {
+			if p(atom) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not answers a predicate that is satisfied by atoms not satisfying
+// the given predicate.
+func Not(pred Predicate) Predicate {
+	return func(atom molecule.Atom) bool {
+		return !pred(atom)
+	}
+}
@@ -0,0 +1,273 @@
+package molecule
+
+import (
+	"sort"
+)
+
+// _RankSearch searches for a canonical ranking of the atoms of a
+// molecule, given their initial keys and adjacency.
+//
+// Refinement alone can leave atoms tied that are not equivalent, as
+// in cage molecules such as cuneane, all of whose atoms look alike to
+// their neighbours.  Hence, when ties remain, each atom of the first
+// tied class is individualised in turn - given a rank of its own - and
+// the ranking is refined again, recursively, until no ties remain.
+// Of the rankings so reached, the one whose labelled adjacency - its
+// certificate - is the least is canonical.
+//
+// Two rankings with equal certificates reveal an automorphism of the
+// molecule: a permutation of its atoms that preserves their keys and
+// bonds.  Automorphisms prune the search, in the manner of McKay's
+// canonical labelling: siblings that some known automorphism maps onto
+// one another lead to equal certificates, and so only one of them is
+// explored.  The orbits of the automorphisms found are the classes of
+// equivalent atoms.
+type _RankSearch struct {
+	adj [][]_RankNbr // Neighbours of the atoms, as seen by the ranking.
+
+	first     []int // First ranking reached.
+	firstCert []int // Certificate of the first ranking.
+	firstPath []int // Atoms individualised to reach the first ranking.
+
+	best     []int // Ranking with the least certificate.
+	bestCert []int // Certificate of the best ranking.
+	bestPath []int // Atoms individualised to reach the best ranking.
+
+	autos [][]int // Automorphisms found, as permutations of atom indices.
+}
+
+// newRankSearch answers a completed search for the canonical ranking
+// of the atoms with the given initial keys and adjacency.  Higher keys
+// receive lower ranks.
+func newRankSearch(keys [][]int, adj [][]_RankNbr) *_RankSearch {
+	s := &_RankSearch{adj: adj}
+	ranks, c := ranksFromKeys(keys, true)
+	s.search(ranks, c, nil)
+	return s
+}
+
+// search refines the given ranking, reached by individualising the
+// atoms in the given path, and explores the rankings that follow from
+// it.
+//
+// Answers the depth to which the search should return: that of this
+// node, once its subtree has been explored, or that of an ancestor,
+// when the rest of the ancestor's subtree is known to repeat what has
+// already been explored.
+func (s *_RankSearch) search(ranks []int, c int, path []int) int {
+	depth := len(path)
+	ranks, c = refineRanks(ranks, c, s.adj)
+	if c == len(ranks) {
+		return s.leaf(ranks, path)
+	}
+
+	tied := firstTiedRank(ranks, c)
+	tried := make([]int, 0, 4)
+	for i, r := range ranks {
+		if r != tied || s.isEquivalentToAny(i, tried, path) {
+			continue
+		}
+		tried = append(tried, i)
+
+		child := make([]int, len(ranks))
+		for j, rj := range ranks {
+			child[j] = 2 * rj
+		}
+		child[i]--
+		cranks, cc := ranksFromKeys(rankKeys(child), false)
+
+		cpath := make([]int, depth+1)
+		copy(cpath, path)
+		cpath[depth] = i
+		if back := s.search(cranks, cc, cpath); back < depth {
+			return back
+		}
+	}
+
+	return depth
+}
+
+// leaf records the given ranking, which has no ties, and was reached
+// by individualising the atoms in the given path.  Answers the depth
+// to which the search should return.  See `search`.
+func (s *_RankSearch) leaf(ranks []int, path []int) int {
+	cert := s.certificate(ranks)
+	if s.first == nil {
+		s.first, s.firstCert, s.firstPath = ranks, cert, path
+		s.best, s.bestCert, s.bestPath = ranks, cert, path
+		return len(path)
+	}
+
+	// The subtree that led to an equal ranking is already explored;
+	// its image under the automorphism holds nothing new.
+	if compareKeys(cert, s.firstCert) == 0 {
+		s.addAutomorphism(s.first, ranks)
+		return commonPrefixLen(path, s.firstPath)
+	}
+	switch c := compareKeys(cert, s.bestCert); {
+	case c < 0:
+		s.best, s.bestCert, s.bestPath = ranks, cert, path
+	case c == 0:
+		s.addAutomorphism(s.best, ranks)
+		return commonPrefixLen(path, s.bestPath)
+	}
+
+	return len(path)
+}
+
+// certificate answers the adjacency of the atoms, labelled with the
+// given ranks, which have no ties.  For each rank in turn, it lists
+// the number of neighbours of its atom, followed by their ranks and
+// bond orders, in ascending order.
+func (s *_RankSearch) certificate(ranks []int) []int {
+	byRank := make([]int, len(ranks))
+	for i, r := range ranks {
+		byRank[r-1] = i
+	}
+
+	cert := make([]int, 0, 4*len(ranks))
+	for _, i := range byRank {
+		k := make([]int, 0, len(s.adj[i]))
+		for _, nbr := range s.adj[i] {
+			k = append(k, 8*ranks[nbr.idx]+nbr.order)
+		}
+		sort.Ints(k)
+		cert = append(cert, len(k))
+		cert = append(cert, k...)
+	}
+
+	return cert
+}
+
+// addAutomorphism records the automorphism that maps each atom ranked
+// by the first given ranking to the atom of the same rank in the
+// second.
+func (s *_RankSearch) addAutomorphism(from, to []int) {
+	byRank := make([]int, len(to))
+	for j, r := range to {
+		byRank[r-1] = j
+	}
+
+	perm := make([]int, len(from))
+	for i, r := range from {
+		perm[i] = byRank[r-1]
+	}
+	s.autos = append(s.autos, perm)
+}
+
+// isEquivalentToAny answers if an automorphism found so far, that
+// fixes every atom in the given path, maps the given atom onto one of
+// the given others.
+func (s *_RankSearch) isEquivalentToAny(i int, others []int, path []int) bool {
+	if len(others) == 0 {
+		return false
+	}
+
+	uf := newUnionFind(len(s.adj))
+	for _, perm := range s.autos {
+		fixes := true
+		for _, p := range path {
+			if perm[p] != p {
+				fixes = false
+				break
+			}
+		}
+		if fixes {
+			uf.unionPerm(perm)
+		}
+	}
+
+	for _, o := range others {
+		if uf.find(i) == uf.find(o) {
+			return true
+		}
+	}
+	return false
+}
+
+// orbitRanks answers dense ranks, starting at `1`, of the orbits of
+// the atoms under the automorphisms found, together with the number of
+// orbits.  Atoms share a rank exactly when they are equivalent.
+// Orbits are ranked in the order of the canonical ranks of their
+// atoms.
+func (s *_RankSearch) orbitRanks() ([]int, int) {
+	n := len(s.adj)
+	uf := newUnionFind(n)
+	for _, perm := range s.autos {
+		uf.unionPerm(perm)
+	}
+
+	// The least canonical rank in each orbit identifies it.
+	least := make([]int, n)
+	for i := range least {
+		least[i] = n + 1
+	}
+	for i, r := range s.best {
+		if root := uf.find(i); r < least[root] {
+			least[root] = r
+		}
+	}
+
+	keys := make([][]int, n)
+	for i := range keys {
+		keys[i] = []int{least[uf.find(i)]}
+	}
+	return ranksFromKeys(keys, false)
+}
+
+// firstTiedRank answers the lowest of the given ranks that is shared
+// by more than one atom.  The ranks are dense, from `1` to `c`.
+func firstTiedRank(ranks []int, c int) int {
+	counts := make([]int, c+1)
+	for _, r := range ranks {
+		counts[r]++
+	}
+	for r := 1; r <= c; r++ {
+		if counts[r] > 1 {
+			return r
+		}
+	}
+
+	return 0
+}
+
+// commonPrefixLen answers the length of the longest common prefix of
+// the given paths.
+func commonPrefixLen(p1, p2 []int) int {
+	n := 0
+	for n < len(p1) && n < len(p2) && p1[n] == p2[n] {
+		n++
+	}
+	return n
+}
+
+// _UnionFind is a disjoint-set forest over the indices of atoms.
+type _UnionFind []int
+
+func newUnionFind(n int) _UnionFind {
+	uf := make(_UnionFind, n)
+	for i := range uf {
+		uf[i] = i
+	}
+	return uf
+}
+
+// find answers the representative of the set of the given index.
+func (uf _UnionFind) find(i int) int {
+	for uf[i] != i {
+		uf[i] = uf[uf[i]]
+		i = uf[i]
+	}
+	return i
+}
+
+// unionPerm merges the set of each index with that of its image under
+// the given permutation.
+func (uf _UnionFind) unionPerm(perm []int) {
+	for i, j := range perm {
+		ri, rj := uf.find(i), uf.find(j)
+		if ri != rj {
+			uf[rj] = ri
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package molecule
+
+import (
+	"sort"
+)
+
+// Tiers of likely reactivity of atoms, in ascending order.  See
+// `ReactionCentres`.
+const (
+	reactivityNone = iota
+	reactivityAdjacent
+	reactivityFunctional
+	reactivityLeaving
+	reactivitySubstituted
+)
+
+// ReactionCentres answers the input IDs of the atoms of this molecule
+// that are likely to participate in a reaction, most likely first.
+//
+// The atoms are ranked in the following tiers.
+//
+//   - Carbon atoms bearing functional groups, such as carbonyl and
+//     carboxyl carbons.  These are the usual sites of substitution.
+//   - Leaving group atoms: hetero atoms singly-bonded to such carbon
+//     atoms, and terminal hetero atoms.  The alkoxy oxygen of an ester,
+//     the hydroxyl of an alcohol and the halogen of a halide are such.
+//   - Other functional atoms - hetero atoms and unsaturated carbon
+//     atoms - and carbon atoms having enolic hydrogen atoms.
+//   - Atoms adjacent to any of the above.
+//
+// Within a tier, carbon atoms bearing more important functional groups
+// come first.  Remaining ties are broken by input ID.  Thus, for an
+// ester, the carbonyl carbon and the alkoxy oxygen are the top two
+// centres.  Atoms in none of the tiers are not answered.
+//
+// This molecule should have been normalised.  Answers `nil` otherwise.
+func (m *Molecule) ReactionCentres() []uint16 {
+	if !m.isNormalised {
+		return nil
+	}
+
+	tiers := make(map[uint16]int, len(m.atoms))
+	for _, a := range m.atoms {
+		tiers[a.iId] = a.reactivityTier()
+	}
+	for _, a := range m.atoms {
+		if tiers[a.iId] != reactivityNone {
+			continue
+		}
+		for _, nid := range a.distinctNeighbours() {
+			if tiers[nid] > reactivityAdjacent {
+				tiers[a.iId] = reactivityAdjacent
+				break
+			}
+		}
+	}
+
+	rcs := make([]_ReactionCentre, 0, len(m.atoms))
+	for _, a := range m.atoms {
+		if t := tiers[a.iId]; t != reactivityNone {
+			rcs = append(rcs, _ReactionCentre{a.iId, t, a.functionalGroup()})
+		}
+	}
+	sort.Sort(_ReactionCentres(rcs))
+
+	ids := make([]uint16, len(rcs))
+	for i, rc := range rcs {
+		ids[i] = rc.iId
+	}
+	return ids
+}
+
+// _ReactionCentre holds the ranking criteria of an atom.  See
+// `ReactionCentres`.
+type _ReactionCentre struct {
+	iId     uint16 // Input ID of the atom.
+	tier    int    // Tier of likely reactivity.
+	feature uint16 // Primary feature of the atom; `0` if none.
+}
+
+// _ReactionCentres orders atoms by descending likely reactivity.
+type _ReactionCentres []_ReactionCentre
+
+func (rcs _ReactionCentres) Len() int {
+	return len(rcs)
+}
+
+func (rcs _ReactionCentres) Swap(i, j int) {
+	rcs[i], rcs[j] = rcs[j], rcs[i]
+}
+
+func (rcs _ReactionCentres) Less(i, j int) bool {
+	ri, rj := rcs[i], rcs[j]
+	if ri.tier != rj.tier {
+		return ri.tier > rj.tier
+	}
+	if ri.feature != rj.feature {
+		// Lower kinds are more important; no feature is the least.
+		return rj.feature == 0 || (ri.feature != 0 && ri.feature < rj.feature)
+	}
+
+	return ri.iId < rj.iId
+}
+
+// reactivityTier answers the tier of likely reactivity of this atom,
+// ignoring its neighbours' tiers.  See `ReactionCentres`.
+func (a *_Atom) reactivityTier() int {
+	if a.atNum == 6 && a.featureCount() > 0 {
+		return reactivitySubstituted
+	}
+
+	if a.atNum != 6 {
+		if a.isAtomicLeavingGroup() && a.singleBondCount == 1 {
+			return reactivityLeaving
+		}
+
+		mol := a.mol
+		for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+			b := mol.bondWithId(uint16(bid))
+			if b.order() != 1 || b.isAro {
+				continue
+			}
+			if oa := mol.atomWithIid(b.otherAtomIid(a.iId)); oa.atNum == 6 && oa.featureCount() > 0 {
+				return reactivityLeaving
+			}
+		}
+	}
+
+	if a.isFunctional() || a.enolicHydrogenCount() > 0 {
+		return reactivityFunctional
+	}
+
+	return reactivityNone
+}
@@ -0,0 +1,118 @@
+package molecule
+
+import (
+	"fmt"
+	"sort"
+
+	bits "github.com/willf/bitset"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// CanonicalAtomOrder answers the input IDs of the atoms of this
+// molecule, in the order of their normalised IDs.  The order does not
+// depend on the order in which the atoms were input.
+//
+// Answers `nil` if this molecule has not been normalised yet.
+func (m *Molecule) CanonicalAtomOrder() []uint16 {
+	if !m.isNormalised {
+		return nil
+	}
+
+	ids := make([]uint16, len(m.atoms))
+	for _, a := range m.atoms {
+		ids[a.nId-1] = a.iId
+	}
+
+	return ids
+}
+
+// RenumberCanonically changes the input ID of each atom of this
+// molecule to its normalised ID, and holds the atoms in that order.
+// Bonds, rings and ring systems are updated alongside.  Bonds are held
+// in the order of the new IDs of their atoms, and - unless they carry
+// a wedge, whose direction matters - run from the lower ID to the
+// higher; their own IDs are left unchanged.  Thereafter, output that
+// lists atoms and bonds in order - such as a molfile - is the same for
+// all inputs of the same structure.
+//
+// Normalised IDs and the other computed state are unaffected; this
+// molecule remains normalised.  Answers an error if this molecule has
+// not been normalised yet.
+//
+// Since normalisation freezes a molecule, this method works on frozen
+// molecules: it unfreezes this molecule for the duration, and freezes
+// it again.  See `Freeze`.
+func (m *Molecule) RenumberCanonically() error {
+	wasFrozen := m.frozen
+	m.Unfreeze()
+
+	err := m.renumberCanonically()
+	if wasFrozen {
+		m.Freeze()
+	}
+	return err
+}
+
+// renumberCanonically renumbers the atoms of this molecule, without
+// regard to its being frozen.  See `RenumberCanonically`.
+func (m *Molecule) renumberCanonically() error {
+	if !m.isNormalised {
+		return fmt.Errorf("Molecule %d has not been normalised.", m.id)
+	}
+
+	newIds := make(map[uint16]uint16, len(m.atoms))
+	for _, a := range m.atoms {
+		newIds[a.iId] = a.nId
+	}
+	remap := func(bs *bits.BitSet) *bits.BitSet {
+		nbs := bits.New(bs.Len())
+		for id, ok := bs.NextSet(0); ok; id, ok = bs.NextSet(id + 1) {
+			nbs.Set(uint(newIds[uint16(id)]))
+		}
+		return nbs
+	}
+
+	atoms := make([]*_Atom, len(m.atoms))
+	for _, a := range m.atoms {
+		a.iId = a.nId
+		for i, nid := range a.nbrs {
+			a.nbrs[i] = newIds[nid]
+		}
+		atoms[a.nId-1] = a
+	}
+	cbs := make([]_CanonicalBond, 0, len(m.bonds))
+	for _, b := range m.bonds {
+		b.a1, b.a2 = newIds[b.a1], newIds[b.a2]
+		if b.a1 > b.a2 && b.bStereo == cmn.BondStereoNone {
+			b.a1, b.a2 = b.a2, b.a1
+		}
+		if b.a1 < b.a2 {
+			cbs = append(cbs, _CanonicalBond{b.a1, b.a2, b})
+		} else {
+			cbs = append(cbs, _CanonicalBond{b.a2, b.a1, b})
+		}
+	}
+	sort.Sort(_CanonicalBonds(cbs))
+	for i, cb := range cbs {
+		m.bonds[i] = cb.b
+	}
+	for _, r := range m.rings {
+		for i, aid := range r.atoms {
+			r.atoms[i] = newIds[aid]
+		}
+		r.atomBitSet = remap(r.atomBitSet)
+	}
+	for _, rs := range m.ringSystems {
+		rs.atomBitSet = remap(rs.atomBitSet)
+	}
+
+	m.atoms = atoms
+	m.atomsByIid = make(map[uint16]*_Atom, len(atoms))
+	for _, a := range atoms {
+		m.atomsByIid[a.iId] = a
+	}
+	m.nextAtomIid = uint16(len(atoms) + 1)
+
+	return nil
+}
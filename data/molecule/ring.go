@@ -3,6 +3,8 @@ package molecule
 import (
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 
 	bits "github.com/willf/bitset"
 
@@ -39,7 +41,7 @@ type _Ring struct {
 }
 
 // newRing creates and initialises a new ring.
-func newRing(mol *Molecule, id uint8) {
+func newRing(mol *Molecule, id uint8) *_Ring {
 	r := new(_Ring)
 	r.mol = mol
 	r.id = id
@@ -50,6 +52,35 @@ func newRing(mol *Molecule, id uint8) {
 
 	r.atomBitSet = bits.New(cmn.ListSizeSmall)
 	r.bondBitSet = bits.New(cmn.ListSizeSmall)
+
+	return r
+}
+
+// String answers a one-line, human-readable description of the state
+// of this ring, for debugging.  Atoms are listed by their input IDs, in
+// ring order.
+//
+//	R#2 size=6 atoms={1,2,3,4,5,6} aromatic=true hetero=false system=1 nbrs={3}
+func (r *_Ring) String() string {
+	var sb strings.Builder
+	sb.Grow(96)
+
+	sb.WriteString("R#")
+	sb.WriteString(strconv.Itoa(int(r.id)))
+	sb.WriteString(" size=")
+	sb.WriteString(strconv.Itoa(r.size()))
+	sb.WriteString(" atoms=")
+	writeIds(&sb, len(r.atoms), func(i int) int { return int(r.atoms[i]) })
+	sb.WriteString(" aromatic=")
+	sb.WriteString(strconv.FormatBool(r.isAro))
+	sb.WriteString(" hetero=")
+	sb.WriteString(strconv.FormatBool(r.isHetAro))
+	sb.WriteString(" system=")
+	sb.WriteString(strconv.Itoa(int(r.rsId)))
+	sb.WriteString(" nbrs=")
+	writeIds(&sb, len(r.nbrs), func(i int) int { return int(r.nbrs[i]) })
+
+	return sb.String()
 }
 
 // size answers the size of this ring.  It is equivalently the number
@@ -110,6 +141,7 @@ func (r *_Ring) addAtom(aid uint16) error {
 	size := len(r.atoms)
 	if size == 0 {
 		r.atoms = append(r.atoms, aid)
+		r.atomBitSet.Set(uint(aid))
 		return nil
 	}
 
@@ -148,6 +180,7 @@ func (r *_Ring) complete() error {
 	}
 
 	r.bonds = append(r.bonds, b.id)
+	r.bondBitSet.Set(uint(b.id))
 
 	r.isComplete = true
 	return nil
@@ -199,7 +232,12 @@ func (r *_Ring) normalise() error {
 	}
 
 	// Rotate the ring so that the atom at `idx` becomes the first.
+	// The bonds are rotated alongside, so that the bond at index `i`
+	// continues to bind the atoms at `i` and `i+1`.
 	r.atoms = append(r.atoms[idx:], r.atoms[:idx]...)
+	if len(r.bonds) == l {
+		r.bonds = append(r.bonds[idx:], r.bonds[:idx]...)
+	}
 	return nil
 }
 
@@ -233,8 +271,9 @@ func (r *_Ring) determineAromaticity() {
 	mol := r.mol
 
 	// First, we apply Huckel's rule.
+	// Charged ring atoms - as in tropylium, cyclopentadienide and
+	// pyridinium - are accounted for in their pi-electron counts.
 	if (n-2)%4 != 0 {
-		// TODO(js): Take exceptions into account.
 		return
 	}
 
@@ -250,6 +289,14 @@ func (r *_Ring) determineAromaticity() {
 	// TODO(js): Take exceptions into account.
 
 	// If we have come this far, this is an aromatic ring.
+	r.markAromatic()
+}
+
+// markAromatic marks this ring, and its atoms and bonds, as being
+// aromatic.
+func (r *_Ring) markAromatic() {
+	mol := r.mol
+
 	r.isAro = true
 
 	for _, aiid := range r.atoms {
@@ -265,6 +312,21 @@ func (r *_Ring) determineAromaticity() {
 	}
 }
 
+// isFusedTo answers if this ring shares at least one atom with the
+// ring with the given ID.
+//
+// Ring systems must have been detected, before this method is
+// invoked.
+func (r *_Ring) isFusedTo(other uint8) bool {
+	for _, nid := range r.nbrs {
+		if nid == other {
+			return true
+		}
+	}
+
+	return false
+}
+
 // commonAtoms answers a list of the atoms that participate in both
 // this ring and the given ring.  The representation is a bitset.
 func (r *_Ring) commonAtoms(other *_Ring) *bits.BitSet {
@@ -277,6 +339,43 @@ func (r *_Ring) commonBonds(other *_Ring) *bits.BitSet {
 	return r.bondBitSet.Intersection(other.bondBitSet)
 }
 
+// isFusedAromatic answers if this ring is aromatic, and shares at
+// least one bond with another aromatic ring - necessarily of the same
+// ring system - as in naphthalene.
+//
+// Ring systems and aromaticity must have been determined, before this
+// method is invoked.
+func (r *_Ring) isFusedAromatic() bool {
+	if !r.isAro {
+		return false
+	}
+
+	mol := r.mol
+	for _, nid := range r.nbrs {
+		nr := mol.ringWithId(nid)
+		if nr.isAro && r.commonBonds(nr).Count() > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// heteroProfile answers the number of hetero atoms in this ring,
+// keyed by their atomic numbers.  Carbon atoms are not counted.
+func (r *_Ring) heteroProfile() map[uint8]int {
+	mol := r.mol
+
+	p := make(map[uint8]int)
+	for _, aid := range r.atoms {
+		if a := mol.atomWithIid(aid); a.atNum != 6 {
+			p[a.atNum]++
+		}
+	}
+
+	return p
+}
+
 // distanceBetweenAtoms answers the shorter distance in the ring,
 // between the two given atoms.
 func (r *_Ring) distanceBetweenAtoms(aid1, aid2 uint16) (int, error) {
@@ -450,3 +549,120 @@ func (r *_Ring) hasAdjacentCHCH() (bool, int) {
 		return a.isSaturatedC() && a.hCount > 0
 	})
 }
+
+// Ring is a read-only view of a ring in a molecule.
+//
+// It exposes the properties of the ring, without allowing them to be
+// modified.  Obtain one using `Molecule.RingWithId`.
+type Ring struct {
+	r *_Ring
+}
+
+// RingCount answers the number of rings detected in this molecule.
+// Ring IDs run from `1` through this number.  Stale rings are
+// perceived afresh; `0` is answered if that fails.
+func (m *Molecule) RingCount() int {
+	if m.refresh(stageRings) != nil {
+		return 0
+	}
+
+	return len(m.rings)
+}
+
+// RingWithId answers a view of the ring with the given ID, if one
+// such exists.  Stale rings are perceived afresh, first.
+func (m *Molecule) RingWithId(id uint8) (Ring, bool) {
+	if m.refresh(stageRings) != nil {
+		return Ring{}, false
+	}
+
+	r := m.ringWithId(id)
+	if r == nil {
+		return Ring{}, false
+	}
+
+	return Ring{r}, true
+}
+
+// Id answers the ID of this ring.
+func (ring Ring) Id() uint8 {
+	return ring.r.id
+}
+
+// Size answers the number of atoms in this ring.
+func (ring Ring) Size() int {
+	return ring.r.size()
+}
+
+// Atoms answers the input IDs of the atoms of this ring, in ring
+// order.
+func (ring Ring) Atoms() []uint16 {
+	ids := make([]uint16, len(ring.r.atoms))
+	copy(ids, ring.r.atoms)
+	return ids
+}
+
+// RingSystemId answers the ID of the ring system this ring belongs to.
+func (ring Ring) RingSystemId() uint8 {
+	return ring.r.rsId
+}
+
+// IsAromatic answers if this ring is aromatic.
+func (ring Ring) IsAromatic() bool {
+	return ring.r.isAro
+}
+
+// Neighbours answers the IDs of the rings that share at least one atom
+// with this ring.
+func (ring Ring) Neighbours() []uint8 {
+	ids := make([]uint8, len(ring.r.nbrs))
+	copy(ids, ring.r.nbrs)
+	return ids
+}
+
+// IsFusedTo answers if this ring shares at least one atom with the
+// ring with the given ID.
+func (ring Ring) IsFusedTo(other uint8) bool {
+	return ring.r.isFusedTo(other)
+}
+
+// RingInfo summarises the size and the hetero atom composition of a
+// ring.  See `Molecule.HeteroaromaticRings`.
+type RingInfo struct {
+	Id          uint8         // ID of the ring.
+	Size        int           // Number of atoms in the ring.
+	Heteroatoms map[uint8]int // Hetero atom counts, by atomic number.
+
+	IsFiveMembered bool // Is this a five-membered ring, as in pyrrole?
+	IsSixMembered  bool // Is this a six-membered ring, as in pyridine?
+}
+
+// HeteroaromaticRings answers a summary of each aromatic ring of this
+// molecule that has at least one hetero atom, in the order of ring
+// IDs.
+//
+// Stale rings and aromaticity are determined afresh; no ring is
+// answered if that fails.
+func (m *Molecule) HeteroaromaticRings() []RingInfo {
+	if m.refresh(stageAromaticity) != nil {
+		return nil
+	}
+
+	ris := make([]RingInfo, 0, len(m.rings))
+	for _, r := range m.rings {
+		if !r.isHeteroAromatic() {
+			continue
+		}
+
+		n := r.size()
+		ris = append(ris, RingInfo{
+			Id:             r.id,
+			Size:           n,
+			Heteroatoms:    r.heteroProfile(),
+			IsFiveMembered: n == 5,
+			IsSixMembered:  n == 6,
+		})
+	}
+
+	return ris
+}
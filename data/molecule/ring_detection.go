@@ -0,0 +1,331 @@
+package molecule
+
+import (
+	"fmt"
+	"sort"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+	bits "github.com/willf/bitset"
+)
+
+// _CandidateRing is a cycle found during ring detection, which may or
+// may not end up as a ring of the molecule.
+type _CandidateRing struct {
+	atoms   []uint16     // Input IDs of the atoms, in ring order.
+	sorted  []uint16     // Input IDs of the atoms, in increasing order.
+	bondSet *bits.BitSet // IDs of the bonds in this cycle.
+}
+
+// _CandidateRings sorts candidate rings in ascending order of size.
+// Rings of equal size are ordered on the input IDs of their atoms, so
+// that the selection is deterministic.
+type _CandidateRings []*_CandidateRing
+
+func (cs _CandidateRings) Len() int {
+	return len(cs)
+}
+
+func (cs _CandidateRings) Swap(i, j int) {
+	cs[i], cs[j] = cs[j], cs[i]
+}
+
+func (cs _CandidateRings) Less(i, j int) bool {
+	ci, cj := cs[i], cs[j]
+	if len(ci.atoms) != len(cj.atoms) {
+		return len(ci.atoms) < len(cj.atoms)
+	}
+	for k := range ci.sorted {
+		if ci.sorted[k] != cj.sorted[k] {
+			return ci.sorted[k] < cj.sorted[k]
+		}
+	}
+	return false
+}
+
+// PerceiveRings detects the rings in this molecule, and registers
+// them with their atoms and bonds.  Any previously-detected rings and
+// ring systems are discarded.  A molecule may have at most
+// `cmn.MaxRingCount` rings; an error is answered, and no rings are
+// retained, if it has more.
+//
+// Candidate cycles are generated by combining pairs of shortest paths
+// from each atom, and the smallest set of smallest rings is then
+// selected from them in ascending order of size.  A candidate is
+// selected only if its set of bonds is independent of those of the
+// rings already selected.
+func (m *Molecule) PerceiveRings() error {
+	m.clearRings()
+
+	cands := m.candidateRings()
+	if len(cands) == 0 {
+		return nil // Acyclic.
+	}
+	sort.Sort(_CandidateRings(cands))
+
+	basis := make(map[uint]*bits.BitSet)
+	for _, c := range cands {
+		if !addToRingBasis(basis, c.bondSet) {
+			continue
+		}
+
+		if int(m.nextRingId) >= cmn.MaxRingCount {
+			m.clearRings()
+			return fmt.Errorf("Molecule %d has more than %d rings.", m.id, cmn.MaxRingCount)
+		}
+		m.nextRingId++
+		r := newRing(m, m.nextRingId)
+		for _, aid := range c.atoms {
+			if err := r.addAtom(aid); err != nil {
+				return err
+			}
+		}
+		if err := r.complete(); err != nil {
+			return err
+		}
+
+		for _, aid := range r.atoms {
+			m.atomWithIid(aid).addRing(r)
+		}
+		for _, bid := range r.bonds {
+			m.bondWithId(bid).addRing(r.id)
+		}
+		m.rings = append(m.rings, r)
+	}
+
+	return nil
+}
+
+// clearRings discards all rings and ring systems of this molecule,
+// together with the information derived from them.
+func (m *Molecule) clearRings() {
+	for _, a := range m.atoms {
+		a.rings.ClearAll()
+		a.isInAroRing = false
+	}
+	for _, b := range m.bonds {
+		b.rings = b.rings[:0]
+		b.isAro = false
+	}
+
+	m.rings = m.rings[:0]
+	m.ringSystems = m.ringSystems[:0]
+	m.nextRingId = 0
+	m.nextRingSystemId = 0
+}
+
+// candidateRings answers the set of unique cycles formed by joining
+// the shortest paths from each atom to both the atoms of a bond, when
+// the two paths have only their starting atom in common.
+//
+// This set is known to include a minimum cycle basis of the molecular
+// graph.
+func (m *Molecule) candidateRings() []*_CandidateRing {
+	cands := make([]*_CandidateRing, 0, len(m.bonds))
+	seen := make(map[string]bool)
+
+	for _, root := range m.atoms {
+		parents := m.shortestPathTree(root.iId)
+
+		for _, b := range m.bonds {
+			p1, ok1 := parents[b.a1]
+			p2, ok2 := parents[b.a2]
+			if !ok1 || !ok2 {
+				continue // Different component.
+			}
+			if p1 == b.a2 || p2 == b.a1 {
+				continue // Bond is part of the tree.
+			}
+
+			path1 := pathFromRoot(parents, b.a1)
+			path2 := pathFromRoot(parents, b.a2)
+			if !disjointExceptRoot(path1, path2) {
+				continue
+			}
+
+			atoms := make([]uint16, 0, len(path1)+len(path2)-1)
+			atoms = append(atoms, path1...)
+			for i := len(path2) - 1; i > 0; i-- {
+				atoms = append(atoms, path2[i])
+			}
+
+			c := m.newCandidateRing(atoms)
+			key := c.bondSet.String()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			cands = append(cands, c)
+		}
+	}
+
+	return cands
+}
+
+// newCandidateRing creates a candidate ring from the given atoms, which
+// are expected to form a cycle in the given order.
+func (m *Molecule) newCandidateRing(atoms []uint16) *_CandidateRing {
+	c := &_CandidateRing{atoms: atoms}
+	c.bondSet = bits.New(uint(len(m.bonds) + 1))
+	c.sorted = make([]uint16, len(atoms))
+	copy(c.sorted, atoms)
+	sort.Sort(_Uint16s(c.sorted))
+
+	n := len(atoms)
+	for i, aid := range atoms {
+		b := m.bondBetween(aid, atoms[(i+1)%n])
+		c.bondSet.Set(uint(b.id))
+	}
+
+	return c
+}
+
+// shortestPathTree answers a breadth-first tree of the atoms
+// reachable from the given atom.  The tree maps each such atom to its
+// parent; the root maps to itself.
+func (m *Molecule) shortestPathTree(root uint16) map[uint16]uint16 {
+	parents := map[uint16]uint16{root: root}
+	queue := []uint16{root}
+	for len(queue) > 0 {
+		aid := queue[0]
+		queue = queue[1:]
+
+		a := m.atomWithIid(aid)
+		for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+			nid := m.bondWithId(uint16(bid)).otherAtomIid(aid)
+			if _, ok := parents[nid]; ok {
+				continue
+			}
+			parents[nid] = aid
+			queue = append(queue, nid)
+		}
+	}
+
+	return parents
+}
+
+// pathFromRoot answers the path from the root of the given tree to the
+// given atom, both inclusive.
+func pathFromRoot(parents map[uint16]uint16, aid uint16) []uint16 {
+	path := []uint16{aid}
+	for parents[aid] != aid {
+		aid = parents[aid]
+		path = append(path, aid)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// disjointExceptRoot answers if the two given paths, both starting at
+// the same root, have no other atom in common.
+func disjointExceptRoot(path1, path2 []uint16) bool {
+	for _, a1 := range path1[1:] {
+		for _, a2 := range path2[1:] {
+			if a1 == a2 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// addToRingBasis adds the given set of bonds to the given basis, if it
+// is linearly independent of the sets already in the basis.  The
+// basis is maintained in row-echelon form over GF(2), keyed by the
+// lowest bond ID in each set.
+//
+// Answers `true` if the given set was added; `false` otherwise.
+func addToRingBasis(basis map[uint]*bits.BitSet, bondSet *bits.BitSet) bool {
+	v := bondSet.Clone()
+	for {
+		pivot, ok := v.NextSet(0)
+		if !ok {
+			return false // Dependent.
+		}
+
+		row, ok := basis[pivot]
+		if !ok {
+			basis[pivot] = v
+			return true
+		}
+		v.InPlaceSymmetricDifference(row)
+	}
+}
+
+// PerceiveRingSystems groups the rings of this molecule into ring
+// systems.  Rings that share at least one atom belong to the same
+// ring system.  Any previously-detected ring systems are discarded.
+//
+// It also records, with each ring, the rings that it shares at least
+// one atom with, as its neighbours.
+//
+// Rings must have been detected before this method is invoked.
+func (m *Molecule) PerceiveRingSystems() error {
+	m.ringSystems = m.ringSystems[:0]
+	m.nextRingSystemId = 0
+
+	for _, r := range m.rings {
+		r.nbrs = r.nbrs[:0]
+	}
+	for i, r1 := range m.rings {
+		for _, r2 := range m.rings[i+1:] {
+			if r1.atomBitSet.IntersectionCardinality(r2.atomBitSet) > 0 {
+				r1.nbrs = append(r1.nbrs, r2.id)
+				r2.nbrs = append(r2.nbrs, r1.id)
+			}
+		}
+	}
+
+	done := make(map[uint8]bool, len(m.rings))
+	for _, r := range m.rings {
+		if done[r.id] {
+			continue
+		}
+
+		m.nextRingSystemId++
+		rs := newRingSystem(m, m.nextRingSystemId)
+
+		queue := []*_Ring{r}
+		done[r.id] = true
+		for len(queue) > 0 {
+			cur := queue[0]
+			queue = queue[1:]
+
+			if err := rs.addRing(cur); err != nil {
+				return err
+			}
+			cur.rsId = rs.id
+
+			for _, nid := range cur.nbrs {
+				if done[nid] {
+					continue
+				}
+				done[nid] = true
+				queue = append(queue, m.ringWithId(nid))
+			}
+		}
+
+		m.ringSystems = append(m.ringSystems, rs)
+	}
+
+	return nil
+}
+
+// _Uint16s attaches the methods of `sort.Interface` to `[]uint16`,
+// sorting in increasing order.
+type _Uint16s []uint16
+
+func (s _Uint16s) Len() int {
+	return len(s)
+}
+
+func (s _Uint16s) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s _Uint16s) Less(i, j int) bool {
+	return s[i] < s[j]
+}
@@ -0,0 +1,90 @@
+package molecule
+
+import (
+	"testing"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// TestPerceiveRingsFused builds perhydroheptacene - seven linearly
+// fused cyclohexane rings - and checks its rings and ring system.
+func TestPerceiveRingsFused(t *testing.T) {
+	const n = 7
+
+	// The fusion bonds run between `top[i]` and `bot[i]`; each ring
+	// closes through two further atoms.
+	mb := NewMoleculeBuilder()
+	addC := func(x, y float32) uint16 {
+		aid, err := mb.AddAtom("C", x, y, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return aid
+	}
+	addBond := func(a1, a2 uint16) {
+		if err := mb.AddBond(a1, a2, cmn.BondTypeSingle); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	top := make([]uint16, n+1)
+	bot := make([]uint16, n+1)
+	for i := range top {
+		top[i] = addC(float32(i)*2.5, 0.75)
+		bot[i] = addC(float32(i)*2.5, -0.75)
+		addBond(top[i], bot[i])
+	}
+	for i := 0; i < n; i++ {
+		x := float32(i)*2.5 + 1.25
+		up, down := addC(x, 1.5), addC(x, -1.5)
+		addBond(top[i], up)
+		addBond(up, top[i+1])
+		addBond(bot[i], down)
+		addBond(down, bot[i+1])
+	}
+
+	m, err := mb.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.discard()
+
+	if c := m.RingCount(); c != n {
+		t.Errorf("%d rings; want %d", c, n)
+	}
+	if c := m.RingSystemCount(); c != 1 {
+		t.Errorf("%d ring systems; want 1", c)
+	}
+	for _, r := range m.rings {
+		if r.size() != 6 {
+			t.Errorf("ring %d has %d atoms; want 6", r.id, r.size())
+		}
+	}
+	if f := m.Formula(); f != "C30H48" {
+		t.Errorf("formula %s; want C30H48", f)
+	}
+}
+
+func TestPerceiveRingsTooMany(t *testing.T) {
+	mb := NewMoleculeBuilder()
+	for i := 0; i <= cmn.MaxRingCount; i++ {
+		var ids [3]uint16
+		for j := range ids {
+			aid, err := mb.AddAtom("C", 0, 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids[j] = aid
+		}
+		for j := range ids {
+			if err := mb.AddBond(ids[j], ids[(j+1)%3], cmn.BondTypeSingle); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if m, err := mb.Finish(); err == nil {
+		m.discard()
+		t.Errorf("molecule with %d rings normalised", cmn.MaxRingCount+1)
+	}
+}
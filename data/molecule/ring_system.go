@@ -97,7 +97,7 @@ func (rs *_RingSystem) addRingAt(idx int, r *_Ring) error {
 	if rs.bondBitSet.Count() > 0 {
 		if rs.bondBitSet.IntersectionCardinality(r.bondBitSet) == 0 {
 			if rs.atomBitSet.IntersectionCardinality(r.atomBitSet) == 0 {
-				return fmt.Errorf("Ring %d has no bonds or atoms in common with any others in this ring system")
+				return fmt.Errorf("Ring %d has no bonds or atoms in common with any others in this ring system", r.id)
 			}
 		}
 	}
@@ -190,8 +190,9 @@ func (rs *_RingSystem) determineAromaticity() {
 	mol := rs.mol
 
 	// First, we apply Huckel's rule.
+	// Charged ring atoms - as in tropylium, cyclopentadienide and
+	// pyridinium - are accounted for in their pi-electron counts.
 	if (n-2)%4 != 0 {
-		// TODO(js): Take exceptions into account.
 		err = true
 	}
 
@@ -220,20 +221,14 @@ func (rs *_RingSystem) determineAromaticity() {
 	}
 }
 
-// markAtomsBondsAromatic marks all participating atoms and bonds are
-// being aromatic.
+// markAtomsBondsAromatic marks all participating rings, atoms and
+// bonds as being aromatic.  The rings are marked even when they are
+// not aromatic by themselves, since each is part of an aromatic
+// system.
 func (rs *_RingSystem) markAtomsBondsAromatic() {
 	mol := rs.mol
 
-	abs := rs.atomBitSet
-	for aiid, ok := abs.NextSet(0); ok; aiid, ok = abs.NextSet(aiid + 1) {
-		a := mol.atomWithIid(uint16(aiid))
-		a.isInAroRing = true
-	}
-
-	bbs := rs.bondBitSet
-	for bid, ok := bbs.NextSet(0); ok; bid, ok = bbs.NextSet(bid + 1) {
-		b := mol.bondWithId(uint16(bid))
-		b.isAro = true
+	for _, rid := range rs.rings {
+		mol.ringWithId(rid).markAromatic()
 	}
 }
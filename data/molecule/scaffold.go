@@ -0,0 +1,151 @@
+package molecule
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ScaffoldKey answers a canonical key of the framework of this
+// molecule, in the manner of Bemis and Murcko.
+//
+// The framework comprises the ring systems of this molecule, and the
+// atoms of the linkers connecting them.  A linker is a shortest path
+// between two ring systems.  All other atoms - side chains, and
+// substituents on rings and linkers - are stripped; each bond to a
+// stripped atom is replaced by as many hydrogen atoms as its order.
+// The key is the canonical key of the resulting framework.  See
+// `CanonicalKey`.
+//
+// Thus, molecules that differ only in their non-ring substituents have
+// the same key.  Answers an empty key for an acyclic molecule.
+//
+// This molecule should have been normalised.
+func (m *Molecule) ScaffoldKey() (string, error) {
+	if !m.isNormalised {
+		return "", fmt.Errorf("Molecule %d has not been normalised.", m.id)
+	}
+	if len(m.ringSystems) == 0 {
+		return "", nil
+	}
+
+	frag, err := m.framework()
+	if err != nil {
+		return "", err
+	}
+	defer frag.discard()
+
+	return frag.CanonicalKey(), nil
+}
+
+// MurckoFramework answers a new molecule comprising the framework of
+// this molecule, in the manner of Bemis and Murcko: its ring systems,
+// and the linkers connecting them.  It is what remains when atoms not
+// in rings, and having a single neighbour, are repeatedly removed.
+// Each bond to a removed atom is replaced by as many hydrogen atoms as
+// its order.  Bonds within the framework retain their orders.
+//
+// The framework is normalised; its canonical key is that answered by
+// `ScaffoldKey`.  An acyclic molecule answers an empty framework.
+//
+// This molecule should have been normalised.
+func (m *Molecule) MurckoFramework() (*Molecule, error) {
+	if !m.isNormalised {
+		return nil, fmt.Errorf("Molecule %d has not been normalised.", m.id)
+	}
+
+	return m.framework()
+}
+
+// framework answers a new, normalised molecule comprising the ring
+// systems of this molecule, and the linkers connecting them.  See
+// `ScaffoldKey`.
+//
+// Distances between atoms must have been computed, before this method
+// is invoked.
+func (m *Molecule) framework() (*Molecule, error) {
+	keep := make(map[uint16]bool, len(m.atoms))
+	for _, a := range m.atoms {
+		if a.isCyclic() {
+			keep[a.iId] = true
+		}
+	}
+	for i, rs1 := range m.ringSystems {
+		for _, rs2 := range m.ringSystems[i+1:] {
+			for _, aid := range m.linkerBetween(rs1, rs2) {
+				keep[aid] = true
+			}
+		}
+	}
+
+	ids := make([]uint16, 0, len(keep))
+	for aid := range keep {
+		ids = append(ids, aid)
+	}
+	sort.Sort(_Uint16s(ids))
+
+	frag, err := m.fragment(ids)
+	if err != nil {
+		return nil, err
+	}
+	frag.Unfreeze()
+
+	// Bonds to stripped atoms are replaced by hydrogen atoms.  The
+	// fragment numbers its atoms in the order of the given IDs.
+	for i, aid := range ids {
+		a := m.atomWithIid(aid)
+		fa := frag.atomWithIid(uint16(i + 1))
+		for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+			b := m.bondWithId(uint16(bid))
+			if !keep[b.otherAtomIid(aid)] {
+				fa.hCount += uint8(b.order())
+			}
+		}
+	}
+	if err := frag.Normalise(); err != nil {
+		frag.discard()
+		return nil, err
+	}
+
+	return frag, nil
+}
+
+// linkerBetween answers the input IDs of the atoms on a shortest path
+// between the two given ring systems, excluding its end atoms.
+// Answers an empty list if the ring systems are not connected, or are
+// directly bonded to each other.
+//
+// Distances between atoms must have been computed, before this method
+// is invoked.
+func (m *Molecule) linkerBetween(rs1, rs2 *_RingSystem) []uint16 {
+	var from, to *_Atom
+	min := -1
+	for aid1, ok := rs1.atomBitSet.NextSet(0); ok; aid1, ok = rs1.atomBitSet.NextSet(aid1 + 1) {
+		a1 := m.atomWithIid(uint16(aid1))
+		for aid2, ok := rs2.atomBitSet.NextSet(0); ok; aid2, ok = rs2.atomBitSet.NextSet(aid2 + 1) {
+			a2 := m.atomWithIid(uint16(aid2))
+			if d := m.distanceBetween(a1, a2); d > 0 && (min < 0 || d < min) {
+				from, to, min = a1, a2, d
+			}
+		}
+	}
+	if min < 2 {
+		return nil
+	}
+
+	// Walk from one end to the other, always stepping to a neighbour
+	// closer to the destination.
+	ids := make([]uint16, 0, min-1)
+	cur := from
+	for d := min; d > 1; d-- {
+		for bid, ok := cur.bonds.NextSet(0); ok; bid, ok = cur.bonds.NextSet(bid + 1) {
+			oa := m.atomWithIid(m.bondWithId(uint16(bid)).otherAtomIid(cur.iId))
+			if m.distanceBetween(oa, to) == d-1 {
+				cur = oa
+				break
+			}
+		}
+		ids = append(ids, cur.iId)
+	}
+
+	return ids
+}
@@ -0,0 +1,114 @@
+package molecule
+
+import (
+	"testing"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// buildBenzodiazepine builds a 5-aryl-1,4-benzodiazepine in the manner
+// of diazepam.  The given options choose the substituents on the
+// framework: the halogen at position 7, a methyl group on N-1 and a
+// carbonyl oxygen at C-2.  Unless `aromatic` is set, the 5-aryl group
+// is replaced by a cyclohexyl group.
+func buildBenzodiazepine(t *testing.T, halogen string, methyl, carbonyl, aromatic bool) *Molecule {
+	syms := []string{"N", "C", "C", "N", "C", "C", "C", "C", "C", "C", "C",
+		"C", "C", "C", "C", "C", "C", halogen}
+	// Order of alternate bonds of the 5-aryl ring.
+	alt := 2
+	if !aromatic {
+		alt = 1
+	}
+	bonds := testBonds([][3]int{
+		// Diazepine ring.
+		{1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {4, 5, 2}, {5, 6, 1}, {11, 1, 1},
+		// Fused benzo ring, with the halogen at C-7.
+		{6, 7, 2}, {7, 8, 1}, {8, 9, 2}, {9, 10, 1}, {10, 11, 2}, {11, 6, 1},
+		{8, 18, 1},
+		// 5-Aryl group.
+		{5, 12, 1},
+		{12, 13, alt}, {13, 14, 1}, {14, 15, alt}, {15, 16, 1}, {16, 17, alt}, {17, 12, 1},
+	})
+	if methyl {
+		syms = append(syms, "C")
+		bonds = append(bonds, _TestBond{1, len(syms), cmn.BondTypeSingle})
+	}
+	if carbonyl {
+		syms = append(syms, "O")
+		bonds = append(bonds, _TestBond{2, len(syms), cmn.BondTypeDouble})
+	}
+	return buildMolecule(t, syms, bonds)
+}
+
+func TestScaffoldKeyBenzodiazepines(t *testing.T) {
+	scaffoldKey := func(m *Molecule) string {
+		defer m.discard()
+		k, err := m.ScaffoldKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+
+	diazepam := scaffoldKey(buildBenzodiazepine(t, "Cl", true, true, true))
+	if diazepam == "" {
+		t.Fatal("diazepam : empty scaffold key")
+	}
+
+	// These differ from diazepam only in substituents off the
+	// framework.
+	analogs := []struct {
+		name string
+		m    *Molecule
+	}{
+		{"nordazepam", buildBenzodiazepine(t, "Cl", false, true, true)},
+		{"medazepam", buildBenzodiazepine(t, "Cl", true, false, true)},
+		{"7-fluoro analog", buildBenzodiazepine(t, "F", true, true, true)},
+	}
+	for _, a := range analogs {
+		if k := scaffoldKey(a.m); k != diazepam {
+			t.Errorf("%s : scaffold key %s; want that of diazepam, %s", a.name, k, diazepam)
+		}
+	}
+
+	// Saturating the 5-aryl ring changes the framework.
+	if k := scaffoldKey(buildBenzodiazepine(t, "Cl", true, true, false)); k == diazepam {
+		t.Errorf("5-cyclohexyl analog : scaffold key %s equals that of diazepam", k)
+	}
+}
+
+// TestMurckoFrameworkIbuprofen checks that the framework of ibuprofen
+// is a single benzene ring: its isobutyl and propanoic acid side chains
+// are both stripped.
+func TestMurckoFrameworkIbuprofen(t *testing.T) {
+	// C1 C2(C3) C4 on the ring of C5 to C10; C11(C12) C13(=O14) O15 on
+	// C8.
+	m := buildMolecule(t,
+		[]string{"C", "C", "C", "C", "C", "C", "C", "C", "C", "C", "C", "C", "C", "O", "O"},
+		testBonds([][3]int{
+			{1, 2, 1}, {2, 3, 1}, {2, 4, 1}, {4, 5, 1},
+			{5, 6, 2}, {6, 7, 1}, {7, 8, 2}, {8, 9, 1}, {9, 10, 2}, {10, 5, 1},
+			{8, 11, 1}, {11, 12, 1}, {11, 13, 1}, {13, 14, 2}, {13, 15, 1},
+		}))
+	defer m.discard()
+	benzene := buildMolecule(t, carbons8[:6], testBonds([][3]int{
+		{1, 2, 2}, {2, 3, 1}, {3, 4, 2}, {4, 5, 1}, {5, 6, 2}, {6, 1, 1},
+	}))
+	defer benzene.discard()
+
+	f, err := m.MurckoFramework()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.discard()
+
+	if len(f.atoms) != 6 || len(f.bonds) != 6 {
+		t.Errorf("framework : %d atoms and %d bonds; want 6 and 6", len(f.atoms), len(f.bonds))
+	}
+	if n := f.AromaticAtomCount(); n != 6 {
+		t.Errorf("framework : %d aromatic atoms; want 6", n)
+	}
+	if !f.Equals(benzene) {
+		t.Errorf("framework %s; want benzene, %s", f.CanonicalKey(), benzene.CanonicalKey())
+	}
+}
@@ -0,0 +1,225 @@
+package molecule
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// Names of the SD data items that carry the supplier information of
+// a molecule.
+const (
+	SDFVendorTag           = "VENDOR"
+	SDFVendorMoleculeIdTag = "VENDOR_ID"
+)
+
+// SDFReader reads molecules from an MDL SD file.
+//
+// An SD file is a concatenation of records, each of which comprises a
+// V2000 molfile followed by an optional list of data items.  Records
+// are separated by lines containing `$$$$`.  The data items of a
+// record are loaded as the attributes of its molecule.
+type SDFReader struct {
+	// SkipMalformed, when set, makes the reader skip records that
+	// cannot be parsed, rather than abort.  The errors of the
+	// skipped records are available from `Skipped`.
+	SkipMalformed bool
+	// HydrogenPolicy determines how the hydrogen counts of atoms are
+	// inferred.  By default, atoms are filled to their valences.
+	HydrogenPolicy cmn.HydrogenPolicy
+
+	sc      *bufio.Scanner
+	lineNo  int     // Number of the last line read.
+	skipped []error // Errors of the records skipped so far.
+}
+
+// NewSDFReader answers a new reader of SD records from the given
+// input.
+func NewSDFReader(r io.Reader) *SDFReader {
+	return &SDFReader{sc: bufio.NewScanner(r)}
+}
+
+// ReadSDF reads all the records in the given SD input, and answers
+// their molecules in input order.  It stops at the first malformed
+// record.  Use an `SDFReader` to skip such records instead.
+func ReadSDF(r io.Reader) ([]*Molecule, error) {
+	return NewSDFReader(r).ReadAll()
+}
+
+// Skipped answers the errors of the malformed records skipped so far.
+// Each error includes the line number at which the problem was found.
+func (sr *SDFReader) Skipped() []error {
+	return sr.skipped
+}
+
+// ReadAll reads all the remaining records in the input of this
+// reader, and answers their molecules in input order.
+func (sr *SDFReader) ReadAll() ([]*Molecule, error) {
+	mols := make([]*Molecule, 0, 16)
+	for {
+		m, err := sr.Next()
+		if err == io.EOF {
+			return mols, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		mols = append(mols, m)
+	}
+}
+
+// Next reads the next record in the input of this reader, and answers
+// its molecule.  Answers `io.EOF` when no more records exist.
+func (sr *SDFReader) Next() (*Molecule, error) {
+	for {
+		lines, first, err := sr.nextRecord()
+		if err != nil {
+			return nil, err
+		}
+
+		m, err := parseSDFRecord(lines, first, sr.HydrogenPolicy)
+		if err == nil {
+			return m, nil
+		}
+		if !sr.SkipMalformed {
+			return nil, err
+		}
+		sr.skipped = append(sr.skipped, err)
+	}
+}
+
+// nextRecord answers the lines of the next record in the input, up to
+// - but excluding - its `$$$$` delimiter, together with the line
+// number of its first line.  Blank trailing input is not a record.
+func (sr *SDFReader) nextRecord() ([]string, int, error) {
+	lines := make([]string, 0, 64)
+	first := sr.lineNo + 1
+	for sr.sc.Scan() {
+		sr.lineNo++
+		line := strings.TrimRight(sr.sc.Text(), "\r")
+		if strings.HasPrefix(line, "$$$$") {
+			return lines, first, nil
+		}
+		lines = append(lines, line)
+	}
+	if err := sr.sc.Err(); err != nil {
+		return nil, first, err
+	}
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			return lines, first, nil // Last record lacks `$$$$`.
+		}
+	}
+	return nil, first, io.EOF
+}
+
+// parseSDFRecord constructs the molecule described by the given lines
+// of an SD record, and loads its data items as attributes.  Hydrogen
+// counts are determined as per the given policy.
+func parseSDFRecord(lines []string, firstLine int, policy cmn.HydrogenPolicy) (*Molecule, error) {
+	m, n, err := parseMolfile(lines, firstLine, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := parseSDFData(m, lines[n:], firstLine+n); err != nil {
+		m.discard()
+		return nil, err
+	}
+	return m, nil
+}
+
+// parseSDFData loads the data items in the given lines as attributes
+// of the given molecule.
+//
+// Each data item begins with a header line starting with `>`, that
+// has the name of the item in angle brackets.  The value follows on
+// one or more lines, terminated by a blank line.  Lines of a
+// multi-line value are joined with newlines.
+//
+// The supplier data items are loaded into the corresponding fields of
+// the molecule, rather than as attributes.
+func parseSDFData(m *Molecule, lines []string, firstLine int) error {
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, ">") {
+			return &_ParseError{firstLine + i, fmt.Errorf("Expected a data header : %s", line)}
+		}
+
+		lb := strings.Index(line, "<")
+		rb := strings.LastIndex(line, ">")
+		if lb < 0 || rb <= lb {
+			return &_ParseError{firstLine + i, fmt.Errorf("Data header without a name : %s", line)}
+		}
+		name := line[lb+1 : rb]
+
+		vals := make([]string, 0, 1)
+		for i++; i < len(lines) && lines[i] != ""; i++ {
+			vals = append(vals, lines[i])
+		}
+		val := strings.Join(vals, "\n")
+		switch name {
+		case SDFVendorTag:
+			m.vendor = val
+		case SDFVendorMoleculeIdTag:
+			m.vendorMoleculeId = val
+		default:
+			m.SetAttribute(name, val)
+		}
+	}
+
+	return nil
+}
+
+// WriteSDF writes the given molecules to the given output as SD
+// records, in the given order.
+//
+// Each record comprises a V2000 molfile, followed by the supplier
+// information of the molecule - if set - and its attributes, as data
+// items.  See `writeMolfile` for how the structure is written.
+func WriteSDF(w io.Writer, mols []*Molecule) error {
+	var buf bytes.Buffer
+	for _, m := range mols {
+		buf.Reset()
+		m.writeMolfile(&buf)
+
+		if m.vendor != "" {
+			writeSDFDataItem(&buf, SDFVendorTag, m.vendor)
+		}
+		if m.vendorMoleculeId != "" {
+			writeSDFDataItem(&buf, SDFVendorMoleculeIdTag, m.vendorMoleculeId)
+		}
+		for _, attr := range m.attributes {
+			writeSDFDataItem(&buf, attr.Name, attr.Value)
+		}
+		buf.WriteString("$$$$\n")
+
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeSDFDataItem writes a data item with the given name and value to
+// the given buffer.  Blank lines in the value are dropped, since they
+// would terminate the item.
+func writeSDFDataItem(buf *bytes.Buffer, name, value string) {
+	fmt.Fprintf(buf, ">  <%s>\n", name)
+	for _, line := range strings.Split(value, "\n") {
+		if line != "" {
+			buf.WriteString(line)
+			buf.WriteByte('\n')
+		}
+	}
+	buf.WriteByte('\n')
+}
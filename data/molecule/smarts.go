@@ -0,0 +1,673 @@
+package molecule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// MatchSMARTS answers the matches of the given SMARTS pattern in this
+// molecule.  Each match lists the input IDs of the matched atoms, in
+// the order in which their query atoms appear in the pattern.  Matches
+// covering the same set of atoms - such as those that differ only by
+// the symmetry of the pattern - are reported once.
+//
+// A subset of SMARTS is supported.
+//
+//   - Atoms: organic-subset symbols outside brackets (`B`, `C`, `N`,
+//     `O`, `P`, `S`, `F`, `Cl`, `Br`, `I` and their aromatic forms),
+//     `*`, `a` and `A`.
+//   - Atom primitives within brackets: element symbols, `#n`, a leading
+//     mass number, `a`, `A`, `*`, `R` (in a ring), `Rn` (in `n` rings),
+//     `Dn` (degree), `Hn` (total hydrogen count), and charges `+`,
+//     `-`, `+n`, `-n`, `++` and `--`.
+//   - Logical operators on atom primitives: `!`, `&`, `,` and `;`, with
+//     the usual precedence.  Adjacent primitives are joined by `&`.
+//   - Bonds: `-`, `=`, `#`, `:`, `~` and `@` (ring bond), optionally
+//     negated by `!`.  An unspecified bond is single or aromatic.
+//   - Branches, ring closures (including `%nn`) and `.` between
+//     disconnected components.
+//
+// Uppercase symbols match only aliphatic atoms, and lowercase ones
+// only aromatic atoms.  Unsupported syntax, such as chirality and
+// recursive SMARTS, answers an error.
+//
+// Stale rings and aromaticity are determined afresh.
+func (m *Molecule) MatchSMARTS(pattern string) ([][]uint16, error) {
+	q, err := parseSmarts(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.refresh(stageAromaticity); err != nil {
+		return nil, err
+	}
+
+	return m.matchSmarts(q, 0), nil
+}
+
+// matchSmarts answers the matches of the given parsed SMARTS pattern
+// in this molecule, stopping after the given number of matches.  A
+// non-positive limit answers all the matches.  See `MatchSMARTS`.
+//
+// This method assumes that the rings and aromaticity of this molecule
+// are current.
+func (m *Molecule) matchSmarts(q *_SmartsQuery, limit int) [][]uint16 {
+	matches := make([][]uint16, 0, cmn.ListSizeTiny)
+	if len(q.atoms) == 0 || len(q.atoms) > len(m.atoms) {
+		return matches
+	}
+
+	sm := _SmartsMatcher{
+		mol:     m,
+		q:       q,
+		limit:   limit,
+		mapping: make([]*_Atom, len(q.atoms)),
+		used:    make(map[uint16]bool, len(q.atoms)),
+		seen:    make(map[string]bool),
+	}
+	sm.match(0, &matches)
+
+	return matches
+}
+
+// _SmartsAtomPred answers if the given atom satisfies a query atom.
+type _SmartsAtomPred func(a *_Atom) bool
+
+// _SmartsBondPred answers if the given bond satisfies a query bond.
+type _SmartsBondPred func(b *_Bond) bool
+
+// _SmartsBond is a bond of a parsed SMARTS pattern, between the query
+// atoms at the given indices.
+type _SmartsBond struct {
+	a1   int
+	a2   int
+	pred _SmartsBondPred
+}
+
+// _SmartsQuery is a parsed SMARTS pattern.
+type _SmartsQuery struct {
+	atoms []_SmartsAtomPred
+	bonds []_SmartsBond
+}
+
+// _SmartsRingOpening is a ring closure awaiting its closing atom.
+type _SmartsRingOpening struct {
+	atom int
+	bond _SmartsBondPred
+}
+
+// _SmartsParser holds the state of parsing a SMARTS pattern.
+type _SmartsParser struct {
+	s   string
+	pos int
+}
+
+// errorf answers an error at the current position of this parser.
+func (p *_SmartsParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("Invalid SMARTS %q at position %d : %s", p.s, p.pos+1, fmt.Sprintf(format, args...))
+}
+
+// parseSmarts parses the given SMARTS pattern.  See `MatchSMARTS` for
+// the supported subset.
+func parseSmarts(pattern string) (*_SmartsQuery, error) {
+	p := &_SmartsParser{s: pattern}
+	q := new(_SmartsQuery)
+
+	prev := -1
+	var bond _SmartsBondPred
+	branches := make([]int, 0, cmn.ListSizeTiny)
+	rings := make(map[int]_SmartsRingOpening)
+
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == '(':
+			if prev < 0 || bond != nil {
+				return nil, p.errorf("unexpected branch")
+			}
+			branches = append(branches, prev)
+			p.pos++
+
+		case c == ')':
+			if len(branches) == 0 || bond != nil {
+				return nil, p.errorf("unexpected end of branch")
+			}
+			prev = branches[len(branches)-1]
+			branches = branches[:len(branches)-1]
+			p.pos++
+
+		case c == '.':
+			if prev < 0 || bond != nil || len(branches) > 0 {
+				return nil, p.errorf("unexpected component separator")
+			}
+			prev = -1
+			p.pos++
+
+		case strings.IndexByte("-=#:~@!", c) >= 0:
+			if prev < 0 || bond != nil {
+				return nil, p.errorf("unexpected bond")
+			}
+			pred, err := p.parseBond()
+			if err != nil {
+				return nil, err
+			}
+			bond = pred
+
+		case c == '%' || isDigit(c):
+			if prev < 0 {
+				return nil, p.errorf("unexpected ring closure")
+			}
+			n, err := p.parseRingNumber()
+			if err != nil {
+				return nil, err
+			}
+			op, ok := rings[n]
+			if !ok {
+				rings[n] = _SmartsRingOpening{prev, bond}
+				bond = nil
+				continue
+			}
+			if op.atom == prev {
+				return nil, p.errorf("ring closure %d to the same atom", n)
+			}
+			if bond == nil {
+				bond = op.bond
+			}
+			q.addBond(op.atom, prev, bond)
+			delete(rings, n)
+			bond = nil
+
+		default:
+			pred, err := p.parseAtom()
+			if err != nil {
+				return nil, err
+			}
+			q.atoms = append(q.atoms, pred)
+			cur := len(q.atoms) - 1
+			if prev >= 0 {
+				q.addBond(prev, cur, bond)
+			}
+			prev = cur
+			bond = nil
+		}
+	}
+
+	switch {
+	case bond != nil:
+		return nil, p.errorf("dangling bond")
+	case len(branches) > 0:
+		return nil, p.errorf("unclosed branch")
+	case len(rings) > 0:
+		return nil, p.errorf("unclosed ring")
+	}
+
+	return q, nil
+}
+
+// addBond adds a bond between the query atoms at the given indices.
+// An unspecified bond is single or aromatic.
+func (q *_SmartsQuery) addBond(a1, a2 int, pred _SmartsBondPred) {
+	if pred == nil {
+		pred = func(b *_Bond) bool {
+			return b.isAro || b.bType == cmn.BondTypeSingle
+		}
+	}
+	q.bonds = append(q.bonds, _SmartsBond{a1, a2, pred})
+}
+
+// parseRingNumber parses a ring closure number: a single digit, or `%`
+// followed by two digits.
+func (p *_SmartsParser) parseRingNumber() (int, error) {
+	if p.s[p.pos] != '%' {
+		n := int(p.s[p.pos] - '0')
+		p.pos++
+		return n, nil
+	}
+
+	if p.pos+2 >= len(p.s) || !isDigit(p.s[p.pos+1]) || !isDigit(p.s[p.pos+2]) {
+		return 0, p.errorf("invalid ring closure number")
+	}
+	n := int(p.s[p.pos+1]-'0')*10 + int(p.s[p.pos+2]-'0')
+	p.pos += 3
+	return n, nil
+}
+
+// parseBond parses a bond primitive, optionally negated.
+func (p *_SmartsParser) parseBond() (_SmartsBondPred, error) {
+	negate := false
+	if p.s[p.pos] == '!' {
+		negate = true
+		p.pos++
+		if p.pos == len(p.s) {
+			return nil, p.errorf("missing bond after `!`")
+		}
+	}
+
+	var pred _SmartsBondPred
+	switch p.s[p.pos] {
+	case '-':
+		pred = func(b *_Bond) bool { return !b.isAro && b.bType == cmn.BondTypeSingle }
+	case '=':
+		pred = func(b *_Bond) bool { return !b.isAro && b.bType == cmn.BondTypeDouble }
+	case '#':
+		pred = func(b *_Bond) bool { return !b.isAro && b.bType == cmn.BondTypeTriple }
+	case ':':
+		pred = func(b *_Bond) bool { return b.isAro }
+	case '~':
+		pred = func(b *_Bond) bool { return true }
+	case '@':
+		pred = func(b *_Bond) bool { return b.isCyclic() }
+	default:
+		return nil, p.errorf("unsupported bond %q", p.s[p.pos])
+	}
+	p.pos++
+
+	if negate {
+		return func(b *_Bond) bool { return !pred(b) }, nil
+	}
+	return pred, nil
+}
+
+// parseAtom parses an atom: either an organic-subset symbol, or a
+// bracketed atom expression.
+func (p *_SmartsParser) parseAtom() (_SmartsAtomPred, error) {
+	if p.s[p.pos] != '[' {
+		return p.parseOrganicAtom()
+	}
+
+	p.pos++
+	pred, err := p.parseLowAnd()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos == len(p.s) || p.s[p.pos] != ']' {
+		return nil, p.errorf("expected `]`")
+	}
+	p.pos++
+	return pred, nil
+}
+
+// parseOrganicAtom parses an atom written outside brackets.
+func (p *_SmartsParser) parseOrganicAtom() (_SmartsAtomPred, error) {
+	s := p.s[p.pos:]
+	for _, sym := range []string{"Cl", "Br"} {
+		if strings.HasPrefix(s, sym) {
+			p.pos += 2
+			return smartsElement(cmn.PeriodicTable[sym].Number, false), nil
+		}
+	}
+
+	c := s[0]
+	switch c {
+	case '*':
+		p.pos++
+		return smartsAny, nil
+	case 'a':
+		p.pos++
+		return smartsAromatic, nil
+	case 'A':
+		p.pos++
+		return smartsAliphatic, nil
+	case 'B', 'C', 'N', 'O', 'P', 'S', 'F', 'I':
+		p.pos++
+		return smartsElement(cmn.PeriodicTable[string(c)].Number, false), nil
+	case 'b', 'c', 'n', 'o', 'p', 's':
+		p.pos++
+		return smartsElement(cmn.PeriodicTable[strings.ToUpper(string(c))].Number, true), nil
+	}
+
+	return nil, p.errorf("unsupported atom %q", c)
+}
+
+// parseLowAnd parses primitives joined by the low-precedence `;`.
+func (p *_SmartsParser) parseLowAnd() (_SmartsAtomPred, error) {
+	pred, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.pos < len(p.s) && p.s[p.pos] == ';' {
+		p.pos++
+		rhs, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		pred = smartsAnd(pred, rhs)
+	}
+
+	return pred, nil
+}
+
+// parseOr parses primitives joined by `,`.
+func (p *_SmartsParser) parseOr() (_SmartsAtomPred, error) {
+	pred, err := p.parseHighAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.pos < len(p.s) && p.s[p.pos] == ',' {
+		p.pos++
+		rhs, err := p.parseHighAnd()
+		if err != nil {
+			return nil, err
+		}
+		lhs := pred
+		pred = func(a *_Atom) bool { return lhs(a) || rhs(a) }
+	}
+
+	return pred, nil
+}
+
+// parseHighAnd parses primitives joined by `&`, or simply adjacent.
+func (p *_SmartsParser) parseHighAnd() (_SmartsAtomPred, error) {
+	pred, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.pos < len(p.s) && strings.IndexByte(";,]", p.s[p.pos]) < 0 {
+		if p.s[p.pos] == '&' {
+			p.pos++
+		}
+		rhs, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		pred = smartsAnd(pred, rhs)
+	}
+
+	return pred, nil
+}
+
+// parseNot parses a primitive, optionally negated.
+func (p *_SmartsParser) parseNot() (_SmartsAtomPred, error) {
+	if p.pos < len(p.s) && p.s[p.pos] == '!' {
+		p.pos++
+		pred, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(a *_Atom) bool { return !pred(a) }, nil
+	}
+
+	return p.parsePrimitive()
+}
+
+// parsePrimitive parses a single atom primitive within brackets.
+func (p *_SmartsParser) parsePrimitive() (_SmartsAtomPred, error) {
+	if p.pos == len(p.s) {
+		return nil, p.errorf("unexpected end of pattern")
+	}
+
+	s := p.s[p.pos:]
+	c := s[0]
+
+	// Two-letter element symbols take precedence over single-letter
+	// primitives.
+	if len(s) > 1 && c >= 'A' && c <= 'Z' && s[1] >= 'a' && s[1] <= 'z' {
+		if el, ok := cmn.PeriodicTable[s[:2]]; ok {
+			p.pos += 2
+			return smartsElement(el.Number, false), nil
+		}
+	}
+
+	switch {
+	case c == '*':
+		p.pos++
+		return smartsAny, nil
+
+	case c == 'a':
+		p.pos++
+		return smartsAromatic, nil
+
+	case c == 'A':
+		p.pos++
+		return smartsAliphatic, nil
+
+	case c == '#':
+		p.pos++
+		n, ok := p.parseNumber()
+		if !ok {
+			return nil, p.errorf("missing atomic number")
+		}
+		return func(a *_Atom) bool { return int(a.atNum) == n }, nil
+
+	case isDigit(c):
+		n, _ := p.parseNumber()
+		return func(a *_Atom) bool { return int(a.isotope) == n }, nil
+
+	case c == 'R':
+		p.pos++
+		n, ok := p.parseNumber()
+		if !ok {
+			return func(a *_Atom) bool { return a.isCyclic() }, nil
+		}
+		return func(a *_Atom) bool { return int(a.rings.Count()) == n }, nil
+
+	case c == 'D':
+		p.pos++
+		n, ok := p.parseNumber()
+		if !ok {
+			n = 1
+		}
+		return func(a *_Atom) bool { return int(a.bonds.Count()) == n }, nil
+
+	case c == 'H':
+		p.pos++
+		n, ok := p.parseNumber()
+		if !ok {
+			n = 1
+		}
+		return func(a *_Atom) bool { return a.totalHydrogenCount() == n }, nil
+
+	case c == '+' || c == '-':
+		return p.parseCharge(), nil
+
+	case c == 'b' || c == 'c' || c == 'n' || c == 'o' || c == 'p' || c == 's':
+		p.pos++
+		return smartsElement(cmn.PeriodicTable[strings.ToUpper(string(c))].Number, true), nil
+
+	case c >= 'A' && c <= 'Z':
+		if el, ok := cmn.PeriodicTable[string(c)]; ok {
+			p.pos++
+			return smartsElement(el.Number, false), nil
+		}
+	}
+
+	return nil, p.errorf("unsupported primitive %q", c)
+}
+
+// parseCharge parses a charge primitive: a sign, followed by either a
+// number, or further repetitions of the sign.
+func (p *_SmartsParser) parseCharge() _SmartsAtomPred {
+	sign := 1
+	if p.s[p.pos] == '-' {
+		sign = -1
+	}
+	c := p.s[p.pos]
+	p.pos++
+
+	n, ok := p.parseNumber()
+	if !ok {
+		n = 1
+		for p.pos < len(p.s) && p.s[p.pos] == c {
+			n++
+			p.pos++
+		}
+	}
+
+	ch := sign * n
+	return func(a *_Atom) bool { return int(a.charge) == ch }
+}
+
+// parseNumber parses an unsigned decimal number, if one is present at
+// the current position.
+func (p *_SmartsParser) parseNumber() (int, bool) {
+	n, ok := 0, false
+	for p.pos < len(p.s) && isDigit(p.s[p.pos]) {
+		n = 10*n + int(p.s[p.pos]-'0')
+		p.pos++
+		ok = true
+	}
+
+	return n, ok
+}
+
+// isDigit answers if the given character is a decimal digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// smartsAny is satisfied by every atom.
+func smartsAny(a *_Atom) bool {
+	return true
+}
+
+// smartsAromatic is satisfied by aromatic atoms.
+func smartsAromatic(a *_Atom) bool {
+	return a.isAromatic()
+}
+
+// smartsAliphatic is satisfied by non-aromatic atoms.
+func smartsAliphatic(a *_Atom) bool {
+	return !a.isAromatic()
+}
+
+// smartsElement answers a predicate that is satisfied by atoms of the
+// given element, and of the given aromaticity.
+func smartsElement(atNum uint8, aromatic bool) _SmartsAtomPred {
+	return func(a *_Atom) bool {
+		return a.atNum == atNum && a.isAromatic() == aromatic
+	}
+}
+
+// smartsAnd answers a predicate satisfied by atoms that satisfy both
+// the given predicates.
+func smartsAnd(lhs, rhs _SmartsAtomPred) _SmartsAtomPred {
+	return func(a *_Atom) bool { return lhs(a) && rhs(a) }
+}
+
+// totalHydrogenCount answers the number of hydrogen atoms attached to
+// this atom, both implicit and explicit.
+func (a *_Atom) totalHydrogenCount() int {
+	n := int(a.hCount)
+	mol := a.mol
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		if mol.atomWithIid(mol.bondWithId(uint16(bid)).otherAtomIid(a.iId)).atNum == 1 {
+			n++
+		}
+	}
+
+	return n
+}
+
+// _SmartsMatcher holds the state of matching a parsed SMARTS pattern
+// against a molecule.
+//
+// Query atoms are mapped in order, by backtracking.  A query atom
+// bonded to an already-mapped one is tried only against the
+// neighbours of its image; every query bond to an already-mapped atom
+// is checked as soon as its second atom is mapped.
+type _SmartsMatcher struct {
+	mol     *Molecule
+	q       *_SmartsQuery
+	limit   int             // Maximum number of matches; all if non-positive.
+	mapping []*_Atom        // Molecule atom mapped to each query atom.
+	used    map[uint16]bool // Input IDs of the mapped molecule atoms.
+	seen    map[string]bool // Atom sets of the matches so far.
+}
+
+// match maps the query atom at the given index, and those after it,
+// in all possible ways, appending complete matches to the given list.
+func (sm *_SmartsMatcher) match(qi int, matches *[][]uint16) {
+	if qi == len(sm.q.atoms) {
+		sm.record(matches)
+		return
+	}
+
+	var cands []*_Atom
+	if anchor := sm.anchorOf(qi); anchor != nil {
+		mol := sm.mol
+		for bid, ok := anchor.bonds.NextSet(0); ok; bid, ok = anchor.bonds.NextSet(bid + 1) {
+			cands = append(cands, mol.atomWithIid(mol.bondWithId(uint16(bid)).otherAtomIid(anchor.iId)))
+		}
+	} else {
+		cands = sm.mol.atoms
+	}
+
+	for _, a := range cands {
+		if sm.limit > 0 && len(*matches) >= sm.limit {
+			return
+		}
+		if sm.used[a.iId] || !sm.q.atoms[qi](a) || !sm.bondsMatch(qi, a) {
+			continue
+		}
+
+		sm.mapping[qi] = a
+		sm.used[a.iId] = true
+		sm.match(qi+1, matches)
+		delete(sm.used, a.iId)
+		sm.mapping[qi] = nil
+	}
+}
+
+// anchorOf answers the molecule atom mapped to a query atom that
+// precedes, and is bonded to, the query atom at the given index.
+// Answers `nil` if there is none.
+func (sm *_SmartsMatcher) anchorOf(qi int) *_Atom {
+	for _, qb := range sm.q.bonds {
+		switch {
+		case qb.a2 == qi && qb.a1 < qi:
+			return sm.mapping[qb.a1]
+		case qb.a1 == qi && qb.a2 < qi:
+			return sm.mapping[qb.a2]
+		}
+	}
+
+	return nil
+}
+
+// bondsMatch answers if mapping the query atom at the given index to
+// the given molecule atom satisfies all the query bonds between it and
+// the query atoms already mapped.
+func (sm *_SmartsMatcher) bondsMatch(qi int, a *_Atom) bool {
+	for _, qb := range sm.q.bonds {
+		var other int
+		switch {
+		case qb.a2 == qi && qb.a1 < qi:
+			other = qb.a1
+		case qb.a1 == qi && qb.a2 < qi:
+			other = qb.a2
+		default:
+			continue
+		}
+
+		b := a.bondTo(sm.mapping[other].iId)
+		if b == nil || !qb.pred(b) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// record appends the current mapping to the given list of matches,
+// unless a match covering the same atoms has already been recorded.
+func (sm *_SmartsMatcher) record(matches *[][]uint16) {
+	ids := make([]uint16, len(sm.mapping))
+	for i, a := range sm.mapping {
+		ids[i] = a.iId
+	}
+
+	sorted := append([]uint16(nil), ids...)
+	sort.Sort(_Uint16s(sorted))
+	key := fmt.Sprint(sorted)
+	if sm.seen[key] {
+		return
+	}
+	sm.seen[key] = true
+
+	*matches = append(*matches, ids)
+}
@@ -0,0 +1,146 @@
+package molecule
+
+import (
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// StandardizeOptions selects the steps performed by `Standardize`.
+// Each step is performed only when its option is set.
+type StandardizeOptions struct {
+	// Keep only the largest connected component, discarding
+	// counter-ions and solvents.  See `LargestComponent`.
+	LargestFragment bool
+	// Fold explicit hydrogen atoms into the hydrogen counts of their
+	// neighbours.  See `suppressHydrogens`.
+	SuppressHydrogens bool
+	// Remove the charges that a proton can neutralise.  See
+	// `Neutralize`.
+	Neutralize bool
+	// Convert enols and imidols into their keto and amide forms.  See
+	// `CanonicalTautomer`.
+	CanonicalTautomer bool
+	// Renumber the atoms in their canonical order.  See
+	// `RenumberCanonically`.
+	Renumber bool
+}
+
+// DefaultStandardizeOptions answers options that perform all the steps
+// of `Standardize`.
+func DefaultStandardizeOptions() StandardizeOptions {
+	return StandardizeOptions{
+		LargestFragment:   true,
+		SuppressHydrogens: true,
+		Neutralize:        true,
+		CanonicalTautomer: true,
+		Renumber:          true,
+	}
+}
+
+// Standardize brings this molecule, in place, to a standard form, as
+// is usual before storing it.  The steps selected by the given options
+// are performed in the following order.
+//
+//   - The largest connected component is kept.
+//   - Explicit hydrogen atoms are suppressed.
+//   - Charges are neutralised.
+//   - The canonical tautomer is formed.
+//   - The atoms are renumbered canonically.
+//
+// Hydrogen atoms are suppressed before the charges and tautomers are
+// considered, so that those steps see all the hydrogen atoms of each
+// atom in its hydrogen count.  Renumbering comes last, since it depends
+// on the final structure.
+//
+// This molecule is normalised - and, hence, frozen - at the end,
+// whichever steps are selected.  The molecule ID, vendor information
+// and attributes are retained.  Standardizing a molecule again, with
+// the same options, leaves it unchanged.
+//
+// Being a whole-molecule transformation, this method works on frozen
+// molecules as well: it unfreezes this molecule for the duration.
+// Should a step fail, this molecule is left unfrozen.  See `Freeze`.
+func (m *Molecule) Standardize(opts StandardizeOptions) error {
+	m.Unfreeze()
+
+	if opts.LargestFragment && m.ComponentCount() > 1 {
+		frag, err := m.LargestComponent()
+		if err != nil {
+			return err
+		}
+		m.adopt(frag)
+	}
+
+	if opts.SuppressHydrogens {
+		if err := m.suppressHydrogens(); err != nil {
+			return err
+		}
+	}
+
+	if opts.Neutralize {
+		if _, err := m.neutralize(); err != nil {
+			return err
+		}
+	}
+
+	if opts.CanonicalTautomer {
+		if err := m.refresh(stageAromaticity); err != nil {
+			return err
+		}
+		shifted := false
+		for m.shiftEnolicHydrogen() {
+			shifted = true
+		}
+		if shifted {
+			m.markStale(stageAromaticity | stageHashes)
+		}
+	}
+
+	if !m.isNormalised {
+		if err := m.Normalise(); err != nil {
+			return err
+		}
+	}
+	if opts.Renumber {
+		if err := m.renumberCanonically(); err != nil {
+			return err
+		}
+	}
+
+	m.Freeze()
+	return nil
+}
+
+// suppressHydrogens removes the hydrogen atoms of this molecule that
+// are held as atoms, adding each to the hydrogen count of its
+// neighbour.  Plain hydrogen atoms are folded so already on input;
+// those remaining are specific isotopes, such as deuterium, whose
+// labels are, hence, lost.
+//
+// Hydrogen atoms that are charged, radicals, mapped, or not bound by a
+// single bond to exactly one other atom - itself not a hydrogen - are
+// retained.
+func (m *Molecule) suppressHydrogens() error {
+	hs := make([]*_Atom, 0, cmn.ListSizeTiny)
+	for _, a := range m.atoms {
+		if a.atNum != 1 || a.charge != 0 || a.radical != cmn.RadicalNone || a.mapNum != 0 {
+			continue
+		}
+		if a.bonds.Count() != 1 || a.singleBondCount != 1 {
+			continue
+		}
+		if nbr := m.atomWithIid(a.nbrs[0]); nbr.atNum == 1 {
+			continue
+		}
+		hs = append(hs, a)
+	}
+
+	for _, h := range hs {
+		nbr := m.atomWithIid(h.nbrs[0])
+		if err := m.RemoveAtom(h.iId); err != nil {
+			return err
+		}
+		nbr.hCount++
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package molecule
+
+import (
+	"bytes"
+	"testing"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// buildEthanol answers ethanol, built with a `MoleculeBuilder`, and
+// hence normalised and frozen.
+func buildEthanol(t *testing.T) *Molecule {
+	mb := NewMoleculeBuilder()
+	c1, err := mb.AddAtom("C", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2, err := mb.AddAtom("C", 1.3, 0.75, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, err := mb.AddAtom("O", 2.6, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mb.AddBond(c1, c2, cmn.BondTypeSingle); err != nil {
+		t.Fatal(err)
+	}
+	if err := mb.AddBond(c2, o, cmn.BondTypeSingle); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := mb.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestStandardizeFrozen(t *testing.T) {
+	m := buildEthanol(t)
+	defer m.discard()
+	if !m.IsFrozen() {
+		t.Fatal("built molecule is not frozen")
+	}
+
+	if err := m.Standardize(DefaultStandardizeOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if !m.IsFrozen() || !m.IsNormalised() {
+		t.Error("standardized molecule is not normalised and frozen")
+	}
+}
+
+func TestStandardizeIdempotent(t *testing.T) {
+	m, _, err := ParseMolfile(molfileLines(acetateMolfile), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.discard()
+
+	if err := m.Standardize(DefaultStandardizeOptions()); err != nil {
+		t.Fatal(err)
+	}
+	var b1 bytes.Buffer
+	m.writeMolfile(&b1)
+	k1 := m.CanonicalKey()
+
+	if err := m.Standardize(DefaultStandardizeOptions()); err != nil {
+		t.Fatal(err)
+	}
+	var b2 bytes.Buffer
+	m.writeMolfile(&b2)
+	if k2 := m.CanonicalKey(); k2 != k1 {
+		t.Errorf("canonical key changed from %s to %s", k1, k2)
+	}
+	if b1.String() != b2.String() {
+		t.Errorf("molfile changed from\n%s\nto\n%s", b1.String(), b2.String())
+	}
+}
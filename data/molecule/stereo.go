@@ -0,0 +1,464 @@
+package molecule
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// substituentKey answers a key representing the priority of this
+// atom, as a substituent of the given atom.
+//
+// The key comprises this atom's atomic number, followed by those of
+// its other neighbours in descending order.  In line with CIP rules,
+// a neighbour across a multiple bond is duplicated, and each attached
+// hydrogen atom counts as a neighbour with atomic number `1`.
+func (a *_Atom) substituentKey(from uint16) []int {
+	mol := a.mol
+
+	rest := make([]int, 0, len(a.nbrs)+int(a.hCount))
+	skipped := false
+	for _, nid := range a.nbrs {
+		if nid == from && !skipped {
+			skipped = true
+			continue
+		}
+		rest = append(rest, int(mol.atomWithIid(nid).atNum))
+	}
+	for i := 0; i < int(a.hCount); i++ {
+		rest = append(rest, 1)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(rest)))
+
+	return append([]int{int(a.atNum)}, rest...)
+}
+
+// doubleBondReference answers the neighbour of this atom - other than
+// the given one - having the highest priority, when it can be
+// distinguished from the remaining substituent of this atom.
+//
+// This atom is expected to be one end of a double bond, with the
+// given atom at the other end.  Answers `false` if this atom does not
+// have suitable substituents for the double bond to be stereogenic.
+func (a *_Atom) doubleBondReference(other uint16) (*_Atom, bool) {
+	if a.doubleBondCount != 1 || a.tripleBondCount != 0 {
+		return nil, false
+	}
+
+	mol := a.mol
+	subs := make([]*_Atom, 0, 2)
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		oaid := mol.bondWithId(uint16(bid)).otherAtomIid(a.iId)
+		if oaid != other {
+			subs = append(subs, mol.atomWithIid(oaid))
+		}
+	}
+
+	switch {
+	case len(subs) == 1 && a.hCount <= 1:
+		return subs[0], true // Hydrogen, if any, has a lower priority.
+
+	case len(subs) == 2 && a.hCount == 0:
+		c := compareKeys(subs[0].substituentKey(a.iId), subs[1].substituentKey(a.iId))
+		switch {
+		case c > 0:
+			return subs[0], true
+		case c < 0:
+			return subs[1], true
+		}
+	}
+
+	return nil, false
+}
+
+// sideOf answers a value whose sign indicates the side of the line
+// from atom `a` to atom `b` on which atom `x` lies, in the X-Y plane.
+// The value is `0` if the three atoms are collinear.
+func sideOf(a, b, x *_Atom) float64 {
+	return float64((b.X-a.X)*(x.Y-a.Y) - (b.Y-a.Y)*(x.X-a.X))
+}
+
+// AssignDoubleBondStereo determines the E/Z configuration of each
+// stereogenic double bond in this molecule, using the 2-D
+// coordinates of its atoms.
+//
+// A double bond qualifies when it is not in a ring, and each of its
+// atoms has a neighbour that has a higher priority than its other
+// substituent.  Priorities are assigned in a CIP-like manner, first
+// on atomic numbers, and then on those of the neighbours.  Symmetric
+// and terminal double bonds are left unmarked, as are those whose
+// reference neighbours are collinear with the bond.
+//
+// E is recorded as `StereoParityEven`, and Z as `StereoParityOdd`.
+// A qualifying double bond marked as `BondStereoDoubleEither`, or one
+// of whose atoms starts a wavy `BondStereoEither` bond, is recorded as
+// `StereoParityUnknown` instead.
+//
+// This molecule must have been normalised, before this method is
+// invoked.
+func (m *Molecule) AssignDoubleBondStereo() error {
+	if !m.isNormalised {
+		return fmt.Errorf("Molecule %d has not been normalised.", m.id)
+	}
+
+	for _, b := range m.bonds {
+		b.stereoParity = cmn.StereoParityNone
+		if b.bType != cmn.BondTypeDouble || b.isCyclic() || b.isAro {
+			continue
+		}
+
+		a1 := m.atomWithIid(b.a1)
+		a2 := m.atomWithIid(b.a2)
+		x, ok1 := a1.doubleBondReference(b.a2)
+		y, ok2 := a2.doubleBondReference(b.a1)
+		if !ok1 || !ok2 {
+			continue
+		}
+		if b.bStereo == cmn.BondStereoDoubleEither || a1.startsEitherBond() || a2.startsEitherBond() {
+			b.stereoParity = cmn.StereoParityUnknown
+			continue
+		}
+
+		s := sideOf(a1, a2, x) * sideOf(a1, a2, y)
+		switch {
+		case s > 0: // Same side.
+			b.stereoParity = cmn.StereoParityOdd
+		case s < 0: // Opposite sides.
+			b.stereoParity = cmn.StereoParityEven
+		}
+	}
+
+	return nil
+}
+
+// branchSpheres answers the atomic numbers of the atoms in the branch
+// rooted at this atom, when reached from the given atom, grouped by
+// their distance from this atom.  Each group is in descending order.
+//
+// As in CIP, a neighbour across a multiple bond is duplicated, and
+// each attached hydrogen atom counts as a neighbour with atomic
+// number `1`.  An atom reached again by closing a ring is listed, but
+// not explored further.
+func (a *_Atom) branchSpheres(from uint16) [][]int {
+	mol := a.mol
+
+	spheres := [][]int{{int(a.atNum)}}
+	seen := map[uint16]bool{from: true, a.iId: true}
+	parents := map[uint16]uint16{a.iId: from}
+	frontier := []*_Atom{a}
+	for len(frontier) > 0 {
+		sphere := make([]int, 0, 3*len(frontier))
+		next := make([]*_Atom, 0, 3*len(frontier))
+		for _, fa := range frontier {
+			skipped := false
+			for _, nid := range fa.nbrs {
+				if nid == parents[fa.iId] && !skipped {
+					skipped = true
+					continue
+				}
+				na := mol.atomWithIid(nid)
+				sphere = append(sphere, int(na.atNum))
+				if !seen[nid] {
+					seen[nid] = true
+					parents[nid] = fa.iId
+					next = append(next, na)
+				}
+			}
+			for i := 0; i < int(fa.hCount); i++ {
+				sphere = append(sphere, 1)
+			}
+		}
+		if len(sphere) == 0 {
+			break
+		}
+
+		sort.Sort(sort.Reverse(sort.IntSlice(sphere)))
+		spheres = append(spheres, sphere)
+		frontier = next
+	}
+
+	return spheres
+}
+
+// compareBranches compares the given branches sphere by sphere.  See
+// `branchSpheres`.
+func compareBranches(s1, s2 [][]int) int {
+	for i := 0; i < len(s1) && i < len(s2); i++ {
+		if c := compareKeys(s1[i], s2[i]); c != 0 {
+			return c
+		}
+	}
+
+	return len(s1) - len(s2)
+}
+
+// _RankedNbrs sorts the neighbours of an atom in descending order of
+// their priorities, given their branches.
+type _RankedNbrs struct {
+	atoms    []*_Atom
+	branches [][][]int
+}
+
+func (rn _RankedNbrs) Len() int {
+	return len(rn.atoms)
+}
+
+func (rn _RankedNbrs) Swap(i, j int) {
+	rn.atoms[i], rn.atoms[j] = rn.atoms[j], rn.atoms[i]
+	rn.branches[i], rn.branches[j] = rn.branches[j], rn.branches[i]
+}
+
+func (rn _RankedNbrs) Less(i, j int) bool {
+	return compareBranches(rn.branches[i], rn.branches[j]) > 0
+}
+
+// rankedNeighbours answers the neighbours of this atom in descending
+// order of priority.  It also answers if all of them - and any
+// attached hydrogen atom - are mutually distinguishable.
+func (a *_Atom) rankedNeighbours() ([]*_Atom, bool) {
+	mol := a.mol
+
+	rn := _RankedNbrs{}
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		na := mol.atomWithIid(mol.bondWithId(uint16(bid)).otherAtomIid(a.iId))
+		rn.atoms = append(rn.atoms, na)
+		rn.branches = append(rn.branches, na.branchSpheres(a.iId))
+	}
+	sort.Sort(rn)
+
+	for i := 1; i < len(rn.atoms); i++ {
+		if compareBranches(rn.branches[i-1], rn.branches[i]) == 0 {
+			return rn.atoms, false
+		}
+	}
+	if a.hCount > 1 {
+		return rn.atoms, false
+	}
+
+	return rn.atoms, true
+}
+
+// isTetrahedralCandidate answers if this atom could be a tetrahedral
+// stereo centre, judging by its bonds alone: it should have four
+// substituents - one of them possibly an implicit hydrogen - all
+// bound by single bonds.
+func (a *_Atom) isTetrahedralCandidate() bool {
+	if a.doubleBondCount > 0 || a.tripleBondCount > 0 || a.isInAroRing {
+		return false
+	}
+
+	nb := int(a.bonds.Count())
+	switch {
+	case nb == 4 && a.hCount == 0:
+		return true
+	case nb == 3 && a.hCount == 1:
+		return true
+	}
+
+	return false
+}
+
+// stereoZ answers the Z-coordinate to use for the given neighbour of
+// this atom, when computing tetrahedral parity.
+//
+// When the bond to the neighbour is a wedge starting at this atom,
+// the neighbour is lifted above or pushed below the plane by the
+// length of the bond.  Answers the neighbour's own Z-coordinate
+// otherwise.  The second answer tells if a wedge was found.
+func (a *_Atom) stereoZ(nbr *_Atom) (float64, bool) {
+	b := a.bondTo(nbr.iId)
+	if b.a1 != a.iId {
+		return float64(nbr.Z), false
+	}
+
+	dx := float64(nbr.X - a.X)
+	dy := float64(nbr.Y - a.Y)
+	l := math.Sqrt(dx*dx + dy*dy)
+	switch b.bStereo {
+	case cmn.BondStereoUp:
+		return float64(nbr.Z) + l, true
+	case cmn.BondStereoDown:
+		return float64(nbr.Z) - l, true
+	}
+
+	return float64(nbr.Z), false
+}
+
+// tetrahedralParity computes the parity of this atom, given its
+// neighbours in descending order of priority.  See
+// `doc/design/stereo-determination.md`.
+//
+// Answers `StereoParityNone` when the parity cannot be determined.
+func (a *_Atom) tetrahedralParity(nbrs []*_Atom) cmn.StereoParity {
+	pts := make([][3]float64, 0, 4)
+	if len(nbrs) == 3 {
+		pts = append(pts, [3]float64{float64(a.X), float64(a.Y), float64(a.Z)})
+	}
+
+	wedged := false
+	zs := make(map[float64]bool)
+	for _, na := range nbrs {
+		z, ok := a.stereoZ(na)
+		wedged = wedged || ok
+		zs[z] = true
+		pts = append(pts, [3]float64{float64(na.X), float64(na.Y), z})
+	}
+	if !wedged && len(zs) == 1 {
+		return cmn.StereoParityNone // Planar, with no wedges.
+	}
+
+	d := determinant4(pts)
+	switch {
+	case d > 0:
+		return cmn.StereoParityEven
+	case d < 0:
+		return cmn.StereoParityOdd
+	}
+
+	return cmn.StereoParityNone
+}
+
+// determinant4 answers the determinant of the 4x4 matrix whose rows
+// comprise `1.0` followed by the coordinates of each given point.
+func determinant4(pts [][3]float64) float64 {
+	var v [3][3]float64
+	for i := 1; i < 4; i++ {
+		for j := 0; j < 3; j++ {
+			v[i-1][j] = pts[i][j] - pts[0][j]
+		}
+	}
+
+	return v[0][0]*(v[1][1]*v[2][2]-v[1][2]*v[2][1]) -
+		v[0][1]*(v[1][0]*v[2][2]-v[1][2]*v[2][0]) +
+		v[0][2]*(v[1][0]*v[2][1]-v[1][1]*v[2][0])
+}
+
+// PerceiveTetrahedralStereo determines the parity of each tetrahedral
+// stereo centre in this molecule.
+//
+// An atom qualifies when it has four single-bonded substituents - one
+// of which may be an implicit hydrogen - all distinguishable in
+// priority.  Its parity is computed from the coordinates of its
+// neighbours, as described in `doc/design/stereo-determination.md`.
+// With 2-D coordinates, wedge bonds starting at the atom supply the
+// missing depth.  Atoms without such information are left at
+// `StereoParityNone`.  Those starting a wavy `BondStereoEither` bond
+// are marked `StereoParityUnknown`, without computing a parity.
+//
+// This molecule must have been normalised, before this method is
+// invoked.
+func (m *Molecule) PerceiveTetrahedralStereo() error {
+	if !m.isNormalised {
+		return fmt.Errorf("Molecule %d has not been normalised.", m.id)
+	}
+
+	for _, a := range m.atoms {
+		a.stereoParity = cmn.StereoParityNone
+		if !a.isTetrahedralCandidate() {
+			continue
+		}
+
+		nbrs, ok := a.rankedNeighbours()
+		if !ok {
+			continue
+		}
+		if a.startsEitherBond() {
+			a.stereoParity = cmn.StereoParityUnknown
+			continue
+		}
+		a.stereoParity = a.tetrahedralParity(nbrs)
+	}
+
+	return nil
+}
+
+// startsEitherBond answers if this atom is the first atom of a bond
+// marked `BondStereoEither`: a wavy bond, denoting that the
+// configuration at this atom is unknown.
+func (a *_Atom) startsEitherBond() bool {
+	mol := a.mol
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		b := mol.bondWithId(uint16(bid))
+		if b.a1 == a.iId && b.bStereo == cmn.BondStereoEither {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasUndefinedStereo answers if the stereo configuration of some part
+// of this molecule is explicitly unknown: a bond is marked
+// `BondStereoEither` or `BondStereoDoubleEither`, or a stereo centre
+// or double bond has been perceived as `StereoParityUnknown`.
+//
+// The marks are inspected directly; stereo need not have been
+// perceived.
+func (m *Molecule) HasUndefinedStereo() bool {
+	for _, b := range m.bonds {
+		switch {
+		case b.bStereo == cmn.BondStereoEither, b.bStereo == cmn.BondStereoDoubleEither:
+			return true
+		case b.stereoParity == cmn.StereoParityUnknown:
+			return true
+		}
+	}
+	for _, a := range m.atoms {
+		if a.stereoParity == cmn.StereoParityUnknown {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PotentialStereocentres answers the input IDs of the atoms of this
+// molecule that could be tetrahedral stereo centres, in the order in
+// which they are held.  Such an atom has four substituents bound by
+// single bonds - one of them possibly an implicit hydrogen - that are
+// mutually topologically distinct.
+//
+// Unlike `PerceiveTetrahedralStereo`, coordinates and wedges play no
+// part; the answer includes centres whose configuration is not
+// specified.  Centres that are stereogenic only by virtue of other
+// centres, such as those in `meso' compounds, are not detected.
+//
+// Stale rings and aromaticity are determined afresh; `nil` is answered
+// if that fails.
+func (m *Molecule) PotentialStereocentres() []uint16 {
+	if m.refresh(stageAromaticity) != nil {
+		return nil
+	}
+
+	ranks, _ := m.topologicalRanks()
+	rankOf := make(map[uint16]int, len(m.atoms))
+	for i, a := range m.atoms {
+		rankOf[a.iId] = ranks[i]
+	}
+
+	ids := make([]uint16, 0, cmn.ListSizeTiny)
+	for _, a := range m.atoms {
+		if !a.isTetrahedralCandidate() {
+			continue
+		}
+
+		distinct := true
+		seen := make(map[int]bool, 4)
+		for _, nid := range a.distinctNeighbours() {
+			r := rankOf[nid]
+			if seen[r] {
+				distinct = false
+				break
+			}
+			seen[r] = true
+		}
+		if !distinct {
+			continue
+		}
+		ids = append(ids, a.iId)
+	}
+
+	return ids
+}
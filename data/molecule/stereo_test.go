@@ -0,0 +1,32 @@
+package molecule
+
+import "testing"
+
+// TestPotentialStereocentres checks that C-2 of 2-butanol, bearing
+// hydrogen, hydroxyl, methyl and ethyl groups, is a potential stereo
+// centre, while C-2 of 2-propanol, bearing two methyl groups, is not.
+func TestPotentialStereocentres(t *testing.T) {
+	cases := []struct {
+		name    string
+		syms    []string
+		bonds   []_TestBond
+		centres []uint16
+	}{
+		// C1 C2(O5) C3 C4
+		{"2-butanol", []string{"C", "C", "C", "C", "O"},
+			testBonds([][3]int{{1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {2, 5, 1}}),
+			[]uint16{2}},
+		// C1 C2(O4) C3
+		{"2-propanol", []string{"C", "C", "C", "O"},
+			testBonds([][3]int{{1, 2, 1}, {2, 3, 1}, {2, 4, 1}}),
+			nil},
+	}
+	for _, c := range cases {
+		m := buildMolecule(t, c.syms, c.bonds)
+		ids := m.PotentialStereocentres()
+		if !equalIds(ids, c.centres) {
+			t.Errorf("%s : potential stereo centres %v; want %v", c.name, ids, c.centres)
+		}
+		m.discard()
+	}
+}
@@ -0,0 +1,387 @@
+package molecule
+
+import (
+	"fmt"
+
+	bits "github.com/willf/bitset"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// _StructuralKey is a structural query, whose answer for a molecule
+// sets one bit of its structural keys.
+type _StructuralKey struct {
+	name string               // Human-readable description of the query.
+	test func(*Molecule) bool // Does the given molecule satisfy the query?
+}
+
+// structuralKeys lists the structural queries, in the order of their
+// bits.  Queries should only ever be appended, so that the keys of
+// molecules remain comparable.
+var structuralKeys = buildStructuralKeys()
+
+// StructuralKeys answers a fixed-length fingerprint of this molecule,
+// in the manner of MACCS keys.  Each bit answers a structural query:
+// the presence of an element, a functional group, a ring of a given
+// kind, a bond between given elements, etc.  See `StructuralKeyName`
+// for what each bit represents.
+//
+// This molecule should have been normalised; `nil` is answered
+// otherwise.
+func (m *Molecule) StructuralKeys() *bits.BitSet {
+	if !m.isNormalised {
+		return nil
+	}
+
+	fp := bits.New(uint(len(structuralKeys)))
+	for i, sk := range structuralKeys {
+		if sk.test(m) {
+			fp.Set(uint(i))
+		}
+	}
+	return fp
+}
+
+// StructuralKeyCount answers the number of bits in structural keys.
+func StructuralKeyCount() int {
+	return len(structuralKeys)
+}
+
+// StructuralKeyName answers a human-readable description of the query
+// answered by the given bit of structural keys.  Answers `false` if
+// the bit is out of range.
+//
+// Bond symbols in descriptions are as in SMILES: `-`, `=` and `#` for
+// single, double and triple bonds, and `:` for aromatic ones.
+func StructuralKeyName(bit int) (string, bool) {
+	if bit < 0 || bit >= len(structuralKeys) {
+		return "", false
+	}
+
+	return structuralKeys[bit].name, true
+}
+
+// buildStructuralKeys answers the structural queries, in the order of
+// their bits.
+func buildStructuralKeys() []_StructuralKey {
+	const (
+		single = cmn.BondTypeSingle
+		double = cmn.BondTypeDouble
+		triple = cmn.BondTypeTriple
+		aro    = cmn.BondTypeAltern
+	)
+
+	sks := make([]_StructuralKey, 0, 160)
+
+	// Elements.
+	for _, z := range []uint8{1, 3, 5, 6, 7, 8, 9, 11, 12, 13, 14, 15, 16, 17, 19, 20, 26, 29, 30, 33, 34, 35, 50, 53} {
+		sks = append(sks, elementKey(z, 1))
+	}
+	sks = append(sks,
+		elementKey(7, 2), elementKey(7, 3),
+		elementKey(8, 2), elementKey(8, 3), elementKey(8, 4),
+		elementKey(16, 2), elementKey(9, 3), elementKey(6, 10),
+	)
+	for _, n := range []int{1, 2, 3} {
+		sks = append(sks, atomCountKey(fmt.Sprintf("halogen >= %d", n), n, (*_Atom).isHalogen))
+	}
+	for _, n := range []int{8, 16, 24, 32} {
+		n := n
+		sks = append(sks, _StructuralKey{fmt.Sprintf("heavy atoms >= %d", n), func(m *Molecule) bool {
+			return len(m.atoms) >= n
+		}})
+	}
+
+	// Atom states.
+	sks = append(sks,
+		atomKey("positive atom", func(a *_Atom) bool { return a.charge > 0 }),
+		atomKey("negative atom", func(a *_Atom) bool { return a.charge < 0 }),
+		atomKey("radical", func(a *_Atom) bool { return a.radical != cmn.RadicalNone }),
+		atomKey("isotope", func(a *_Atom) bool { return a.isotope != 0 }),
+		_StructuralKey{"dative bond", func(m *Molecule) bool {
+			for _, b := range m.bonds {
+				if b.bType == cmn.BondTypeDative {
+					return true
+				}
+			}
+			return false
+		}},
+		_StructuralKey{"multiple components", func(m *Molecule) bool {
+			return m.ComponentCount() > 1
+		}},
+	)
+
+	// Functional groups.
+	for _, n := range []int{1, 2} {
+		for _, fg := range cmn.FunctionalGroups {
+			sks = append(sks, featureKey(fg.Kind, n))
+		}
+	}
+
+	// Rings.
+	for _, n := range []int{1, 2, 3, 4} {
+		n := n
+		sks = append(sks, _StructuralKey{fmt.Sprintf("rings >= %d", n), func(m *Molecule) bool {
+			return len(m.rings) >= n
+		}})
+	}
+	for size := 3; size <= 8; size++ {
+		sks = append(sks, ringKey(fmt.Sprintf("%d-membered ring", size), 1, sizeIs(size)))
+	}
+	sks = append(sks,
+		ringKey("ring larger than 8", 1, func(r *_Ring) bool { return r.size() > 8 }),
+		ringKey("6-membered rings >= 2", 2, sizeIs(6)),
+		ringKey("aromatic ring", 1, (*_Ring).isAromatic),
+		ringKey("aromatic rings >= 2", 2, (*_Ring).isAromatic),
+		ringKey("aromatic rings >= 3", 3, (*_Ring).isAromatic),
+		ringKey("heteroaromatic ring", 1, (*_Ring).isHeteroAromatic),
+		ringKey("5-membered heteroaromatic ring", 1, func(r *_Ring) bool { return r.isHetAro && r.size() == 5 }),
+		ringKey("6-membered heteroaromatic ring", 1, func(r *_Ring) bool { return r.isHetAro && r.size() == 6 }),
+		ringKey("fused rings", 1, (*_Ring).isFused),
+		ringKey("fused aromatic ring", 1, (*_Ring).isFusedAromatic),
+		ringKey("isolated aromatic ring", 1, func(r *_Ring) bool { return r.isAro && !r.isFusedAromatic() }),
+		_StructuralKey{"ring systems >= 2", func(m *Molecule) bool {
+			return len(m.ringSystems) >= 2
+		}},
+		atomKey("atom in 3 or more rings", func(a *_Atom) bool { return a.rings.Count() >= 3 }),
+		_StructuralKey{"spiro atom", (*Molecule).hasSpiroAtom},
+	)
+	for _, z := range []uint8{7, 8, 16} {
+		z := z
+		sym := cmn.ElementSymbols[z]
+		sks = append(sks,
+			atomKey("aromatic "+sym, func(a *_Atom) bool { return a.atNum == z && a.isInAroRing }),
+			atomKey("non-aromatic ring "+sym, func(a *_Atom) bool { return a.atNum == z && a.isCyclic() && !a.isInAroRing }),
+		)
+	}
+
+	// Bonds.
+	for _, p := range [][2]uint8{{6, 6}, {6, 7}, {6, 8}, {6, 16}, {7, 7}, {7, 8}, {16, 8}, {15, 8}, {15, 16}} {
+		sks = append(sks, pathKey([]uint8{p[0], p[1]}, []cmn.BondType{double}))
+	}
+	sks = append(sks,
+		pathKey([]uint8{6, 6}, []cmn.BondType{triple}),
+		pathKey([]uint8{6, 7}, []cmn.BondType{triple}),
+	)
+	for _, p := range [][2]uint8{{6, 7}, {6, 8}, {6, 16}, {6, 9}, {6, 17}, {6, 35}, {6, 53}, {7, 7}, {7, 8}, {8, 8}, {16, 16}, {7, 16}, {8, 15}, {8, 16}, {6, 15}, {6, 14}, {6, 5}} {
+		sks = append(sks, pathKey([]uint8{p[0], p[1]}, []cmn.BondType{single}))
+	}
+
+	// Atom environments.
+	sks = append(sks,
+		atomKey("CH3", (*_Atom).isCH3),
+		atomCountKey("CH3 >= 2", 2, (*_Atom).isCH3),
+		atomCountKey("CH3 >= 3", 3, (*_Atom).isCH3),
+		atomKey("CH2", (*_Atom).isCH2),
+		atomKey("saturated CH", func(a *_Atom) bool { return a.isSaturatedC() && a.hCount == 1 }),
+		atomKey("quaternary C", func(a *_Atom) bool { return a.isSaturatedC() && a.bonds.Count() == 4 }),
+		atomKey("NH2", func(a *_Atom) bool { return a.atNum == 7 && a.hCount == 2 }),
+		atomKey("NH", func(a *_Atom) bool { return a.atNum == 7 && a.hCount == 1 }),
+		atomKey("tertiary N", func(a *_Atom) bool {
+			return a.atNum == 7 && a.hCount == 0 && !a.isInAroRing && a.singleBondCount == 3
+		}),
+		atomKey("quaternary N", func(a *_Atom) bool { return a.atNum == 7 && a.bonds.Count() == 4 }),
+		atomKey("OH", (*_Atom).isHydroxyl),
+		atomKey("SH", func(a *_Atom) bool { return a.atNum == 16 && a.hCount > 0 }),
+		atomKey("tetrahedral stereo centre candidate", (*_Atom).isTetrahedralCandidate),
+		atomKey("acyclic branch point", func(a *_Atom) bool { return !a.isCyclic() && a.isJunction() }),
+		atomKey("2 or more electron-withdrawing neighbours", func(a *_Atom) bool {
+			return a.satEwNbrCount+a.unsatEwNbrCount >= 2
+		}),
+	)
+
+	// Paths.
+	for _, zs := range [][]uint8{{8, 6, 8}, {7, 6, 7}, {7, 6, 8}, {7, 6, 16}, {8, 6, 6, 8}, {7, 6, 6, 7}, {7, 6, 6, 8}} {
+		sks = append(sks, pathKey(zs, make([]cmn.BondType, len(zs)-1)))
+	}
+	sks = append(sks,
+		pathKey([]uint8{8, 6, 6, 8}, []cmn.BondType{double, single, double}),
+		pathKey([]uint8{6, 6, 6, 8}, []cmn.BondType{double, single, double}),
+		pathKey([]uint8{6, 6, 6, 6}, []cmn.BondType{double, single, double}),
+		pathKey([]uint8{6, 6, 7}, []cmn.BondType{aro, single}),
+		pathKey([]uint8{6, 6, 8}, []cmn.BondType{aro, single}),
+		atomKey("halogen on aromatic C", func(a *_Atom) bool {
+			if !a.isHalogen() {
+				return false
+			}
+			for _, nid := range a.distinctNeighbours() {
+				if oa := a.mol.atomWithIid(nid); oa.atNum == 6 && oa.isInAroRing {
+					return true
+				}
+			}
+			return false
+		}),
+	)
+
+	return sks
+}
+
+// elementKey answers a query for at least the given number of atoms
+// of the given element.  Hydrogen atoms are counted whether or not
+// they are held as atoms.
+func elementKey(z uint8, min int) _StructuralKey {
+	name := cmn.ElementSymbols[z]
+	if min > 1 {
+		name = fmt.Sprintf("%s >= %d", name, min)
+	}
+
+	return _StructuralKey{name, func(m *Molecule) bool {
+		c := 0
+		for _, a := range m.atoms {
+			if a.atNum == z {
+				c++
+			}
+			if z == 1 {
+				c += int(a.hCount)
+			}
+		}
+		return c >= min
+	}}
+}
+
+// atomKey answers a query for an atom satisfying the given predicate.
+func atomKey(name string, f func(*_Atom) bool) _StructuralKey {
+	return atomCountKey(name, 1, f)
+}
+
+// atomCountKey answers a query for at least the given number of atoms
+// satisfying the given predicate.
+func atomCountKey(name string, min int, f func(*_Atom) bool) _StructuralKey {
+	return _StructuralKey{name, func(m *Molecule) bool {
+		c := 0
+		for _, a := range m.atoms {
+			if f(a) {
+				c++
+			}
+		}
+		return c >= min
+	}}
+}
+
+// featureKey answers a query for at least the given number of the
+// given functional group.
+func featureKey(fk cmn.FeatureKind, min int) _StructuralKey {
+	name := fk.String()
+	if min > 1 {
+		name = fmt.Sprintf("%s >= %d", name, min)
+	}
+
+	return _StructuralKey{name, func(m *Molecule) bool {
+		c := 0
+		for _, a := range m.atoms {
+			for _, f := range a.features {
+				if f == uint16(fk) {
+					c++
+				}
+			}
+		}
+		return c >= min
+	}}
+}
+
+// ringKey answers a query for at least the given number of rings
+// satisfying the given predicate.
+func ringKey(name string, min int, f func(*_Ring) bool) _StructuralKey {
+	return _StructuralKey{name, func(m *Molecule) bool {
+		c := 0
+		for _, r := range m.rings {
+			if f(r) {
+				c++
+			}
+		}
+		return c >= min
+	}}
+}
+
+// sizeIs answers a predicate for rings of the given size.
+func sizeIs(size int) func(*_Ring) bool {
+	return func(r *_Ring) bool {
+		return r.size() == size
+	}
+}
+
+// pathKey answers a query for a path of distinct atoms of the given
+// elements, bound by bonds of the given types, in order.  A bond type
+// of `BondTypeNone` matches any bond, and `BondTypeAltern` matches
+// aromatic bonds.
+func pathKey(zs []uint8, bts []cmn.BondType) _StructuralKey {
+	name := cmn.ElementSymbols[zs[0]]
+	for i, bt := range bts {
+		switch bt {
+		case cmn.BondTypeSingle:
+			name += "-"
+		case cmn.BondTypeDouble:
+			name += "="
+		case cmn.BondTypeTriple:
+			name += "#"
+		case cmn.BondTypeAltern:
+			name += ":"
+		default:
+			name += "~"
+		}
+		name += cmn.ElementSymbols[zs[i+1]]
+	}
+
+	return _StructuralKey{name, func(m *Molecule) bool {
+		for _, a := range m.atoms {
+			if a.atNum == zs[0] && m.extendsPath(a, zs[1:], bts, map[uint16]bool{a.iId: true}) {
+				return true
+			}
+		}
+		return false
+	}}
+}
+
+// extendsPath answers if a path of distinct atoms of the given
+// elements, bound by bonds of the given types, starts at a neighbour
+// of the given atom.  Atoms already on the path are given as used.
+func (m *Molecule) extendsPath(a *_Atom, zs []uint8, bts []cmn.BondType, used map[uint16]bool) bool {
+	if len(zs) == 0 {
+		return true
+	}
+
+	for bid, ok := a.bonds.NextSet(0); ok; bid, ok = a.bonds.NextSet(bid + 1) {
+		b := m.bondWithId(uint16(bid))
+		oa := m.atomWithIid(b.otherAtomIid(a.iId))
+		if used[oa.iId] || oa.atNum != zs[0] {
+			continue
+		}
+		if bts[0] != cmn.BondTypeNone && cmn.BondType(b.rankOrder()) != bts[0] {
+			continue
+		}
+
+		used[oa.iId] = true
+		if m.extendsPath(oa, zs[1:], bts[1:], used) {
+			return true
+		}
+		delete(used, oa.iId)
+	}
+
+	return false
+}
+
+// isFused answers if this ring shares at least one bond with another
+// ring.
+func (r *_Ring) isFused() bool {
+	for _, nid := range r.nbrs {
+		if r.commonBonds(r.mol.ringWithId(nid)).Count() > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasSpiroAtom answers if two rings of this molecule share exactly one
+// atom.
+func (m *Molecule) hasSpiroAtom() bool {
+	for i, r1 := range m.rings {
+		for _, r2 := range m.rings[i+1:] {
+			if r1.commonAtoms(r2).Count() == 1 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
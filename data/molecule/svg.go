@@ -0,0 +1,319 @@
+package molecule
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// SVGOptions controls the depiction of a molecule as SVG.  See
+// `RenderSVG`.
+type SVGOptions struct {
+	Width  int // Width of the image, in pixels; 300 if not given.
+	Height int // Height of the image, in pixels; 300 if not given.
+
+	// BondLength is the length, in pixels, of an average bond.  It is
+	// 30 if not given.  The depiction is shrunk, should it not fit the
+	// image otherwise.
+	BondLength float64
+
+	ShowCarbons bool // Label carbon atoms, too?
+
+	// AromaticCircles, when set, draws aromatic bonds as single bonds,
+	// with a circle inside each aromatic ring.  Otherwise, aromatic
+	// bonds are drawn with their assigned orders.
+	AromaticCircles bool
+}
+
+const (
+	svgDefaultSize       = 300
+	svgDefaultBondLength = 30.0
+	svgFontSize          = 14.0
+)
+
+// RenderSVG writes a 2-D depiction of this molecule to the given
+// output, as an SVG image.  The X and Y coordinates of the atoms are
+// used; the depiction is centred in the image, with the Y-axis
+// pointing up.
+//
+// Bonds are drawn as lines: double and triple bonds as parallel lines,
+// with the second line of a ring bond drawn inside the ring.  Bonds
+// given as aromatic, but with no assigned order, are drawn with a
+// dashed second line.  See `SVGOptions.AromaticCircles`.
+//
+// Hetero atoms are labelled with their symbols, and their attached
+// hydrogen atoms.  So are carbon atoms that carry a charge, a radical
+// or a specific isotope, or that have no bonds.  Isotopes are shown as
+// superscripts before the symbols, and charges and radicals as
+// superscripts after.
+//
+// Answers an error for negative dimensions, when the atoms have no 2-D
+// coordinates, or when writing fails.
+func (m *Molecule) RenderSVG(w io.Writer, opts SVGOptions) error {
+	if opts.Width < 0 || opts.Height < 0 || opts.BondLength < 0 {
+		return fmt.Errorf("Invalid SVG dimensions given : %dx%d, bond length %.2f", opts.Width, opts.Height, opts.BondLength)
+	}
+	if opts.Width == 0 {
+		opts.Width = svgDefaultSize
+	}
+	if opts.Height == 0 {
+		opts.Height = svgDefaultSize
+	}
+	if opts.BondLength == 0 {
+		opts.BondLength = svgDefaultBondLength
+	}
+
+	min, max := m.BoundingBox()
+	if len(m.atoms) > 1 && min[0] == max[0] && min[1] == max[1] {
+		return fmt.Errorf("Molecule %d has no 2-D coordinates.", m.id)
+	}
+
+	d := m.newSVGDepiction(opts, min, max)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		opts.Width, opts.Height, opts.Width, opts.Height)
+	fmt.Fprintf(bw, "<rect width=\"100%%\" height=\"100%%\" fill=\"white\"/>\n")
+
+	fmt.Fprintf(bw, "<g stroke=\"black\" stroke-width=\"1.5\" stroke-linecap=\"round\" fill=\"none\">\n")
+	for _, b := range m.bonds {
+		d.writeBond(bw, b)
+	}
+	if opts.AromaticCircles {
+		for _, r := range m.rings {
+			if r.isAro {
+				d.writeRingCircle(bw, r)
+			}
+		}
+	}
+	fmt.Fprintf(bw, "</g>\n")
+
+	fmt.Fprintf(bw, "<g font-family=\"sans-serif\" font-size=\"%.0f\" text-anchor=\"middle\" dominant-baseline=\"central\">\n", svgFontSize)
+	for _, a := range m.atoms {
+		if d.labelled[a.iId] {
+			d.writeLabel(bw, a)
+		}
+	}
+	fmt.Fprintf(bw, "</g>\n")
+	fmt.Fprintf(bw, "</svg>\n")
+
+	return bw.Flush()
+}
+
+// _SVGDepiction holds the state of a depiction being rendered: the
+// mapping of atom coordinates to the image, and the atoms labelled.
+type _SVGDepiction struct {
+	mol             *Molecule
+	scale           float64         // Pixels per unit of atom coordinates.
+	cx, cy          float64         // Centre of the atoms, in atom coordinates.
+	w, h            float64         // Size of the image, in pixels.
+	bl              float64         // Length of an average bond, in pixels.
+	labelled        map[uint16]bool // Atoms labelled, by input ID.
+	aromaticCircles bool            // Draw aromatic rings with circles?
+}
+
+// newSVGDepiction answers a depiction of this molecule, for the given
+// options and bounding box of the atoms.
+func (m *Molecule) newSVGDepiction(opts SVGOptions, min, max [3]float32) *_SVGDepiction {
+	d := &_SVGDepiction{mol: m, w: float64(opts.Width), h: float64(opts.Height), aromaticCircles: opts.AromaticCircles}
+	d.cx = float64(min[0]+max[0]) / 2
+	d.cy = float64(min[1]+max[1]) / 2
+
+	avg := 0.0
+	for _, b := range m.bonds {
+		a1, a2 := m.atomWithIid(b.a1), m.atomWithIid(b.a2)
+		avg += math.Hypot(float64(a1.X-a2.X), float64(a1.Y-a2.Y))
+	}
+	if len(m.bonds) > 0 {
+		avg /= float64(len(m.bonds))
+	}
+	if avg == 0 {
+		avg = 1
+	}
+
+	d.scale = opts.BondLength / avg
+	margin := 2 * svgFontSize
+	for _, fit := range [][2]float64{{d.w, float64(max[0] - min[0])}, {d.h, float64(max[1] - min[1])}} {
+		if fit[1] > 0 && fit[0] > 2*margin {
+			if s := (fit[0] - 2*margin) / fit[1]; s < d.scale {
+				d.scale = s
+			}
+		}
+	}
+	d.bl = avg * d.scale
+
+	d.labelled = make(map[uint16]bool, len(m.atoms))
+	for _, a := range m.atoms {
+		d.labelled[a.iId] = a.atNum != 6 || opts.ShowCarbons || a.charge != 0 ||
+			a.radical != cmn.RadicalNone || a.isotope != 0 || a.bonds.Count() == 0
+	}
+
+	return d
+}
+
+// point answers the position of the given atom in the image.
+func (d *_SVGDepiction) point(a *_Atom) (float64, float64) {
+	return d.w/2 + (float64(a.X)-d.cx)*d.scale, d.h/2 - (float64(a.Y)-d.cy)*d.scale
+}
+
+// writeBond draws the given bond.
+func (d *_SVGDepiction) writeBond(w io.Writer, b *_Bond) {
+	mol := d.mol
+	a1, a2 := mol.atomWithIid(b.a1), mol.atomWithIid(b.a2)
+	x1, y1 := d.point(a1)
+	x2, y2 := d.point(a2)
+	dx, dy := x2-x1, y2-y1
+	l := math.Hypot(dx, dy)
+	if l == 0 {
+		return
+	}
+	ux, uy := dx/l, dy/l
+
+	// Keep the lines clear of the labels.
+	gap := 0.6 * svgFontSize
+	if d.labelled[a1.iId] {
+		x1, y1 = x1+ux*gap, y1+uy*gap
+	}
+	if d.labelled[a2.iId] {
+		x2, y2 = x2-ux*gap, y2-uy*gap
+	}
+
+	// Unit normal, and the spacing of parallel lines.
+	nx, ny := -uy, ux
+	off := 0.18 * d.bl
+
+	order := b.order()
+	if b.isAro && d.aromaticCircles {
+		order = 1
+	}
+
+	switch {
+	case b.bType == cmn.BondTypeAltern:
+		writeSVGLine(w, x1, y1, x2, y2, "")
+		d.writeInnerLine(w, b, x1, y1, x2, y2, nx, ny, off, " stroke-dasharray=\"3,3\"")
+	case order == 2 && b.isCyclic():
+		writeSVGLine(w, x1, y1, x2, y2, "")
+		d.writeInnerLine(w, b, x1, y1, x2, y2, nx, ny, off, "")
+	case order == 2:
+		h := off / 2
+		writeSVGLine(w, x1+nx*h, y1+ny*h, x2+nx*h, y2+ny*h, "")
+		writeSVGLine(w, x1-nx*h, y1-ny*h, x2-nx*h, y2-ny*h, "")
+	case order == 3:
+		writeSVGLine(w, x1, y1, x2, y2, "")
+		writeSVGLine(w, x1+nx*off, y1+ny*off, x2+nx*off, y2+ny*off, "")
+		writeSVGLine(w, x1-nx*off, y1-ny*off, x2-nx*off, y2-ny*off, "")
+	default:
+		writeSVGLine(w, x1, y1, x2, y2, "")
+	}
+}
+
+// writeInnerLine draws the second line of the given ring bond, whose
+// first line runs between the given points, inside its smallest ring.
+// The line is shortened at either end, as is conventional.
+func (d *_SVGDepiction) writeInnerLine(w io.Writer, b *_Bond, x1, y1, x2, y2, nx, ny, off float64, attrs string) {
+	if len(b.rings) > 0 {
+		rx, ry := d.ringCentre(d.smallestRingOf(b))
+		mx, my := (x1+x2)/2, (y1+y2)/2
+		if (rx-mx)*nx+(ry-my)*ny < 0 {
+			nx, ny = -nx, -ny
+		}
+	}
+
+	sx, sy := (x2-x1)*0.15, (y2-y1)*0.15
+	writeSVGLine(w, x1+sx+nx*off, y1+sy+ny*off, x2-sx+nx*off, y2-sy+ny*off, attrs)
+}
+
+// smallestRingOf answers the smallest ring in which the given bond
+// participates.  Of rings of equal size, the one with the lowest ID
+// is answered.
+func (d *_SVGDepiction) smallestRingOf(b *_Bond) *_Ring {
+	var best *_Ring
+	for _, rid := range b.rings {
+		r := d.mol.ringWithId(rid)
+		if best == nil || r.size() < best.size() || (r.size() == best.size() && r.id < best.id) {
+			best = r
+		}
+	}
+
+	return best
+}
+
+// ringCentre answers the centre of the given ring, in the image.
+func (d *_SVGDepiction) ringCentre(r *_Ring) (float64, float64) {
+	var sx, sy float64
+	for _, aid := range r.atoms {
+		x, y := d.point(d.mol.atomWithIid(aid))
+		sx += x
+		sy += y
+	}
+
+	n := float64(len(r.atoms))
+	return sx / n, sy / n
+}
+
+// writeRingCircle draws a circle inside the given aromatic ring.
+func (d *_SVGDepiction) writeRingCircle(w io.Writer, r *_Ring) {
+	cx, cy := d.ringCentre(r)
+
+	rad := 0.0
+	for _, aid := range r.atoms {
+		x, y := d.point(d.mol.atomWithIid(aid))
+		rad += math.Hypot(x-cx, y-cy)
+	}
+	rad = 0.6 * rad / float64(len(r.atoms))
+
+	fmt.Fprintf(w, "<circle cx=\"%.2f\" cy=\"%.2f\" r=\"%.2f\"/>\n", cx, cy, rad)
+}
+
+// writeLabel draws the label of the given atom: its symbol, attached
+// hydrogen atoms, isotope, charge and radical electrons.
+func (d *_SVGDepiction) writeLabel(w io.Writer, a *_Atom) {
+	x, y := d.point(a)
+
+	var sb strings.Builder
+	if a.isotope != 0 {
+		writeSVGSuperscript(&sb, strconv.Itoa(int(a.isotope)))
+	}
+	sb.WriteString(a.symbol)
+	if a.hCount > 0 {
+		sb.WriteString("H")
+		if a.hCount > 1 {
+			fmt.Fprintf(&sb, "<tspan baseline-shift=\"sub\" font-size=\"%.0f\">%d</tspan>", 0.7*svgFontSize, a.hCount)
+		}
+	}
+
+	sup := ""
+	switch {
+	case a.charge == 1:
+		sup = "+"
+	case a.charge == -1:
+		sup = "−"
+	case a.charge > 1:
+		sup = strconv.Itoa(int(a.charge)) + "+"
+	case a.charge < -1:
+		sup = strconv.Itoa(-int(a.charge)) + "−"
+	}
+	sup += strings.Repeat("•", a.radicalElectronCount())
+	if sup != "" {
+		writeSVGSuperscript(&sb, sup)
+	}
+
+	fmt.Fprintf(w, "<text x=\"%.2f\" y=\"%.2f\">%s</text>\n", x, y, sb.String())
+}
+
+// writeSVGLine draws a line between the given points, with the given
+// additional attributes.
+func writeSVGLine(w io.Writer, x1, y1, x2, y2 float64, attrs string) {
+	fmt.Fprintf(w, "<line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\"%s/>\n", x1, y1, x2, y2, attrs)
+}
+
+// writeSVGSuperscript writes the given text as a superscript.
+func writeSVGSuperscript(sb *strings.Builder, s string) {
+	fmt.Fprintf(sb, "<tspan baseline-shift=\"super\" font-size=\"%.0f\">%s</tspan>", 0.7*svgFontSize, s)
+}
@@ -0,0 +1,88 @@
+package molecule
+
+import (
+	"sort"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// CanonicalTautomer answers a new molecule that is the canonical
+// tautomer of this one.  This molecule itself is left unchanged.
+//
+// The mobile hydrogen systems handled are keto-enol and
+// amide-imidol.  Their canonical forms are the keto and the amide
+// forms, respectively: a hydroxyl on a carbon atom that is
+// doubly-bonded to a carbon or a nitrogen atom is converted into a
+// carbonyl, with its hydrogen moving to the other end of the double
+// bond.  This is repeated until no enol or imidol remains, so that
+// conjugated systems - as in the enol form of a 1,3-diketone - reach
+// the same form as their keto tautomers.  Aromatic bonds do not take
+// part; phenols, therefore, remain as they are.
+//
+// The new molecule is constructed as described in `SplitComponents`,
+// and is normalised.
+func (m *Molecule) CanonicalTautomer() (*Molecule, error) {
+	iIds := make([]uint16, 0, len(m.atoms))
+	for _, a := range m.atoms {
+		iIds = append(iIds, a.iId)
+	}
+	sort.Sort(_Uint16s(iIds))
+
+	t, err := m.fragment(iIds)
+	if err != nil {
+		return nil, err
+	}
+	t.Unfreeze()
+
+	shifted := false
+	for t.shiftEnolicHydrogen() {
+		shifted = true
+	}
+	if !shifted {
+		t.Freeze()
+		return t, nil
+	}
+
+	if err := t.Normalise(); err != nil {
+		t.discard()
+		return nil, err
+	}
+	return t, nil
+}
+
+// shiftEnolicHydrogen converts the first enol or imidol found in this
+// molecule into its keto or amide form, respectively.  See
+// `CanonicalTautomer`.
+//
+// Answers `true` if a hydrogen was moved; `false` otherwise.
+func (m *Molecule) shiftEnolicHydrogen() bool {
+	for _, o := range m.atoms {
+		if !o.isHydroxyl() || o.charge != 0 || o.bonds.Count() != 1 {
+			continue
+		}
+
+		bid, _ := o.bonds.NextSet(0)
+		ob := m.bondWithId(uint16(bid))
+		c := m.atomWithIid(ob.otherAtomIid(o.iId))
+		if ob.isAro || c.atNum != 6 || c.charge != 0 || c.doubleBondCount != 1 {
+			continue
+		}
+
+		xid, db := c.firstDoublyBondedNeighbourId()
+		if db == nil {
+			continue
+		}
+		x := m.atomWithIid(xid)
+		if db.isAro || (x.atNum != 6 && x.atNum != 7) {
+			continue
+		}
+
+		m.setBondType(db, cmn.BondTypeSingle)
+		m.setBondType(ob, cmn.BondTypeDouble)
+		o.hCount--
+		x.hCount++
+		return true
+	}
+
+	return false
+}
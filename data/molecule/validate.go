@@ -0,0 +1,140 @@
+package molecule
+
+import (
+	"fmt"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// IssueCode identifies the kind of a problem found when validating a
+// molecule.  See `Validate`.
+type IssueCode string
+
+const (
+	// Rings or aromaticity could not be determined.
+	IssuePerception IssueCode = "perception"
+	// An atom's bonds, hydrogens and unpaired electrons do not add up
+	// to a valid valence of its element.
+	IssueValence IssueCode = "valence"
+	// An atom has more connections than `cmn.MaxBonds`.
+	IssueTooManyBonds IssueCode = "too-many-bonds"
+	// The molecule comprises more than one connected component.
+	IssueDisconnected IssueCode = "disconnected"
+	// A bond is marked with stereo that cannot be determined.
+	IssueUndefinedStereo IssueCode = "undefined-stereo"
+	// A charged radical's valence does not match its charge.
+	IssueRadicalCharge IssueCode = "radical-charge"
+	// Two atoms have the same coordinates.
+	IssueOverlappingAtoms IssueCode = "overlapping-atoms"
+	// All the atoms are at the origin; the molecule has no coordinates.
+	IssueNoCoordinates IssueCode = "no-coordinates"
+)
+
+// ValidationIssue describes a problem found when validating a
+// molecule.
+type ValidationIssue struct {
+	Severity cmn.Severity // How serious the problem is.
+	Code     IssueCode    // Kind of the problem.
+	Atoms    []uint16     // Input IDs of the atoms concerned, if any.
+	Bonds    []uint16     // IDs of the bonds concerned, if any.
+	Message  string       // Human-readable description.
+}
+
+// Validate answers the problems found in this molecule, all of them,
+// in the order of the checks below.  An empty list means that no
+// problem was found.
+//
+//   - Errors: atoms with an invalid valence; rings or aromaticity that
+//     cannot be determined otherwise; atoms with more than
+//     `cmn.MaxBonds` connections.
+//   - Warnings: more than one connected component; wedge bonds not
+//     starting at a tetrahedral stereo centre, and bonds marked with
+//     `either' stereo; charged radicals whose valence does not match
+//     their charge; atoms with the same coordinates, or all atoms at
+//     the origin.
+//
+// Stale rings and aromaticity are determined afresh.  This molecule
+// is not otherwise modified.
+func (m *Molecule) Validate() []ValidationIssue {
+	issues := make([]ValidationIssue, 0, cmn.ListSizeTiny)
+	add := func(sev cmn.Severity, code IssueCode, atoms, bonds []uint16, format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{sev, code, atoms, bonds, fmt.Sprintf(format, args...)})
+	}
+
+	for _, a := range m.atoms {
+		if err := a.checkValence(); err != nil {
+			add(cmn.SeverityError, IssueValence, []uint16{a.iId}, nil, "Atom %d (%s) : %v", a.iId, a.symbol, err)
+		}
+	}
+	// Invalid valences also defeat perception; that is not reported
+	// again.
+	if err := m.refresh(stageAromaticity); err != nil && len(issues) == 0 {
+		add(cmn.SeverityError, IssuePerception, nil, nil, "%v", err)
+	}
+
+	for _, a := range m.atoms {
+		if n := int(a.bonds.Count()) + int(a.hCount); n > cmn.MaxBonds {
+			add(cmn.SeverityError, IssueTooManyBonds, []uint16{a.iId}, nil, "Atom %d (%s) has %d connections; at most %d are allowed.", a.iId, a.symbol, n, cmn.MaxBonds)
+		}
+	}
+
+	if n := m.ComponentCount(); n > 1 {
+		add(cmn.SeverityWarning, IssueDisconnected, nil, nil, "Molecule %d has %d disconnected fragments.", m.id, n)
+	}
+
+	for _, b := range m.bonds {
+		switch b.bStereo {
+		case cmn.BondStereoUp, cmn.BondStereoDown:
+			a := m.atomWithIid(b.a1)
+			if _, ok := a.rankedNeighbours(); !a.isTetrahedralCandidate() || !ok {
+				add(cmn.SeverityWarning, IssueUndefinedStereo, []uint16{a.iId}, []uint16{b.id}, "Wedge bond %d does not start at a stereo centre.", b.id)
+			}
+		case cmn.BondStereoEither, cmn.BondStereoDoubleEither:
+			add(cmn.SeverityWarning, IssueUndefinedStereo, []uint16{b.a1, b.a2}, []uint16{b.id}, "Bond %d has undefined stereo.", b.id)
+		}
+	}
+
+	for _, a := range m.atoms {
+		if a.radical == cmn.RadicalNone || a.formalCharge() == 0 {
+			continue
+		}
+		if v := len(a.nbrs) + int(a.hCount) + a.radicalElectronCount(); v != a.chargedValence() {
+			add(cmn.SeverityWarning, IssueRadicalCharge, []uint16{a.iId}, nil, "Atom %d (%s) has valence %d; %d is expected with charge %+d.", a.iId, a.symbol, v, a.chargedValence(), a.formalCharge())
+		}
+	}
+
+	m.validateCoordinates(add)
+
+	return issues
+}
+
+// validateCoordinates reports atoms of this molecule sharing the same
+// coordinates, using the given function.  When all the atoms are at
+// the origin, a single issue is reported instead.  See `Validate`.
+func (m *Molecule) validateCoordinates(add func(cmn.Severity, IssueCode, []uint16, []uint16, string, ...interface{})) {
+	if len(m.atoms) < 2 {
+		return
+	}
+
+	atOrigin := true
+	for _, a := range m.atoms {
+		if a.X != 0 || a.Y != 0 || a.Z != 0 {
+			atOrigin = false
+			break
+		}
+	}
+	if atOrigin {
+		add(cmn.SeverityWarning, IssueNoCoordinates, nil, nil, "Molecule %d has no coordinates.", m.id)
+		return
+	}
+
+	first := make(map[[3]float32]uint16, len(m.atoms))
+	for _, a := range m.atoms {
+		c := [3]float32{a.X, a.Y, a.Z}
+		if oid, ok := first[c]; ok {
+			add(cmn.SeverityWarning, IssueOverlappingAtoms, []uint16{oid, a.iId}, nil, "Atoms %d and %d have the same coordinates.", oid, a.iId)
+			continue
+		}
+		first[c] = a.iId
+	}
+}
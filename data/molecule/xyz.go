@@ -0,0 +1,147 @@
+package molecule
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// XYZReader reads a molecule from an XYZ file.
+//
+// An XYZ file lists the element and the Cartesian coordinates of each
+// atom, with no connectivity information.  The first line gives the
+// number of atoms, and the second is a free-form comment, which is
+// ignored.  Only the first frame of a multi-frame file is read.
+type XYZReader struct {
+	// PerceiveBonds, when set, makes the reader create bonds between
+	// the atoms read, using `PerceiveBondsFromCoordinates`.  Plain
+	// hydrogen atoms bonded to heavy atoms are then folded into the
+	// hydrogen counts of those atoms, and removed; the molecule
+	// answered - and any XYZ file written from it - therefore has
+	// fewer atoms than were read.  It is not set by default.
+	PerceiveBonds bool
+
+	r io.Reader
+}
+
+// NewXYZReader answers a new reader of an XYZ molecule from the given
+// input.
+func NewXYZReader(r io.Reader) *XYZReader {
+	return &XYZReader{r: r}
+}
+
+// ReadXYZ reads the molecule in the given XYZ input.  The molecule has
+// atoms with coordinates, but no bonds.  Use an `XYZReader` to
+// perceive bonds as well.
+func ReadXYZ(r io.Reader) (*Molecule, error) {
+	return NewXYZReader(r).Read()
+}
+
+// Read reads the molecule in the input of this reader.
+func (xr *XYZReader) Read() (*Molecule, error) {
+	sc := bufio.NewScanner(xr.r)
+	lineNo := 0
+	next := func() (string, bool) {
+		if !sc.Scan() {
+			return "", false
+		}
+		lineNo++
+		return strings.TrimSpace(sc.Text()), true
+	}
+	lerr := func(err error) error {
+		return &_ParseError{lineNo, err}
+	}
+
+	line, ok := next()
+	if !ok {
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	n, err := strconv.Atoi(line)
+	if err != nil || n < 0 {
+		return nil, lerr(fmt.Errorf("Invalid atom count : %s", line))
+	}
+	if _, ok := next(); !ok {
+		return nil, lerr(fmt.Errorf("Missing comment line."))
+	}
+
+	m := New()
+	ab := m.NewAtomBuilder()
+	for i := 1; i <= n; i++ {
+		line, ok := next()
+		if !ok {
+			m.discard()
+			return nil, lerr(fmt.Errorf("Expected %d atoms, found %d.", n, i-1))
+		}
+		if err := parseXYZAtom(ab, line, i); err != nil {
+			m.discard()
+			return nil, lerr(err)
+		}
+	}
+
+	if xr.PerceiveBonds {
+		if err := m.PerceiveBondsFromCoordinates(); err != nil {
+			m.discard()
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// parseXYZAtom builds the atom described by the given line of an XYZ
+// file, and adds it to the builder's molecule.  The element may be
+// given either as its symbol or as its atomic number.
+func parseXYZAtom(ab *AtomBuilder, line string, iId int) error {
+	fs := strings.Fields(line)
+	if len(fs) < 4 {
+		return fmt.Errorf("Expected an element and three coordinates : %s", line)
+	}
+
+	var cs [3]float32
+	for i := range cs {
+		f, err := strconv.ParseFloat(fs[i+1], 32)
+		if err != nil {
+			return fmt.Errorf("Invalid coordinate : %v", err)
+		}
+		cs[i] = float32(f)
+	}
+
+	sym := fs[0]
+	if atNum, err := strconv.Atoi(sym); err == nil {
+		if atNum < 1 || atNum >= len(cmn.ElementSymbols) {
+			return fmt.Errorf("Unknown atomic number : %d", atNum)
+		}
+		sym = cmn.ElementSymbols[atNum]
+	} else {
+		sym = strings.ToUpper(sym[:1]) + strings.ToLower(sym[1:])
+	}
+
+	if _, err := ab.New(sym, iId); err != nil {
+		return err
+	}
+	ab.Coordinates(cs[0], cs[1], cs[2])
+
+	_, err := ab.Build()
+	return err
+}
+
+// WriteXYZ writes this molecule to the given output in XYZ format.
+// Only the atoms held by this molecule are written; implicit hydrogen
+// atoms have no coordinates, and are hence omitted.  The comment line
+// carries the supplier ID of this molecule, if set.
+func (m *Molecule) WriteXYZ(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "%d\n%s\n", len(m.atoms), m.vendorMoleculeId)
+	for _, a := range m.atoms {
+		fmt.Fprintf(bw, "%-2s %12.6f %12.6f %12.6f\n", a.symbol, a.X, a.Y, a.Z)
+	}
+
+	return bw.Flush()
+}
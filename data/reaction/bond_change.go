@@ -0,0 +1,137 @@
+package reaction
+
+import (
+	"fmt"
+	"sort"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+	"github.com/RxnWeaver/RxnWeaver/data/molecule"
+)
+
+// BondChange describes the change in the bond between two mapped
+// atoms, across a reaction.
+type BondChange struct {
+	MapNum1 uint16 // Smaller of the map numbers of the two atoms.
+	MapNum2 uint16 // Larger of the map numbers of the two atoms.
+
+	Before cmn.BondType // Bond type among reactants; `BondTypeNone` if absent.
+	After  cmn.BondType // Bond type among products; `BondTypeNone` if absent.
+}
+
+// _MapPair identifies a bond by the map numbers of its atoms, the
+// smaller first.
+type _MapPair [2]uint16
+
+// _BondChanges orders bond changes by the map numbers of their atoms.
+type _BondChanges []BondChange
+
+func (bcs _BondChanges) Len() int {
+	return len(bcs)
+}
+
+func (bcs _BondChanges) Swap(i, j int) {
+	bcs[i], bcs[j] = bcs[j], bcs[i]
+}
+
+func (bcs _BondChanges) Less(i, j int) bool {
+	if bcs[i].MapNum1 != bcs[j].MapNum1 {
+		return bcs[i].MapNum1 < bcs[j].MapNum1
+	}
+	return bcs[i].MapNum2 < bcs[j].MapNum2
+}
+
+// BondChanges answers the bonds formed and broken by this reaction,
+// judging by the atom-atom mapping of its reactants and products.
+//
+// A bond is formed when it is absent among the reactants, or its order
+// is raised.  A bond is broken when it is absent among the products,
+// or its order is lowered.  Aromatic bonds present on both sides are
+// not considered changed, irrespective of their Kekulé structures.
+// Orders are compared as in valence checks, where a dative bond counts
+// as a single bond.  See `molecule.Bond.Order`.  A single bond that
+// becomes dative - or the reverse - only separates charges; it is
+// neither formed nor broken.
+// Since hydrogen atoms are held as counts, bonds to them are not
+// reported.  Both lists are ordered by the map numbers of the atoms.
+//
+// Every atom of every reactant and product should carry a map number,
+// which is unique on its side of the reaction.  The same map numbers
+// should occur on both sides.  Answers an error otherwise.
+func (rxn *Reaction) BondChanges() (formed, broken []BondChange, err error) {
+	rMaps, rBonds, err := mappedBonds(rxn.reactants, "reactants")
+	if err != nil {
+		return nil, nil, err
+	}
+	pMaps, pBonds, err := mappedBonds(rxn.products, "products")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for mn := range rMaps {
+		if !pMaps[mn] {
+			return nil, nil, fmt.Errorf("Map number %d occurs only among reactants.", mn)
+		}
+	}
+	for mn := range pMaps {
+		if !rMaps[mn] {
+			return nil, nil, fmt.Errorf("Map number %d occurs only among products.", mn)
+		}
+	}
+
+	formed = make([]BondChange, 0, cmn.ListSizeTiny)
+	broken = make([]BondChange, 0, cmn.ListSizeTiny)
+	for mp, rb := range rBonds {
+		pb, ok := pBonds[mp]
+		switch {
+		case !ok:
+			broken = append(broken, BondChange{mp[0], mp[1], rb.Type(), cmn.BondTypeNone})
+		case rb.IsAromatic() && pb.IsAromatic():
+		case pb.Order() > rb.Order():
+			formed = append(formed, BondChange{mp[0], mp[1], rb.Type(), pb.Type()})
+		case pb.Order() < rb.Order():
+			broken = append(broken, BondChange{mp[0], mp[1], rb.Type(), pb.Type()})
+		}
+	}
+	for mp, pb := range pBonds {
+		if _, ok := rBonds[mp]; !ok {
+			formed = append(formed, BondChange{mp[0], mp[1], cmn.BondTypeNone, pb.Type()})
+		}
+	}
+
+	sort.Sort(_BondChanges(formed))
+	sort.Sort(_BondChanges(broken))
+	return formed, broken, nil
+}
+
+// mappedBonds answers the map numbers of the atoms of the given
+// molecules, and their bonds keyed by the map numbers of their atoms.
+// The given side name is used in errors.
+func mappedBonds(ms []*molecule.Molecule, side string) (map[uint16]bool, map[_MapPair]molecule.Bond, error) {
+	maps := make(map[uint16]bool)
+	bonds := make(map[_MapPair]molecule.Bond)
+	for _, m := range ms {
+		mns := make(map[uint16]uint16)
+		for _, a := range m.Atoms() {
+			mn := a.MapNumber()
+			if mn == 0 {
+				return nil, nil, fmt.Errorf("Atom %d of molecule %d among %s is not mapped.", a.InputId(), m.Id(), side)
+			}
+			if maps[mn] {
+				return nil, nil, fmt.Errorf("Map number %d is repeated among %s.", mn, side)
+			}
+			maps[mn] = true
+			mns[a.InputId()] = mn
+		}
+
+		for _, b := range m.Bonds() {
+			a1, a2 := b.Atoms()
+			mn1, mn2 := mns[a1], mns[a2]
+			if mn1 > mn2 {
+				mn1, mn2 = mn2, mn1
+			}
+			bonds[_MapPair{mn1, mn2}] = b
+		}
+	}
+
+	return maps, bonds, nil
+}
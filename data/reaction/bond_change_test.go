@@ -0,0 +1,149 @@
+package reaction
+
+import (
+	"strings"
+	"testing"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+)
+
+// esterificationRXN is the esterification of acetic acid by methanol.
+// The hydroxyl oxygen of the acid leaves as water, and the methoxy
+// oxygen of the ester comes from the alcohol.
+const esterificationRXN = `$RXN
+esterification
+  test
+
+  2  2
+$MOL
+acetic acid
+  test
+
+  4  3  0  0  0  0  0  0  0  0999 V2000
+    0.0000    0.0000    0.0000 C   0  0  0  0  0  0  0  0  0  1  0  0
+    1.2990    0.7500    0.0000 C   0  0  0  0  0  0  0  0  0  2  0  0
+    2.5980    0.0000    0.0000 O   0  0  0  0  0  0  0  0  0  3  0  0
+    3.8970    0.7500    0.0000 O   0  0  0  0  0  0  0  0  0  4  0  0
+  1  2  1  0
+  2  3  2  0
+  2  4  1  0
+M  END
+$MOL
+methanol
+  test
+
+  2  1  0  0  0  0  0  0  0  0999 V2000
+    0.0000    0.0000    0.0000 C   0  0  0  0  0  0  0  0  0  5  0  0
+    1.2990    0.7500    0.0000 O   0  0  0  0  0  0  0  0  0  6  0  0
+  1  2  1  0
+M  END
+$MOL
+methyl acetate
+  test
+
+  5  4  0  0  0  0  0  0  0  0999 V2000
+    0.0000    0.0000    0.0000 C   0  0  0  0  0  0  0  0  0  1  0  0
+    1.2990    0.7500    0.0000 C   0  0  0  0  0  0  0  0  0  2  0  0
+    2.5980    0.0000    0.0000 O   0  0  0  0  0  0  0  0  0  3  0  0
+    3.8970    0.7500    0.0000 O   0  0  0  0  0  0  0  0  0  6  0  0
+    5.1960    0.0000    0.0000 C   0  0  0  0  0  0  0  0  0  5  0  0
+  1  2  1  0
+  2  3  2  0
+  2  4  1  0
+  4  5  1  0
+M  END
+$MOL
+water
+  test
+
+  1  0  0  0  0  0  0  0  0  0999 V2000
+    0.0000    0.0000    0.0000 O   0  0  0  0  0  0  0  0  0  4  0  0
+M  END
+`
+
+// amineOxideRXN redraws trimethylamine N-oxide from its
+// charge-separated form to its dative form.
+const amineOxideRXN = `$RXN
+amine oxide
+  test
+
+  1  1
+$MOL
+trimethylamine N-oxide
+  test
+
+  5  4  0  0  0  0  0  0  0  0999 V2000
+    0.0000    0.0000    0.0000 C   0  0  0  0  0  0  0  0  0  1  0  0
+    1.2990    0.7500    0.0000 C   0  0  0  0  0  0  0  0  0  2  0  0
+    2.5980    0.0000    0.0000 C   0  0  0  0  0  0  0  0  0  3  0  0
+    3.8970    0.7500    0.0000 N   0  3  0  0  0  0  0  0  0  4  0  0
+    5.1960    0.0000    0.0000 O   0  5  0  0  0  0  0  0  0  5  0  0
+  1  4  1  0
+  2  4  1  0
+  3  4  1  0
+  4  5  1  0
+M  END
+$MOL
+trimethylamine N-oxide
+  test
+
+  5  4  0  0  0  0  0  0  0  0999 V2000
+    0.0000    0.0000    0.0000 C   0  0  0  0  0  0  0  0  0  1  0  0
+    1.2990    0.7500    0.0000 C   0  0  0  0  0  0  0  0  0  2  0  0
+    2.5980    0.0000    0.0000 C   0  0  0  0  0  0  0  0  0  3  0  0
+    3.8970    0.7500    0.0000 N   0  0  0  0  0  0  0  0  0  4  0  0
+    5.1960    0.0000    0.0000 O   0  0  0  0  0  0  0  0  0  5  0  0
+  1  4  1  0
+  2  4  1  0
+  3  4  1  0
+  4  5  9  0
+M  END
+`
+
+func TestBondChangesEsterification(t *testing.T) {
+	rxn, err := ReadRXN(strings.NewReader(esterificationRXN))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	formed, broken, err := rxn.BondChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(formed) != 1 || formed[0] != (BondChange{2, 6, cmn.BondTypeNone, cmn.BondTypeSingle}) {
+		t.Errorf("Expected only the acyl C-O bond to the alcohol formed, found : %v", formed)
+	}
+	if len(broken) != 1 || broken[0] != (BondChange{2, 4, cmn.BondTypeSingle, cmn.BondTypeNone}) {
+		t.Errorf("Expected only the acyl C-OH bond broken, found : %v", broken)
+	}
+}
+
+func TestBondChangesDative(t *testing.T) {
+	rxn, err := ReadRXN(strings.NewReader(amineOxideRXN))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	formed, broken, err := rxn.BondChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(formed) != 0 || len(broken) != 0 {
+		t.Errorf("Expected no bond changes between single and dative forms, found formed : %v, broken : %v", formed, broken)
+	}
+}
+
+func TestBondChangesUnmapped(t *testing.T) {
+	lines := strings.Split(esterificationRXN, "\n")
+	// Drop the map number of the water oxygen.
+	last := len(lines) - 3
+	lines[last] = lines[last][:60] + "  0" + lines[last][63:]
+
+	rxn, err := parseRXN(lines, cmn.HydrogenPolicyFill)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := rxn.BondChanges(); err == nil {
+		t.Error("Expected an error for an unmapped atom.")
+	}
+}
@@ -0,0 +1,161 @@
+// Package reaction relates the molecules that participate in a
+// chemical reaction.
+package reaction
+
+import (
+	"fmt"
+	"sort"
+	"unicode"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+	"github.com/RxnWeaver/RxnWeaver/data/molecule"
+)
+
+// Reaction represents a chemical reaction, as the lists of its
+// reactant and product molecules.
+//
+// The molecules are only referred to; they are not copied.  A
+// molecule appearing more than once on a side - as in a
+// stoichiometric coefficient - should be added as many times.
+type Reaction struct {
+	reactants []*molecule.Molecule // Molecules consumed by this reaction.
+	products  []*molecule.Molecule // Molecules produced by this reaction.
+}
+
+// NewReaction creates and initialises a reaction with no reactants or
+// products.
+func NewReaction() *Reaction {
+	rxn := new(Reaction)
+	rxn.reactants = make([]*molecule.Molecule, 0, cmn.ListSizeTiny)
+	rxn.products = make([]*molecule.Molecule, 0, cmn.ListSizeTiny)
+	return rxn
+}
+
+// AddReactant adds the given molecule to the reactants of this
+// reaction.
+func (rxn *Reaction) AddReactant(m *molecule.Molecule) error {
+	if m == nil {
+		return fmt.Errorf("Reactant should not be nil.")
+	}
+
+	rxn.reactants = append(rxn.reactants, m)
+	return nil
+}
+
+// AddProduct adds the given molecule to the products of this
+// reaction.
+func (rxn *Reaction) AddProduct(m *molecule.Molecule) error {
+	if m == nil {
+		return fmt.Errorf("Product should not be nil.")
+	}
+
+	rxn.products = append(rxn.products, m)
+	return nil
+}
+
+// Reactants answers the reactant molecules of this reaction, in the
+// order in which they were added.
+func (rxn *Reaction) Reactants() []*molecule.Molecule {
+	ms := make([]*molecule.Molecule, len(rxn.reactants))
+	copy(ms, rxn.reactants)
+	return ms
+}
+
+// Products answers the product molecules of this reaction, in the
+// order in which they were added.
+func (rxn *Reaction) Products() []*molecule.Molecule {
+	ms := make([]*molecule.Molecule, len(rxn.products))
+	copy(ms, rxn.products)
+	return ms
+}
+
+// BalanceCheck verifies that this reaction conserves atoms: that each
+// element occurs as many times amongst its reactants as it does
+// amongst its products.  The molecular formulae of the molecules are
+// used for the purpose; hence, hydrogen atoms are counted, while
+// charges are not.
+//
+// Answers an error naming the first unbalanced element, in alphabetical
+// order, if atoms are not conserved.  A reaction lacking reactants or
+// products is not balanced.
+func (rxn *Reaction) BalanceCheck() error {
+	if len(rxn.reactants) == 0 {
+		return fmt.Errorf("Reaction has no reactants.")
+	}
+	if len(rxn.products) == 0 {
+		return fmt.Errorf("Reaction has no products.")
+	}
+
+	rcs, err := elementCounts(rxn.reactants)
+	if err != nil {
+		return err
+	}
+	pcs, err := elementCounts(rxn.products)
+	if err != nil {
+		return err
+	}
+
+	syms := make([]string, 0, len(rcs)+len(pcs))
+	for sym := range rcs {
+		syms = append(syms, sym)
+	}
+	for sym := range pcs {
+		if _, ok := rcs[sym]; !ok {
+			syms = append(syms, sym)
+		}
+	}
+	sort.Strings(syms)
+
+	for _, sym := range syms {
+		if rcs[sym] != pcs[sym] {
+			return fmt.Errorf("Element %s is not balanced : %d in reactants, %d in products.", sym, rcs[sym], pcs[sym])
+		}
+	}
+
+	return nil
+}
+
+// elementCounts answers the total number of atoms of each element in
+// the given molecules, keyed by element symbol.
+func elementCounts(ms []*molecule.Molecule) (map[string]int, error) {
+	counts := make(map[string]int)
+	for _, m := range ms {
+		if err := addFormulaCounts(counts, m.Formula()); err != nil {
+			return nil, err
+		}
+	}
+
+	return counts, nil
+}
+
+// addFormulaCounts parses the given molecular formula, and adds the
+// number of atoms of each of its elements to the given counts.  See
+// `molecule.Molecule.Formula`.
+func addFormulaCounts(counts map[string]int, formula string) error {
+	rs := []rune(formula)
+	for i := 0; i < len(rs); {
+		if !unicode.IsUpper(rs[i]) {
+			return fmt.Errorf("Malformed formula : %s", formula)
+		}
+
+		j := i + 1
+		for j < len(rs) && unicode.IsLower(rs[j]) {
+			j++
+		}
+		sym := string(rs[i:j])
+
+		n := 0
+		for j < len(rs) && unicode.IsDigit(rs[j]) {
+			n = 10*n + int(rs[j]-'0')
+			j++
+		}
+		if n == 0 {
+			n = 1
+		}
+
+		counts[sym] += n
+		i = j
+	}
+
+	return nil
+}
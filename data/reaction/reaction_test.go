@@ -0,0 +1,102 @@
+package reaction
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/RxnWeaver/RxnWeaver/data/molecule"
+)
+
+const methaneMolfile = `methane
+  test
+
+  1  0  0  0  0  0  0  0  0  0999 V2000
+    0.0000    0.0000    0.0000 C   0  0  0  0  0  0  0  0  0  0  0  0
+M  END`
+
+const oxygenMolfile = `oxygen
+  test
+
+  2  1  0  0  0  0  0  0  0  0999 V2000
+    0.0000    0.0000    0.0000 O   0  0  0  0  0  0  0  0  0  0  0  0
+    1.2100    0.0000    0.0000 O   0  0  0  0  0  0  0  0  0  0  0  0
+  1  2  2  0
+M  END`
+
+const carbonDioxideMolfile = `carbon dioxide
+  test
+
+  3  2  0  0  0  0  0  0  0  0999 V2000
+   -1.1600    0.0000    0.0000 O   0  0  0  0  0  0  0  0  0  0  0  0
+    0.0000    0.0000    0.0000 C   0  0  0  0  0  0  0  0  0  0  0  0
+    1.1600    0.0000    0.0000 O   0  0  0  0  0  0  0  0  0  0  0  0
+  1  2  2  0
+  2  3  2  0
+M  END`
+
+const waterMolfile = `water
+  test
+
+  1  0  0  0  0  0  0  0  0  0999 V2000
+    0.0000    0.0000    0.0000 O   0  0  0  0  0  0  0  0  0  0  0  0
+M  END`
+
+// parseMolecule answers the molecule in the given molfile, with its
+// hydrogen atoms filled to the valences of its atoms.
+func parseMolecule(t *testing.T, s string) *molecule.Molecule {
+	m, _, err := molecule.ParseMolfile(strings.Split(s, "\n"), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// combustion answers the combustion of methane, with the given number
+// of oxygen and water molecules.
+func combustion(t *testing.T, nO2, nH2O int) *Reaction {
+	rxn := NewReaction()
+	rxn.AddReactant(parseMolecule(t, methaneMolfile))
+	for i := 0; i < nO2; i++ {
+		rxn.AddReactant(parseMolecule(t, oxygenMolfile))
+	}
+	rxn.AddProduct(parseMolecule(t, carbonDioxideMolfile))
+	for i := 0; i < nH2O; i++ {
+		rxn.AddProduct(parseMolecule(t, waterMolfile))
+	}
+	return rxn
+}
+
+func TestBalanceCheck(t *testing.T) {
+	if err := combustion(t, 2, 2).BalanceCheck(); err != nil {
+		t.Errorf("Expected CH4 + 2 O2 -> CO2 + 2 H2O to balance, found : %v", err)
+	}
+
+	// Hydrogen is reported before oxygen, in alphabetical order.
+	err := combustion(t, 1, 1).BalanceCheck()
+	if err == nil {
+		t.Fatal("Expected CH4 + O2 -> CO2 + H2O not to balance.")
+	}
+	if want := "Element H is not balanced : 4 in reactants, 2 in products."; err.Error() != want {
+		t.Errorf("Expected error %q, found : %q", want, err.Error())
+	}
+
+	err = combustion(t, 1, 2).BalanceCheck()
+	if err == nil {
+		t.Fatal("Expected CH4 + O2 -> CO2 + 2 H2O not to balance.")
+	}
+	if want := "Element O is not balanced : 2 in reactants, 4 in products."; err.Error() != want {
+		t.Errorf("Expected error %q, found : %q", want, err.Error())
+	}
+}
+
+func TestBalanceCheckEmptySide(t *testing.T) {
+	rxn := NewReaction()
+	if err := rxn.BalanceCheck(); err == nil {
+		t.Error("Expected a reaction without reactants not to balance.")
+	}
+
+	rxn.AddReactant(parseMolecule(t, methaneMolfile))
+	if err := rxn.BalanceCheck(); err == nil {
+		t.Error("Expected a reaction without products not to balance.")
+	}
+}
@@ -0,0 +1,147 @@
+package reaction
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	cmn "github.com/RxnWeaver/RxnWeaver/common"
+	"github.com/RxnWeaver/RxnWeaver/data/molecule"
+)
+
+// RXNReader reads a reaction from an MDL V2000 RXN file.
+//
+// An RXN file begins with a header block of four lines: the `$RXN`
+// line, the name of the reaction, a program line and a comment.  A
+// counts line follows, giving the number of reactants and products in
+// columns 1-3 and 4-6 respectively.  Then come the molecules - all the
+// reactants followed by all the products - each as a molfile preceded
+// by a `$MOL` line.
+//
+// The atom-atom mapping numbers in the atom blocks of the molfiles
+// are retained in their atoms.  See `BondChanges`.
+type RXNReader struct {
+	// HydrogenPolicy determines how the hydrogen counts of the atoms
+	// of the molecules are inferred.  By default, atoms are filled to
+	// their valences.
+	HydrogenPolicy cmn.HydrogenPolicy
+
+	r io.Reader
+}
+
+// NewRXNReader answers a new reader of a reaction from the given
+// input.
+func NewRXNReader(r io.Reader) *RXNReader {
+	return &RXNReader{r: r}
+}
+
+// ReadRXN reads the reaction in the given RXN input, using a default
+// `RXNReader`.
+func ReadRXN(r io.Reader) (*Reaction, error) {
+	return NewRXNReader(r).Read()
+}
+
+// Read reads the reaction in the input of this reader.
+func (rr *RXNReader) Read() (*Reaction, error) {
+	lines := make([]string, 0, 128)
+	sc := bufio.NewScanner(rr.r)
+	for sc.Scan() {
+		lines = append(lines, strings.TrimRight(sc.Text(), "\r"))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return parseRXN(lines, rr.HydrogenPolicy)
+}
+
+// parseRXN constructs a new reaction from the given lines of an RXN
+// file.  Hydrogen counts are determined as per the given policy.  See
+// `RXNReader`.
+func parseRXN(lines []string, policy cmn.HydrogenPolicy) (*Reaction, error) {
+	lerr := func(idx int, err error) error {
+		return fmt.Errorf("Line %d : %v", idx+1, err)
+	}
+
+	if len(lines) < 5 {
+		return nil, lerr(len(lines), fmt.Errorf("Incomplete header block."))
+	}
+	if hdr := strings.TrimSpace(lines[0]); hdr != "$RXN" {
+		if strings.HasPrefix(hdr, "$RXN") {
+			return nil, lerr(0, fmt.Errorf("Unsupported RXN version : %s", strings.TrimSpace(hdr[4:])))
+		}
+		return nil, lerr(0, fmt.Errorf("Expected '$RXN', found : %s", hdr))
+	}
+
+	counts := lines[4]
+	nReactants, err := rxnCount(counts, 0, 3)
+	if err != nil {
+		return nil, lerr(4, fmt.Errorf("Invalid reactant count : %v", err))
+	}
+	nProducts, err := rxnCount(counts, 3, 6)
+	if err != nil {
+		return nil, lerr(4, fmt.Errorf("Invalid product count : %v", err))
+	}
+
+	mp := molecule.MolfileParser{HydrogenPolicy: policy}
+	rxn := NewReaction()
+	idx := 5
+	for i := 0; i < nReactants+nProducts; i++ {
+		if idx >= len(lines) {
+			return nil, lerr(idx, fmt.Errorf("Expected %d reactants and %d products; found %d molecules.", nReactants, nProducts, i))
+		}
+		if !strings.HasPrefix(lines[idx], "$MOL") {
+			return nil, lerr(idx, fmt.Errorf("Expected '$MOL', found : %s", lines[idx]))
+		}
+		idx++
+
+		// Line numbers reported by the molfile parser are 1-based.
+		m, n, err := mp.Parse(lines[idx:], idx+1)
+		if err != nil {
+			return nil, err
+		}
+		idx += n
+
+		if i < nReactants {
+			err = rxn.AddReactant(m)
+		} else {
+			err = rxn.AddProduct(m)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for ; idx < len(lines); idx++ {
+		if strings.HasPrefix(lines[idx], "$MOL") {
+			return nil, lerr(idx, fmt.Errorf("Expected %d reactants and %d products; found more molecules.", nReactants, nProducts))
+		}
+		if strings.TrimSpace(lines[idx]) != "" {
+			return nil, lerr(idx, fmt.Errorf("Unexpected content after the last molecule : %s", lines[idx]))
+		}
+	}
+
+	return rxn, nil
+}
+
+// rxnCount answers the non-negative count in the given columns of the
+// given line.  Unlike in molfiles, a blank count is an error.
+func rxnCount(line string, from, to int) (int, error) {
+	if from >= len(line) {
+		return 0, fmt.Errorf("Count is missing.")
+	}
+	if to > len(line) {
+		to = len(line)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(line[from:to]))
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("Negative count : %d", n)
+	}
+	return n, nil
+}